@@ -141,9 +141,11 @@ func drawPreflightTable(analyzeResults []*analyzerunner.AnalyzeResult) {
 		title := analyzeResult.Title
 		if analyzeResult.IsPass {
 			title = fmt.Sprintf("✔  %s", title)
+		} else if analyzeResult.IsSkipped {
+			title = fmt.Sprintf("-  %s", title)
 		} else if analyzeResult.IsWarn {
 			title = fmt.Sprintf("⚠️  %s", title)
-		} else if analyzeResult.IsFail {
+		} else if analyzeResult.IsFail || analyzeResult.IsError {
 			title = fmt.Sprintf("✘  %s", title)
 		}
 		table.Rows = append(table.Rows, []string{
@@ -156,13 +158,19 @@ func drawPreflightTable(analyzeResults []*analyzerunner.AnalyzeResult) {
 			} else {
 				table.RowStyles[i] = ui.NewStyle(ui.ColorGreen, ui.ColorClear)
 			}
+		} else if analyzeResult.IsSkipped {
+			if i == selectedResult {
+				table.RowStyles[i] = ui.NewStyle(ui.ColorWhite, ui.ColorClear, ui.ModifierReverse)
+			} else {
+				table.RowStyles[i] = ui.NewStyle(ui.ColorWhite, ui.ColorClear)
+			}
 		} else if analyzeResult.IsWarn {
 			if i == selectedResult {
 				table.RowStyles[i] = ui.NewStyle(ui.ColorYellow, ui.ColorClear, ui.ModifierReverse)
 			} else {
 				table.RowStyles[i] = ui.NewStyle(ui.ColorYellow, ui.ColorClear)
 			}
-		} else if analyzeResult.IsFail {
+		} else if analyzeResult.IsFail || analyzeResult.IsError {
 			if i == selectedResult {
 				table.RowStyles[i] = ui.NewStyle(ui.ColorRed, ui.ColorClear, ui.ModifierReverse)
 			} else {
@@ -183,9 +191,11 @@ func drawDetails(analysisResult *analyzerunner.AnalyzeResult) {
 	title.Border = false
 	if analysisResult.IsPass {
 		title.TextStyle = ui.NewStyle(ui.ColorGreen, ui.ColorClear, ui.ModifierBold)
+	} else if analysisResult.IsSkipped {
+		title.TextStyle = ui.NewStyle(ui.ColorWhite, ui.ColorClear, ui.ModifierBold)
 	} else if analysisResult.IsWarn {
 		title.TextStyle = ui.NewStyle(ui.ColorYellow, ui.ColorClear, ui.ModifierBold)
-	} else if analysisResult.IsFail {
+	} else if analysisResult.IsFail || analysisResult.IsError {
 		title.TextStyle = ui.NewStyle(ui.ColorRed, ui.ColorClear, ui.ModifierBold)
 	}
 	height := estimateNumberOfLines(title.Text, termWidth/2)
@@ -230,10 +240,14 @@ func save(preflightName string, analyzeResults []*analyzerunner.AnalyzeResult) (
 
 		if analyzeResult.IsPass {
 			result = "Check PASS\n"
+		} else if analyzeResult.IsSkipped {
+			result = "Check SKIPPED\n"
 		} else if analyzeResult.IsWarn {
 			result = "Check WARN\n"
 		} else if analyzeResult.IsFail {
 			result = "Check FAIL\n"
+		} else if analyzeResult.IsError {
+			result = "Check ERROR\n"
 		}
 
 		result = result + fmt.Sprintf("Title: %s\n", analyzeResult.Title)
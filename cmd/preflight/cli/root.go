@@ -22,7 +22,11 @@ that a cluster meets the requirements to run an application.`,
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			v := viper.GetViper()
-			return runPreflights(v, args[0])
+			err := runPreflights(v, args[0])
+			if exitCode, ok := err.(exitCodeError); ok {
+				os.Exit(int(exitCode))
+			}
+			return err
 		},
 	}
 
@@ -30,12 +34,14 @@ that a cluster meets the requirements to run an application.`,
 
 	cmd.AddCommand(VersionCmd())
 
-	cmd.Flags().Bool("interactive", true, "interactive preflights")
-	cmd.Flags().String("format", "human", "output format, one of human, json, yaml. only used when interactive is set to false")
+	cmd.Flags().Bool("interactive", true, "interactive preflights. when false, the exit code is the overall status of the checks: 0 pass, 1 fail, 2 analyzer error. warnings pass unless they are at or above --severity-threshold")
+	cmd.Flags().String("format", "human", "output format, one of human, json, json-v1, yaml, sarif, junit. only used when interactive is set to false")
 	cmd.Flags().String("collector-image", "", "the full name of the collector image to use")
 	cmd.Flags().String("collector-pullpolicy", "", "the pull policy of the collector image")
 	cmd.Flags().Bool("collect-without-permissions", false, "always run preflight checks even if some require permissions that preflight does not have")
 	cmd.Flags().String("since-time", "", "force pod logs collectors to return logs after a specific date (RFC3339)")
+	cmd.Flags().String("severity-threshold", "fail", "the lowest severity that fails the exit code, one of pass, warn or fail. results are still shown with their own severity. only used when interactive is set to false")
+	cmd.Flags().StringSlice("var", []string{}, "a NAME=VALUE substituted for ${NAME} in nodeResources conditionals and messages, e.g. --var MIN_NODES=$MIN_NODES. can be repeated")
 	cmd.Flags().String("since", "", "force pod logs collectors to return logs newer than a relative duration like 5s, 2m, or 3h.")
 
 	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
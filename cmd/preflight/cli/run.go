@@ -1,10 +1,12 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/troubleshoot/cmd/util"
+	analyzerunner "github.com/replicatedhq/troubleshoot/pkg/analyze"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	troubleshootclientsetscheme "github.com/replicatedhq/troubleshoot/pkg/client/troubleshootclientset/scheme"
 	"github.com/replicatedhq/troubleshoot/pkg/docrewrite"
@@ -25,11 +28,19 @@ import (
 )
 
 func runPreflights(v *viper.Viper, arg string) error {
+	severityThreshold, err := preflight.ParseSeverityThreshold(v.GetString("severity-threshold"))
+	if err != nil {
+		return err
+	}
+	values, err := preflight.ParseValues(v.GetStringSlice("var"))
+	if err != nil {
+		return err
+	}
+
 	fmt.Print(cursor.Hide())
 	defer fmt.Print(cursor.Show())
 
 	var preflightContent []byte
-	var err error
 	if strings.HasPrefix(arg, "secret/") {
 		// format secret/namespace-name/secret-name
 		pathParts := strings.Split(arg, "/")
@@ -149,8 +160,22 @@ func runPreflights(v *viper.Viper, arg string) error {
 		}
 		return err
 	}
+	collectResults.Values = values
 
-	analyzeResults := collectResults.Analyze()
+	// interrupting the analysis still shows the results of the analyzers that finished
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+	go func() {
+		select {
+		case <-interrupted:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	analyzeResults := collectResults.AnalyzeContext(ctx)
+	signal.Stop(interrupted)
 	if preflightSpec.Spec.UploadResultsTo != "" {
 		err := uploadResults(preflightSpec.Spec.UploadResultsTo, analyzeResults)
 		if err != nil {
@@ -164,10 +189,37 @@ func runPreflights(v *viper.Viper, arg string) error {
 		if len(analyzeResults) == 0 {
 			return errors.New("no data has been collected")
 		}
-		return showInteractiveResults(preflightSpec.Name, analyzeResults)
+		if err := showInteractiveResults(preflightSpec.Name, analyzeResults); err != nil {
+			return err
+		}
+	} else if err := showStdoutResults(v.GetString("format"), preflightSpec.Name, analyzeResults); err != nil {
+		return err
+	}
+
+	if exitCode := resultsExitCode(analyzeResults, severityThreshold, v.GetBool("interactive")); exitCode != preflight.ExitCodePass {
+		return exitCodeError(exitCode)
 	}
 
-	return showStdoutResults(v.GetString("format"), preflightSpec.Name, analyzeResults)
+	return nil
+}
+
+// resultsExitCode is the process exit code for the results. Interactive runs show the results on screen and exit 0
+// once the checks have run. Otherwise failures and analyzer errors fail the exit code, and warnings only do with a
+// warn or pass severity threshold.
+func resultsExitCode(results []*analyzerunner.AnalyzeResult, threshold preflight.Status, interactive bool) int {
+	if interactive {
+		return preflight.ExitCodePass
+	}
+	_, exitCode := preflight.ReduceResultsWithThreshold(results, threshold)
+	return exitCode
+}
+
+// exitCodeError is returned once the results have been shown, so that the process exits with the exit code for
+// the overall status without printing an error
+type exitCodeError int
+
+func (e exitCodeError) Error() string {
+	return fmt.Sprintf("preflight checks exited with code %d", int(e))
 }
 
 func parseTimeFlags(v *viper.Viper, progressChan chan interface{}, collectors []*troubleshootv1beta2.Collect) error {
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"testing"
+
+	analyzerunner "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	"github.com/replicatedhq/troubleshoot/pkg/preflight"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_resultsExitCode(t *testing.T) {
+	warnings := []*analyzerunner.AnalyzeResult{
+		{IsPass: true},
+		{IsWarn: true},
+	}
+	failures := []*analyzerunner.AnalyzeResult{
+		{IsWarn: true},
+		{IsFail: true},
+	}
+
+	tests := []struct {
+		name        string
+		results     []*analyzerunner.AnalyzeResult
+		threshold   preflight.Status
+		interactive bool
+		expected    int
+	}{
+		{
+			name:      "warnings pass with the default threshold",
+			results:   warnings,
+			threshold: preflight.StatusFail,
+			expected:  0,
+		},
+		{
+			name:      "warnings fail with a warn threshold",
+			results:   warnings,
+			threshold: preflight.StatusWarn,
+			expected:  1,
+		},
+		{
+			name:      "failures fail",
+			results:   failures,
+			threshold: preflight.StatusFail,
+			expected:  1,
+		},
+		{
+			name:      "analyzer errors",
+			results:   []*analyzerunner.AnalyzeResult{{IsFail: true}, {IsError: true}},
+			threshold: preflight.StatusFail,
+			expected:  2,
+		},
+		{
+			name:        "interactive warnings with a warn threshold",
+			results:     warnings,
+			threshold:   preflight.StatusWarn,
+			interactive: true,
+			expected:    0,
+		},
+		{
+			name:        "interactive failures",
+			results:     failures,
+			threshold:   preflight.StatusFail,
+			interactive: true,
+			expected:    0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, resultsExitCode(test.results, test.threshold, test.interactive))
+		})
+	}
+}
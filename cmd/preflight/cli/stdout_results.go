@@ -6,6 +6,7 @@ import (
 
 	"github.com/pkg/errors"
 	analyzerunner "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	"github.com/replicatedhq/troubleshoot/pkg/convert"
 )
 
 func showStdoutResults(format string, preflightName string, analyzeResults []*analyzerunner.AnalyzeResult) error {
@@ -13,6 +14,12 @@ func showStdoutResults(format string, preflightName string, analyzeResults []*an
 		return showStdoutResultsHuman(preflightName, analyzeResults)
 	} else if format == "json" {
 		return showStdoutResultsJSON(preflightName, analyzeResults)
+	} else if format == "sarif" {
+		return showStdoutResultsSARIF(analyzeResults)
+	} else if format == "junit" {
+		return showStdoutResultsJUnit(analyzeResults)
+	} else if format == "json-v1" {
+		return showStdoutResultsJSONV1(analyzeResults)
 	}
 
 	return errors.Errorf("unknown output format: %q", format)
@@ -43,15 +50,19 @@ func showStdoutResultsJSON(preflightName string, analyzeResults []*analyzerunner
 		URI     string `json:"uri,omitempty"`
 	}
 	type Output struct {
-		Pass []ResultOutput `json:"pass,omitempty"`
-		Warn []ResultOutput `json:"warn,omitempty"`
-		Fail []ResultOutput `json:"fail,omitempty"`
+		Pass    []ResultOutput `json:"pass,omitempty"`
+		Warn    []ResultOutput `json:"warn,omitempty"`
+		Fail    []ResultOutput `json:"fail,omitempty"`
+		Error   []ResultOutput `json:"error,omitempty"`
+		Skipped []ResultOutput `json:"skipped,omitempty"`
 	}
 
 	output := Output{
-		Pass: []ResultOutput{},
-		Warn: []ResultOutput{},
-		Fail: []ResultOutput{},
+		Pass:    []ResultOutput{},
+		Warn:    []ResultOutput{},
+		Fail:    []ResultOutput{},
+		Error:   []ResultOutput{},
+		Skipped: []ResultOutput{},
 	}
 
 	for _, analyzeResult := range analyzeResults {
@@ -63,10 +74,16 @@ func showStdoutResultsJSON(preflightName string, analyzeResults []*analyzerunner
 
 		if analyzeResult.IsPass {
 			output.Pass = append(output.Pass, resultOutput)
+		} else if analyzeResult.IsSkipped {
+			output.Skipped = append(output.Skipped, resultOutput)
 		} else if analyzeResult.IsWarn {
 			output.Warn = append(output.Warn, resultOutput)
 		} else if analyzeResult.IsFail {
 			output.Fail = append(output.Fail, resultOutput)
+		} else if analyzeResult.IsError {
+			// errors were reported as failures before they had their own list, so they are still listed under fail
+			output.Fail = append(output.Fail, resultOutput)
+			output.Error = append(output.Error, resultOutput)
 		}
 	}
 
@@ -80,10 +97,48 @@ func showStdoutResultsJSON(preflightName string, analyzeResults []*analyzerunner
 	return nil
 }
 
+func showStdoutResultsSARIF(analyzeResults []*analyzerunner.AnalyzeResult) error {
+	b, err := convert.ToSARIF(analyzeResults)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert results to sarif")
+	}
+
+	fmt.Printf("%s\n", b)
+
+	return nil
+}
+
+func showStdoutResultsJUnit(analyzeResults []*analyzerunner.AnalyzeResult) error {
+	b, err := convert.ToJUnit(analyzeResults)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert results to junit")
+	}
+
+	fmt.Printf("%s\n", b)
+
+	return nil
+}
+
+// showStdoutResultsJSONV1 writes the versioned results document, which unlike the json format includes every
+// field of the results and a summary
+func showStdoutResultsJSONV1(analyzeResults []*analyzerunner.AnalyzeResult) error {
+	b, err := convert.MarshalResults(analyzeResults)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", b)
+
+	return nil
+}
+
 func outputResult(analyzeResult *analyzerunner.AnalyzeResult) bool {
 	if analyzeResult.IsPass {
 		fmt.Printf("   --- PASS %s\n", analyzeResult.Title)
 		fmt.Printf("      --- %s\n", analyzeResult.Message)
+	} else if analyzeResult.IsSkipped {
+		fmt.Printf("   --- SKIPPED: %s\n", analyzeResult.Title)
+		fmt.Printf("      --- %s\n", analyzeResult.Message)
 	} else if analyzeResult.IsWarn {
 		fmt.Printf("   --- WARN: %s\n", analyzeResult.Title)
 		fmt.Printf("      --- %s\n", analyzeResult.Message)
@@ -91,6 +146,10 @@ func outputResult(analyzeResult *analyzerunner.AnalyzeResult) bool {
 		fmt.Printf("   --- FAIL: %s\n", analyzeResult.Title)
 		fmt.Printf("      --- %s\n", analyzeResult.Message)
 		return true
+	} else if analyzeResult.IsError {
+		fmt.Printf("   --- ERROR: %s\n", analyzeResult.Title)
+		fmt.Printf("      --- %s\n", analyzeResult.Message)
+		return true
 	}
 	return false
 }
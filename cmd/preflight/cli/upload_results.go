@@ -20,6 +20,7 @@ func uploadResults(uri string, analyzeResults []*analyzerunner.AnalyzeResult) er
 			IsFail:  analyzeResult.IsFail,
 			IsWarn:  analyzeResult.IsWarn,
 			IsPass:  analyzeResult.IsPass,
+			IsError: analyzeResult.IsError,
 			Title:   analyzeResult.Title,
 			Message: analyzeResult.Message,
 			URI:     analyzeResult.URI,
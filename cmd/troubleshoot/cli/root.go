@@ -37,7 +37,8 @@ from a server that can be used to assist when troubleshooting a Kubernetes clust
 	cmd.AddCommand(VersionCmd())
 
 	cmd.Flags().StringSlice("redactors", []string{}, "names of the additional redactors to use")
-	cmd.Flags().Bool("redact", true, "enable/disable default redactions")
+	cmd.Flags().Bool("redact", true, "enable/disable default redactions. When disabled, only the additional redactors run and anything they do not match is left in the bundle")
+	cmd.Flags().Bool("redact-cloud-credentials", true, "enable/disable the built-in AWS, GCP and Azure credential redactions")
 	cmd.Flags().Bool("collect-without-permissions", false, "always generate a support bundle, even if it some require additional permissions")
 	cmd.Flags().String("since-time", "", "force pod logs collectors to return logs after a specific date (RFC3339)")
 	cmd.Flags().String("since", "", "force pod logs collectors to return logs newer than a relative duration like 5s, 2m, or 3h.")
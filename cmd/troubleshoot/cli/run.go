@@ -147,7 +147,8 @@ func runTroubleshoot(v *viper.Viper, arg string) error {
 		}
 	}()
 
-	archivePath, err := runCollectors(v, supportBundleSpec.Spec.Collectors, additionalRedactors, progressChan)
+	redactionRecorder := redact.NewRedactionRecorder()
+	archivePath, err := runCollectors(v, supportBundleSpec.Spec.Collectors, additionalRedactors, redactionRecorder, progressChan)
 	if err != nil {
 		return errors.Wrap(err, "run collectors")
 	}
@@ -223,6 +224,9 @@ func runTroubleshoot(v *viper.Viper, arg string) error {
 		}
 	}
 
+	// stdout is kept for the bundle path and analysis that scripts parse
+	fmt.Fprintf(os.Stderr, "%s\n", redactionRecorder.Summary())
+
 	if !fileUploaded {
 		msg := archivePath
 		if appName := supportBundleSpec.Labels["applicationName"]; appName != "" {
@@ -375,7 +379,7 @@ func canTryInsecure() bool {
 	return true
 }
 
-func runCollectors(v *viper.Viper, collectors []*troubleshootv1beta2.Collect, additionalRedactors *troubleshootv1beta2.Redactor, progressChan chan interface{}) (string, error) {
+func runCollectors(v *viper.Viper, collectors []*troubleshootv1beta2.Collect, additionalRedactors *troubleshootv1beta2.Redactor, redactionRecorder *redact.RedactionRecorder, progressChan chan interface{}) (string, error) {
 	tmpDir, err := ioutil.TempDir("", "troubleshoot")
 	if err != nil {
 		return "", errors.Wrap(err, "create temp dir")
@@ -409,7 +413,14 @@ func runCollectors(v *viper.Viper, collectors []*troubleshootv1beta2.Collect, ad
 	var cleanedCollectors collect.Collectors
 	for _, desiredCollector := range collectSpecs {
 		collector := collect.Collector{
-			Redact:       true,
+			Redact: true,
+			RedactOptions: collect.RedactOptions{
+				Options: redact.Options{
+					DisableDefaultRedactors:         !v.GetBool("redact"),
+					DisableCloudCredentialRedactors: !v.GetBool("redact-cloud-credentials"),
+					Recorder:                        redactionRecorder,
+				},
+			},
 			Collect:      desiredCollector,
 			ClientConfig: config,
 			Namespace:    v.GetString("namespace"),
@@ -21,6 +21,7 @@ require (
 	github.com/hashicorp/go-getter v1.3.1-0.20190627223108-da0323b9545e
 	github.com/hashicorp/go-multierror v1.0.0
 	github.com/imdario/mergo v0.3.8 // indirect
+	github.com/klauspost/compress v1.10.10
 	github.com/lib/pq v1.3.0
 	github.com/manifoldco/promptui v0.3.2
 	github.com/mattn/go-colorable v0.1.4 // indirect
@@ -1,7 +1,9 @@
 package analyzer
 
 import (
+	"context"
 	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
@@ -13,11 +15,24 @@ type AnalyzeResult struct {
 	IsFail bool
 	IsWarn bool
 
+	// IsError is set instead of IsFail when the analyzer itself could not run, so that crashes can be told apart
+	// from failing checks
+	IsError bool
+
+	// IsSkipped is set when the analyzer chose not to evaluate its outcomes, such as nodeResources for a workload
+	// that already exists without onUpdate outcomes. IsWarn is still set on skipped results for now, so that
+	// callers that do not know about IsSkipped keep showing them.
+	IsSkipped bool
+
 	Title   string
 	Message string
 	URI     string
 	IconKey string
 	IconURI string
+
+	// AnalyzerKind is the kind of analyzer in the spec that produced the result, such as clusterVersion, so that
+	// results can be grouped by analyzer
+	AnalyzerKind string
 }
 
 type getCollectedFileContents func(string) ([]byte, error)
@@ -41,6 +56,31 @@ func isExcluded(excludeVal multitype.BoolOrString) (bool, error) {
 }
 
 func Analyze(analyzer *troubleshootv1beta2.Analyze, getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	return AnalyzeWithCache(context.Background(), analyzer, getFile, findFiles, NewCollectedObjectCache(getFile))
+}
+
+// AnalyzeWithCache is like Analyze, but parsed collected files are read from cache so that they can be shared
+// between analyzers. Analyzers that loop over many nodes or files, such as nodeResources and textAnalyze, stop and
+// return the error of ctx once it is done.
+func AnalyzeWithCache(ctx context.Context, analyzer *troubleshootv1beta2.Analyze, getFile getCollectedFileContents, findFiles getChildCollectedFileContents, cache *CollectedObjectCache) ([]*AnalyzeResult, error) {
+	results, err := analyzeWithCache(ctx, analyzer, getFile, findFiles, cache)
+	if err != nil {
+		return nil, err
+	}
+
+	kind, _ := AnalyzerKindAndMeta(analyzer)
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		result.AnalyzerKind = kind
+		setDefaultAnalyzerIcon(result, kind)
+	}
+
+	return results, nil
+}
+
+func analyzeWithCache(ctx context.Context, analyzer *troubleshootv1beta2.Analyze, getFile getCollectedFileContents, findFiles getChildCollectedFileContents, cache *CollectedObjectCache) ([]*AnalyzeResult, error) {
 	if analyzer.ClusterVersion != nil {
 		isExcluded, err := isExcluded(analyzer.ClusterVersion.Exclude)
 		if err != nil {
@@ -189,7 +229,20 @@ func Analyze(analyzer *troubleshootv1beta2.Analyze, getFile getCollectedFileCont
 		if isExcluded {
 			return nil, nil
 		}
-		result, err := analyzeNodeResources(analyzer.NodeResources, getFile)
+		if analyzer.NodeResources.PerNode {
+			return analyzeNodeResourcesPerNode(ctx, analyzer.NodeResources, getFile, cache.GetCollectedObject)
+		}
+		return analyzeNodeResources(ctx, analyzer.NodeResources, getFile, cache.GetCollectedObject)
+	}
+	if analyzer.NodeOS != nil {
+		isExcluded, err := isExcluded(analyzer.NodeOS.Exclude)
+		if err != nil {
+			return nil, err
+		}
+		if isExcluded {
+			return nil, nil
+		}
+		result, err := analyzeNodeOS(analyzer.NodeOS, cache.GetCollectedObject)
 		if err != nil {
 			return nil, err
 		}
@@ -203,7 +256,7 @@ func Analyze(analyzer *troubleshootv1beta2.Analyze, getFile getCollectedFileCont
 		if isExcluded {
 			return nil, nil
 		}
-		multiResult, err := analyzeTextAnalyze(analyzer.TextAnalyze, findFiles)
+		multiResult, err := analyzeTextAnalyze(ctx, analyzer.TextAnalyze, findFiles)
 		if err != nil {
 			return nil, err
 		}
@@ -265,6 +318,191 @@ func Analyze(analyzer *troubleshootv1beta2.Analyze, getFile getCollectedFileCont
 		}
 		return []*AnalyzeResult{result}, nil
 	}
+	if analyzer.Sysctl != nil {
+		isExcluded, err := isExcluded(analyzer.Sysctl.Exclude)
+		if err != nil {
+			return nil, err
+		}
+		if isExcluded {
+			return nil, nil
+		}
+		result, err := analyzeSysctl(analyzer.Sysctl, findFiles)
+		if err != nil {
+			return nil, err
+		}
+		return []*AnalyzeResult{result}, nil
+	}
+	if analyzer.Events != nil {
+		isExcluded, err := isExcluded(analyzer.Events.Exclude)
+		if err != nil {
+			return nil, err
+		}
+		if isExcluded {
+			return nil, nil
+		}
+		result, err := analyzeEvents(analyzer.Events, getFile, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return []*AnalyzeResult{result}, nil
+	}
+	if analyzer.CompareField != nil {
+		isExcluded, err := isExcluded(analyzer.CompareField.Exclude)
+		if err != nil {
+			return nil, err
+		}
+		if isExcluded {
+			return nil, nil
+		}
+		result, err := analyzeCompareField(analyzer.CompareField, getFile)
+		if err != nil {
+			return nil, err
+		}
+		return []*AnalyzeResult{result}, nil
+	}
+	if analyzer.KubeletMaxPods != nil {
+		isExcluded, err := isExcluded(analyzer.KubeletMaxPods.Exclude)
+		if err != nil {
+			return nil, err
+		}
+		if isExcluded {
+			return nil, nil
+		}
+		result, err := analyzeKubeletMaxPods(analyzer.KubeletMaxPods, cache.GetCollectedObject, findFiles)
+		if err != nil {
+			return nil, err
+		}
+		return []*AnalyzeResult{result}, nil
+	}
+	if analyzer.Certificates != nil {
+		isExcluded, err := isExcluded(analyzer.Certificates.Exclude)
+		if err != nil {
+			return nil, err
+		}
+		if isExcluded {
+			return nil, nil
+		}
+		result, err := analyzeCertificates(analyzer.Certificates, getFile, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return []*AnalyzeResult{result}, nil
+	}
+	if analyzer.APIServerHealth != nil {
+		isExcluded, err := isExcluded(analyzer.APIServerHealth.Exclude)
+		if err != nil {
+			return nil, err
+		}
+		if isExcluded {
+			return nil, nil
+		}
+		result, err := analyzeAPIServerHealth(analyzer.APIServerHealth, getFile)
+		if err != nil {
+			return nil, err
+		}
+		return []*AnalyzeResult{result}, nil
+	}
+	if analyzer.PersistentVolumes != nil {
+		isExcluded, err := isExcluded(analyzer.PersistentVolumes.Exclude)
+		if err != nil {
+			return nil, err
+		}
+		if isExcluded {
+			return nil, nil
+		}
+		result, err := analyzePersistentVolumes(analyzer.PersistentVolumes, getFile)
+		if err != nil {
+			return nil, err
+		}
+		return []*AnalyzeResult{result}, nil
+	}
+	if analyzer.IngressHosts != nil {
+		isExcluded, err := isExcluded(analyzer.IngressHosts.Exclude)
+		if err != nil {
+			return nil, err
+		}
+		if isExcluded {
+			return nil, nil
+		}
+		result, err := analyzeIngressHosts(analyzer.IngressHosts, getFile)
+		if err != nil {
+			return nil, err
+		}
+		return []*AnalyzeResult{result}, nil
+	}
+	if analyzer.NodeCgroups != nil {
+		isExcluded, err := isExcluded(analyzer.NodeCgroups.Exclude)
+		if err != nil {
+			return nil, err
+		}
+		if isExcluded {
+			return nil, nil
+		}
+		result, err := analyzeNodeCgroups(analyzer.NodeCgroups, cache.GetCollectedObject, findFiles)
+		if err != nil {
+			return nil, err
+		}
+		return []*AnalyzeResult{result}, nil
+	}
 	return nil, errors.New("invalid analyzer")
 
 }
+
+// AnalyzerKindAndMeta returns the kind of the analyzer, as it is named in specs, and its metadata
+func AnalyzerKindAndMeta(analyzer *troubleshootv1beta2.Analyze) (string, troubleshootv1beta2.AnalyzeMeta) {
+	switch {
+	case analyzer.ClusterVersion != nil:
+		return "clusterVersion", analyzer.ClusterVersion.AnalyzeMeta
+	case analyzer.StorageClass != nil:
+		return "storageClass", analyzer.StorageClass.AnalyzeMeta
+	case analyzer.CustomResourceDefinition != nil:
+		return "customResourceDefinition", analyzer.CustomResourceDefinition.AnalyzeMeta
+	case analyzer.Ingress != nil:
+		return "ingress", analyzer.Ingress.AnalyzeMeta
+	case analyzer.Secret != nil:
+		return "secret", analyzer.Secret.AnalyzeMeta
+	case analyzer.ImagePullSecret != nil:
+		return "imagePullSecret", analyzer.ImagePullSecret.AnalyzeMeta
+	case analyzer.DeploymentStatus != nil:
+		return "deploymentStatus", analyzer.DeploymentStatus.AnalyzeMeta
+	case analyzer.StatefulsetStatus != nil:
+		return "statefulsetStatus", analyzer.StatefulsetStatus.AnalyzeMeta
+	case analyzer.ContainerRuntime != nil:
+		return "containerRuntime", analyzer.ContainerRuntime.AnalyzeMeta
+	case analyzer.Distribution != nil:
+		return "distribution", analyzer.Distribution.AnalyzeMeta
+	case analyzer.NodeResources != nil:
+		return "nodeResources", analyzer.NodeResources.AnalyzeMeta
+	case analyzer.NodeOS != nil:
+		return "nodeOS", analyzer.NodeOS.AnalyzeMeta
+	case analyzer.TextAnalyze != nil:
+		return "textAnalyze", analyzer.TextAnalyze.AnalyzeMeta
+	case analyzer.Postgres != nil:
+		return "postgres", analyzer.Postgres.AnalyzeMeta
+	case analyzer.Mysql != nil:
+		return "mysql", analyzer.Mysql.AnalyzeMeta
+	case analyzer.Redis != nil:
+		return "redis", analyzer.Redis.AnalyzeMeta
+	case analyzer.CephStatus != nil:
+		return "cephStatus", analyzer.CephStatus.AnalyzeMeta
+	case analyzer.Sysctl != nil:
+		return "sysctl", analyzer.Sysctl.AnalyzeMeta
+	case analyzer.Events != nil:
+		return "events", analyzer.Events.AnalyzeMeta
+	case analyzer.CompareField != nil:
+		return "compareField", analyzer.CompareField.AnalyzeMeta
+	case analyzer.KubeletMaxPods != nil:
+		return "kubeletMaxPods", analyzer.KubeletMaxPods.AnalyzeMeta
+	case analyzer.Certificates != nil:
+		return "certificates", analyzer.Certificates.AnalyzeMeta
+	case analyzer.APIServerHealth != nil:
+		return "apiServerHealth", analyzer.APIServerHealth.AnalyzeMeta
+	case analyzer.PersistentVolumes != nil:
+		return "persistentVolumes", analyzer.PersistentVolumes.AnalyzeMeta
+	case analyzer.IngressHosts != nil:
+		return "ingressHosts", analyzer.IngressHosts.AnalyzeMeta
+	case analyzer.NodeCgroups != nil:
+		return "nodeCgroups", analyzer.NodeCgroups.AnalyzeMeta
+	}
+	return "unknown", troubleshootv1beta2.AnalyzeMeta{}
+}
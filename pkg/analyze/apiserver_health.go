@@ -0,0 +1,140 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+type apiServerHealthMessageData struct {
+	// FailedChecks are the failed subchecks as endpoint/check, e.g. readyz/etcd, or the endpoint itself when it
+	// could not be reached
+	FailedChecks string
+}
+
+// analyzeAPIServerHealth fails when any subcheck of the API server's /readyz or /livez endpoint is not ok, or when
+// an endpoint could not be reached
+func analyzeAPIServerHealth(analyzer *troubleshootv1beta2.APIServerHealthAnalyze, getFile getCollectedFileContents) (*AnalyzeResult, error) {
+	contents, err := getFile("apiserver-health/request.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected api server health")
+	}
+
+	request := collect.APIServerHealthRequest{}
+	if err := json.Unmarshal(contents, &request); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal collected api server health")
+	}
+
+	failedChecks := []string{}
+	for _, check := range request.Checks {
+		endpoint := strings.TrimPrefix(check.Path, "/")
+		body, err := getFile(path.Join("apiserver-health", endpoint+".txt"))
+		if err != nil || len(body) == 0 {
+			failedChecks = append(failedChecks, endpoint)
+			continue
+		}
+
+		failed := failedAPIServerHealthChecks(body)
+		for _, name := range failed {
+			failedChecks = append(failedChecks, endpoint+"/"+name)
+		}
+		if len(failed) == 0 && check.StatusCode != 200 {
+			failedChecks = append(failedChecks, endpoint)
+		}
+	}
+
+	title := analyzer.CheckName
+	if title == "" {
+		title = "API Server Health"
+	}
+	result := &AnalyzeResult{
+		Title: title,
+	}
+
+	var failOutcome, passOutcome *troubleshootv1beta2.SingleOutcome
+	for _, outcome := range analyzer.Outcomes {
+		if outcome.Fail != nil && failOutcome == nil {
+			failOutcome = outcome.Fail
+		}
+		if outcome.Pass != nil && passOutcome == nil {
+			passOutcome = outcome.Pass
+		}
+	}
+
+	data := apiServerHealthMessageData{
+		FailedChecks: strings.Join(failedChecks, ", "),
+	}
+	single := passOutcome
+	if len(failedChecks) > 0 {
+		result.IsFail = true
+		single = failOutcome
+	} else {
+		result.IsPass = true
+	}
+
+	message := ""
+	if single != nil {
+		message = single.Message
+		result.URI = single.URI
+	}
+	result.Message, err = renderAPIServerHealthMessage(message, data, result.IsFail)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render message")
+	}
+
+	return result, nil
+}
+
+// failedAPIServerHealthChecks returns the names of the subchecks that verbose health output reports as failed, e.g.
+// etcd from "[-]etcd failed: reason withheld"
+func failedAPIServerHealthChecks(body []byte) []string {
+	failed := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "[-]") {
+			continue
+		}
+		name := strings.TrimPrefix(line, "[-]")
+		if i := strings.Index(name, " "); i != -1 {
+			name = name[:i]
+		}
+		failed = append(failed, name)
+	}
+	return failed
+}
+
+// renderAPIServerHealthMessage renders the outcome message as a template. Fail outcomes without a message get a
+// default one.
+func renderAPIServerHealthMessage(message string, data apiServerHealthMessageData, isProblem bool) (string, error) {
+	if message == "" {
+		if !isProblem {
+			return "", nil
+		}
+		return fmt.Sprintf("API server health checks failed: %s", data.FailedChecks), nil
+	}
+
+	if !strings.Contains(message, "{{") {
+		return message, nil
+	}
+
+	tmpl, err := template.New("message").Parse(message)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse message template")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", errors.Wrap(err, "failed to execute message template")
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+)
+
+func Test_analyzeAPIServerHealth(t *testing.T) {
+	request := []byte(`{"host": "https://10.96.0.1:443", "bearerToken": "***HIDDEN***", "checks": [{"path": "/readyz", "statusCode": 200}, {"path": "/livez", "statusCode": 200}]}`)
+	healthy := []byte("[+]ping ok\n[+]log ok\n[+]etcd ok\nreadyz check passed\n")
+
+	outcomes := []*troubleshootv1beta2.Outcome{
+		{
+			Fail: &troubleshootv1beta2.SingleOutcome{
+				URI: "https://kubernetes.io/docs/reference/using-api/health-checks/",
+			},
+		},
+		{
+			Pass: &troubleshootv1beta2.SingleOutcome{
+				Message: "The API server is healthy",
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		files    map[string][]byte
+		expected *AnalyzeResult
+	}{
+		{
+			name: "healthy",
+			files: map[string][]byte{
+				"apiserver-health/request.json": request,
+				"apiserver-health/readyz.txt":   healthy,
+				"apiserver-health/livez.txt":    healthy,
+			},
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "API Server Health",
+				Message: "The API server is healthy",
+			},
+		},
+		{
+			name: "failed subcheck",
+			files: map[string][]byte{
+				"apiserver-health/request.json": []byte(`{"checks": [{"path": "/readyz", "statusCode": 500}, {"path": "/livez", "statusCode": 200}]}`),
+				"apiserver-health/readyz.txt":   []byte("[+]ping ok\n[-]etcd failed: reason withheld\n[-]poststarthook/rbac/bootstrap-roles failed: not finished\nreadyz check failed\n"),
+				"apiserver-health/livez.txt":    healthy,
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "API Server Health",
+				Message: "API server health checks failed: readyz/etcd, readyz/poststarthook/rbac/bootstrap-roles",
+				URI:     "https://kubernetes.io/docs/reference/using-api/health-checks/",
+			},
+		},
+		{
+			name: "unreachable endpoint",
+			files: map[string][]byte{
+				"apiserver-health/request.json": []byte(`{"checks": [{"path": "/readyz", "statusCode": 200}, {"path": "/livez", "error": "context deadline exceeded"}]}`),
+				"apiserver-health/readyz.txt":   healthy,
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "API Server Health",
+				Message: "API server health checks failed: livez",
+				URI:     "https://kubernetes.io/docs/reference/using-api/health-checks/",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+			req := require.New(t)
+
+			getFile := func(fileName string) ([]byte, error) {
+				contents, ok := test.files[fileName]
+				if !ok {
+					return nil, errors.Errorf("%s not found", fileName)
+				}
+				return contents, nil
+			}
+
+			analyzer := &troubleshootv1beta2.APIServerHealthAnalyze{
+				Outcomes: outcomes,
+			}
+			actual, err := analyzeAPIServerHealth(analyzer, getFile)
+			req.NoError(err)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -95,12 +95,14 @@ func cephStatus(analyzer *troubleshootv1beta2.CephStatusAnalyze, getCollectedFil
 		analyzer.Outcomes = CephStatusDefaultOutcomes
 	}
 
+	// the outcomes may be the shared defaults, so the default when clauses are not written back to them
 	for _, outcome := range analyzer.Outcomes {
 		if outcome.Fail != nil {
-			if outcome.Fail.When == "" {
-				outcome.Fail.When = string(CephHealthErr)
+			when := outcome.Fail.When
+			if when == "" {
+				when = string(CephHealthErr)
 			}
-			match, err := compareCephStatus(status.Health.Status, outcome.Fail.When)
+			match, err := compareCephStatus(status.Health.Status, when)
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to compare ceph status")
 			} else if match {
@@ -110,10 +112,11 @@ func cephStatus(analyzer *troubleshootv1beta2.CephStatusAnalyze, getCollectedFil
 				return analyzeResult, nil
 			}
 		} else if outcome.Warn != nil {
-			if outcome.Warn.When == "" {
-				outcome.Warn.When = string(CephHealthWarn)
+			when := outcome.Warn.When
+			if when == "" {
+				when = string(CephHealthWarn)
 			}
-			match, err := compareCephStatus(status.Health.Status, outcome.Warn.When)
+			match, err := compareCephStatus(status.Health.Status, when)
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to compare ceph status")
 			} else if match {
@@ -123,10 +126,11 @@ func cephStatus(analyzer *troubleshootv1beta2.CephStatusAnalyze, getCollectedFil
 				return analyzeResult, nil
 			}
 		} else if outcome.Pass != nil {
-			if outcome.Pass.When == "" {
-				outcome.Pass.When = string(CephHealthOK)
+			when := outcome.Pass.When
+			if when == "" {
+				when = string(CephHealthOK)
 			}
-			match, err := compareCephStatus(status.Health.Status, outcome.Pass.When)
+			match, err := compareCephStatus(status.Health.Status, when)
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to compare ceph status")
 			} else if match {
@@ -0,0 +1,132 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+type certificatesMessageData struct {
+	Namespace  string
+	SecretName string
+	Subject    string
+	NotAfter   string
+	// ExpiresIn is the time left until the certificate expires, and is negative once it has expired
+	ExpiresIn string
+	Count     int
+}
+
+// analyzeCertificates fails when any certificate collected by the certificates collector expires within the
+// threshold of now, e.g. "720h" for 30 days. The message describes the certificate that expires first.
+func analyzeCertificates(analyzer *troubleshootv1beta2.CertificatesAnalyze, getFile getCollectedFileContents, now time.Time) (*AnalyzeResult, error) {
+	threshold, err := time.ParseDuration(analyzer.Threshold)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse threshold %q", analyzer.Threshold)
+	}
+
+	contents, err := getFile("certificates/certificates.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected certificates")
+	}
+
+	certificates := []collect.CertificateInfo{}
+	if err := json.Unmarshal(contents, &certificates); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal collected certificates")
+	}
+
+	expiring := []collect.CertificateInfo{}
+	for _, certificate := range certificates {
+		if certificate.NotAfter.Sub(now) < threshold {
+			expiring = append(expiring, certificate)
+		}
+	}
+	sort.SliceStable(expiring, func(i, j int) bool {
+		return expiring[i].NotAfter.Before(expiring[j].NotAfter)
+	})
+
+	title := analyzer.CheckName
+	if title == "" {
+		title = "Certificate Expiry"
+	}
+	result := &AnalyzeResult{
+		Title: title,
+	}
+
+	var failOutcome, passOutcome *troubleshootv1beta2.SingleOutcome
+	for _, outcome := range analyzer.Outcomes {
+		if outcome.Fail != nil && failOutcome == nil {
+			failOutcome = outcome.Fail
+		}
+		if outcome.Pass != nil && passOutcome == nil {
+			passOutcome = outcome.Pass
+		}
+	}
+
+	data := certificatesMessageData{
+		Count: len(expiring),
+	}
+	single := passOutcome
+	if len(expiring) > 0 {
+		result.IsFail = true
+		single = failOutcome
+
+		first := expiring[0]
+		data.Namespace = first.Namespace
+		data.SecretName = first.SecretName
+		data.Subject = first.Subject
+		data.NotAfter = first.NotAfter.UTC().Format(time.RFC3339)
+		data.ExpiresIn = first.NotAfter.Sub(now).Round(time.Second).String()
+	} else {
+		result.IsPass = true
+	}
+
+	message := ""
+	if single != nil {
+		message = single.Message
+		result.URI = single.URI
+	}
+	result.Message, err = renderCertificatesMessage(message, data, result.IsFail)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render message")
+	}
+
+	return result, nil
+}
+
+// renderCertificatesMessage renders the outcome message as a template. Fail outcomes without a message get a
+// default one.
+func renderCertificatesMessage(message string, data certificatesMessageData, isProblem bool) (string, error) {
+	if message == "" {
+		if !isProblem {
+			return "", nil
+		}
+		if strings.HasPrefix(data.ExpiresIn, "-") {
+			return fmt.Sprintf("Certificate %s in secret %s/%s expired %s ago", data.Subject, data.Namespace, data.SecretName, strings.TrimPrefix(data.ExpiresIn, "-")), nil
+		}
+		return fmt.Sprintf("Certificate %s in secret %s/%s expires in %s", data.Subject, data.Namespace, data.SecretName, data.ExpiresIn), nil
+	}
+
+	if !strings.Contains(message, "{{") {
+		return message, nil
+	}
+
+	tmpl, err := template.New("message").Parse(message)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse message template")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", errors.Wrap(err, "failed to execute message template")
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+)
+
+func Test_analyzeCertificates(t *testing.T) {
+	now := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	certificate := func(name string, notAfter time.Time) collect.CertificateInfo {
+		return collect.CertificateInfo{
+			Namespace:  "default",
+			SecretName: name,
+			Subject:    "CN=" + name,
+			NotAfter:   notAfter,
+		}
+	}
+
+	outcomes := []*troubleshootv1beta2.Outcome{
+		{
+			Fail: &troubleshootv1beta2.SingleOutcome{
+				URI: "https://kubernetes.io/docs/tasks/tls/managing-tls-in-a-cluster/",
+			},
+		},
+		{
+			Pass: &troubleshootv1beta2.SingleOutcome{
+				Message: "No certificates expire within 30 days",
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		certificates []collect.CertificateInfo
+		outcomes     []*troubleshootv1beta2.Outcome
+		expected     *AnalyzeResult
+	}{
+		{
+			name: "none expiring",
+			certificates: []collect.CertificateInfo{
+				certificate("ingress-tls", now.AddDate(0, 6, 0)),
+			},
+			outcomes: outcomes,
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "Certificate Expiry",
+				Message: "No certificates expire within 30 days",
+			},
+		},
+		{
+			name: "expiring soon",
+			certificates: []collect.CertificateInfo{
+				certificate("ingress-tls", now.AddDate(0, 6, 0)),
+				certificate("webhook-tls", now.Add(72*time.Hour)),
+				certificate("api-tls", now.Add(240*time.Hour)),
+			},
+			outcomes: outcomes,
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Certificate Expiry",
+				Message: "Certificate CN=webhook-tls in secret default/webhook-tls expires in 72h0m0s",
+				URI:     "https://kubernetes.io/docs/tasks/tls/managing-tls-in-a-cluster/",
+			},
+		},
+		{
+			name: "expired",
+			certificates: []collect.CertificateInfo{
+				certificate("webhook-tls", now.Add(-time.Hour)),
+			},
+			outcomes: outcomes,
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Certificate Expiry",
+				Message: "Certificate CN=webhook-tls in secret default/webhook-tls expired 1h0m0s ago",
+				URI:     "https://kubernetes.io/docs/tasks/tls/managing-tls-in-a-cluster/",
+			},
+		},
+		{
+			name: "templated message",
+			certificates: []collect.CertificateInfo{
+				certificate("webhook-tls", now.Add(72*time.Hour)),
+				certificate("api-tls", now.Add(240*time.Hour)),
+			},
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{
+					Fail: &troubleshootv1beta2.SingleOutcome{
+						Message: "{{ .Count }} certificates expire soon, first {{ .SecretName }} on {{ .NotAfter }}",
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Certificate Expiry",
+				Message: "2 certificates expire soon, first webhook-tls on 2021-03-04T00:00:00Z",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+			req := require.New(t)
+
+			contents, err := json.Marshal(test.certificates)
+			req.NoError(err)
+			getFile := func(string) ([]byte, error) {
+				return contents, nil
+			}
+
+			analyzer := &troubleshootv1beta2.CertificatesAnalyze{
+				Threshold: "720h",
+				Outcomes:  test.outcomes,
+			}
+			actual, err := analyzeCertificates(analyzer, getFile, now)
+			req.NoError(err)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
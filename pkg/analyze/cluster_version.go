@@ -73,7 +73,7 @@ func analyzeClusterVersionResult(k8sVersion semver.Version, outcomes []*troubles
 			return &result, nil
 		}
 
-		whenRange, err := semver.ParseRange(when)
+		whenRange, err := parseVersionConstraint(when)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to parse semver range")
 		}
@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+type getCollectedObject func(string, interface{}) error
+
+// CollectedObjectCache unmarshals collected json files once and shares the parsed value between analyzers.
+// Values handed out by the cache are shared, so analyzers must treat them as read only.
+type CollectedObjectCache struct {
+	getFile getCollectedFileContents
+
+	mut     sync.Mutex
+	objects map[collectedObjectKey]*collectedObject
+}
+
+type collectedObjectKey struct {
+	name string
+	typ  reflect.Type
+}
+
+type collectedObject struct {
+	once  sync.Once
+	value reflect.Value
+	err   error
+}
+
+func NewCollectedObjectCache(getFile func(string) ([]byte, error)) *CollectedObjectCache {
+	return &CollectedObjectCache{
+		getFile: getFile,
+		objects: map[collectedObjectKey]*collectedObject{},
+	}
+}
+
+// GetCollectedObject unmarshals the collected file name into out, which must be a non-nil pointer. The file is only
+// read and unmarshaled the first time it is requested for a given type of out.
+func (c *CollectedObjectCache) GetCollectedObject(name string, out interface{}) error {
+	outValue := reflect.ValueOf(out)
+	if outValue.Kind() != reflect.Ptr || outValue.IsNil() {
+		return errors.Errorf("cannot unmarshal %s into non-pointer %T", name, out)
+	}
+
+	key := collectedObjectKey{name: name, typ: outValue.Type()}
+
+	c.mut.Lock()
+	object, ok := c.objects[key]
+	if !ok {
+		object = &collectedObject{}
+		c.objects[key] = object
+	}
+	c.mut.Unlock()
+
+	object.once.Do(func() {
+		collected, err := c.getFile(name)
+		if err != nil {
+			object.err = err
+			return
+		}
+
+		value := reflect.New(outValue.Type().Elem())
+		if err := json.Unmarshal(collected, value.Interface()); err != nil {
+			object.err = errors.Wrapf(err, "failed to unmarshal %s", name)
+			return
+		}
+		object.value = value.Elem()
+	})
+	if object.err != nil {
+		return object.err
+	}
+
+	outValue.Elem().Set(object.value)
+	return nil
+}
@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCollectedObjectCache_GetCollectedObject(t *testing.T) {
+	scopetest := scopeagent.StartTest(t)
+	defer scopetest.End()
+	req := require.New(t)
+
+	var reads int32
+	cache := NewCollectedObjectCache(func(name string) ([]byte, error) {
+		atomic.AddInt32(&reads, 1)
+		if name == "cluster-resources/nodes.json" {
+			return []byte(`[{"metadata":{"name":"node-1"}},{"metadata":{"name":"node-2"}}]`), nil
+		}
+		return nil, errors.Errorf("%s not found", name)
+	})
+
+	nodeLists := make([][]corev1.Node, 10)
+	errs := make([]error, 10)
+	var wg sync.WaitGroup
+	for i := range nodeLists {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = cache.GetCollectedObject("cluster-resources/nodes.json", &nodeLists[i])
+		}(i)
+	}
+	wg.Wait()
+	for i, nodes := range nodeLists {
+		req.NoError(errs[i])
+		req.Len(nodes, 2)
+		req.Equal("node-2", nodes[1].Name)
+	}
+	req.Equal(int32(1), atomic.LoadInt32(&reads))
+
+	// a different type is unmarshaled separately
+	names := []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}{}
+	err := cache.GetCollectedObject("cluster-resources/nodes.json", &names)
+	req.NoError(err)
+	req.Len(names, 2)
+	req.Equal("node-1", names[0].Metadata.Name)
+	req.Equal(int32(2), atomic.LoadInt32(&reads))
+
+	// errors are cached too
+	for i := 0; i < 2; i++ {
+		err = cache.GetCollectedObject("cluster-resources/pods.json", &[]corev1.Pod{})
+		req.EqualError(err, "cluster-resources/pods.json not found")
+	}
+	req.Equal(int32(3), atomic.LoadInt32(&reads))
+
+	err = cache.GetCollectedObject("cluster-resources/nodes.json", []corev1.Node{})
+	req.Error(err)
+}
@@ -0,0 +1,252 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/analyze/conditional"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// errNoCompareFieldValue is returned when an aggregate such as min() has no values to evaluate
+var errNoCompareFieldValue = errors.New("no values found")
+
+type compareFieldMessageData struct {
+	Count int
+	Value string
+}
+
+// analyzeCompareField evaluates conditionals such as "sum() > 100Gi" against the numbers and quantities that a
+// JSONPath selects from a collected JSON file, with the count(), min(), max(), sum() and avg() functions
+func analyzeCompareField(analyzer *troubleshootv1beta2.CompareField, getFile getCollectedFileContents) (*AnalyzeResult, error) {
+	fullPath := filepath.Join(analyzer.CollectorName, analyzer.FileName)
+	contents, err := getFile(fullPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", fullPath)
+	}
+
+	values, err := findCompareFieldValues(contents, analyzer.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find values in %s", fullPath)
+	}
+
+	title := analyzer.CheckName
+	if title == "" {
+		title = analyzer.FileName
+	}
+	result := &AnalyzeResult{
+		Title: title,
+	}
+
+	// ordering is important for passthrough
+	for _, outcome := range analyzer.Outcomes {
+		single := outcome.Fail
+		if single == nil {
+			single = outcome.Warn
+		}
+		if single == nil {
+			single = outcome.Pass
+		}
+		if single == nil {
+			continue
+		}
+
+		isMatch, actualValue, err := evaluateCompareFieldConditional(single.When, values)
+		if errors.Cause(err) == errNoCompareFieldValue {
+			result.IsWarn = true
+			result.Message = fmt.Sprintf("Unable to evaluate %q: no values found at %s", single.When, analyzer.Path)
+			return result, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to evaluate %q", single.When)
+		}
+		if !isMatch {
+			continue
+		}
+
+		result.IsFail = single == outcome.Fail
+		result.IsWarn = single == outcome.Warn
+		result.IsPass = single == outcome.Pass
+		result.URI = single.URI
+
+		data := compareFieldMessageData{
+			Count: len(values),
+			Value: conditional.FormatValue(actualValue),
+		}
+		result.Message, err = renderCompareFieldMessage(single.Message, single.When, data, !result.IsPass)
+		if err != nil {
+			return nil, err
+		}
+
+		return result, nil
+	}
+
+	return result, nil
+}
+
+func evaluateCompareFieldConditional(when string, values []*resource.Quantity) (bool, interface{}, error) {
+	return conditional.Evaluate(when, func(expression string) (interface{}, error) {
+		return findCompareFieldValue(expression, values)
+	})
+}
+
+// findCompareFieldValues returns the values selected by the JSONPath. Numbers and quantity strings such as "10Gi"
+// are returned as quantities, and arrays are flattened so that both .sizes and .sizes[*] select each size.
+func findCompareFieldValues(contents []byte, path string) ([]*resource.Quantity, error) {
+	var data interface{}
+	if err := json.Unmarshal(contents, &data); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal json")
+	}
+
+	parser := jsonpath.New("compareField")
+	parser.AllowMissingKeys(true)
+	if err := parser.Parse(compareFieldJSONPath(path)); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse path %q", path)
+	}
+
+	results, err := parser.FindResults(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to evaluate path %q", path)
+	}
+
+	values := []*resource.Quantity{}
+	for _, result := range results {
+		for _, value := range result {
+			if !value.IsValid() {
+				continue
+			}
+			selected := []interface{}{value.Interface()}
+			if array, ok := value.Interface().([]interface{}); ok {
+				selected = array
+			}
+			for _, v := range selected {
+				quantity, err := compareFieldQuantity(v)
+				if err != nil {
+					return nil, err
+				}
+				if quantity != nil {
+					values = append(values, quantity)
+				}
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// compareFieldJSONPath accepts paths with or without the braces of a kubectl JSONPath template, e.g. .items[*].size
+func compareFieldJSONPath(path string) string {
+	path = strings.TrimSpace(path)
+	if strings.HasPrefix(path, "{") {
+		return path
+	}
+	if !strings.HasPrefix(path, ".") {
+		path = "." + path
+	}
+	return fmt.Sprintf("{%s}", path)
+}
+
+// compareFieldQuantity parses a selected value. Nulls are skipped.
+func compareFieldQuantity(value interface{}) (*resource.Quantity, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case float64:
+		parsed, err := resource.ParseQuantity(strconv.FormatFloat(v, 'f', -1, 64))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %v", v)
+		}
+		return &parsed, nil
+	case string:
+		parsed, err := resource.ParseQuantity(strings.TrimSpace(v))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %q", v)
+		}
+		return &parsed, nil
+	}
+
+	return nil, errors.Errorf("value %v is not a number or quantity", value)
+}
+
+// findCompareFieldValue computes the value of a function() expression across the selected values. The result is an
+// int for count() and a *resource.Quantity otherwise.
+func findCompareFieldValue(expression string, values []*resource.Quantity) (interface{}, error) {
+	match := conditional.ExpressionRegex.FindStringSubmatch(expression)
+	if match == nil {
+		return nil, errors.Errorf("conditional does not match pattern of function(), got %q", expression)
+	}
+	function := match[1]
+	if strings.TrimSpace(match[2]) != "" {
+		return nil, errors.Errorf("%s() does not take a property", function)
+	}
+
+	switch function {
+	case "count":
+		return len(values), nil
+	case "sum":
+		sum := resource.Quantity{}
+		for _, value := range values {
+			sum.Add(*value)
+		}
+		return &sum, nil
+	case "min", "max", "avg":
+		if len(values) == 0 {
+			return nil, errNoCompareFieldValue
+		}
+	default:
+		return nil, errors.Errorf("unsupported function %q", function)
+	}
+
+	found := values[0].DeepCopy()
+	sum := resource.Quantity{}
+	for _, value := range values {
+		sum.Add(*value)
+		if (function == "min" && value.Cmp(found) < 0) || (function == "max" && value.Cmp(found) > 0) {
+			found = value.DeepCopy()
+		}
+	}
+	if function == "avg" {
+		return resource.NewMilliQuantity(sum.MilliValue()/int64(len(values)), sum.Format), nil
+	}
+
+	return &found, nil
+}
+
+// renderCompareFieldMessage renders the outcome message as a template with the number of values and the computed
+// value. Fail and warn outcomes without a message get a default one, e.g. "sum() is 30Gi, which is > 20Gi".
+func renderCompareFieldMessage(message string, when string, data compareFieldMessageData, isProblem bool) (string, error) {
+	if message == "" {
+		parts := conditional.SplitActual(strings.TrimSpace(when))
+		if !isProblem || len(parts) == 0 || data.Value == "" {
+			return "", nil
+		}
+		if len(parts) == 2 {
+			parts = append([]string{"count()"}, parts...)
+		}
+		return fmt.Sprintf("%s is %s, which is %s", parts[0], data.Value, strings.Join(parts[1:], " ")), nil
+	}
+
+	if !strings.Contains(message, "{{") {
+		return message, nil
+	}
+
+	tmpl, err := template.New("message").Parse(message)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse message template")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", errors.Wrap(err, "failed to execute message template")
+	}
+
+	return buf.String(), nil
+}
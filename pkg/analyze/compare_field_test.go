@@ -0,0 +1,154 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+)
+
+func Test_analyzeCompareField(t *testing.T) {
+	files := map[string][]byte{
+		"cluster-resources/pvcs/default.json": []byte(`{
+			"items": [
+				{"metadata": {"name": "data-0"}, "spec": {"resources": {"requests": {"storage": "10Gi"}}}},
+				{"metadata": {"name": "data-1"}, "spec": {"resources": {"requests": {"storage": "20Gi"}}}},
+				{"metadata": {"name": "logs-0"}, "spec": {"resources": {"requests": {"storage": "5Gi"}}}}
+			]
+		}`),
+		"app/replicas.json": []byte(`{"replicas": [1, 2, 3], "missing": null}`),
+	}
+	getFile := func(path string) ([]byte, error) {
+		return files[path], nil
+	}
+
+	pvcs := func(outcomes ...*troubleshootv1beta2.Outcome) *troubleshootv1beta2.CompareField {
+		return &troubleshootv1beta2.CompareField{
+			AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{
+				CheckName: "PVC Sizes",
+			},
+			CollectorName: "cluster-resources/pvcs",
+			FileName:      "default.json",
+			Path:          "{.items[*].spec.resources.requests.storage}",
+			Outcomes:      outcomes,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.CompareField
+		expected *AnalyzeResult
+		isError  bool
+	}{
+		{
+			name: "default fail message",
+			analyzer: pvcs(
+				&troubleshootv1beta2.Outcome{
+					Fail: &troubleshootv1beta2.SingleOutcome{
+						When: "sum() > 30Gi",
+					},
+				},
+				&troubleshootv1beta2.Outcome{
+					Pass: &troubleshootv1beta2.SingleOutcome{
+						Message: "PVCs fit on the disk",
+					},
+				},
+			),
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "PVC Sizes",
+				Message: "sum() is 35Gi, which is > 30Gi",
+			},
+		},
+		{
+			name: "templated warn message",
+			analyzer: pvcs(
+				&troubleshootv1beta2.Outcome{
+					Warn: &troubleshootv1beta2.SingleOutcome{
+						When:    "min() < 8Gi",
+						Message: "The smallest of {{ .Count }} PVCs is {{ .Value }}",
+					},
+				},
+			),
+			expected: &AnalyzeResult{
+				IsWarn:  true,
+				Title:   "PVC Sizes",
+				Message: "The smallest of 3 PVCs is 5Gi",
+			},
+		},
+		{
+			name: "numbers without braces",
+			analyzer: &troubleshootv1beta2.CompareField{
+				CollectorName: "app",
+				FileName:      "replicas.json",
+				Path:          "replicas",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When: "avg() < 2",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    "max() between 3 5",
+							Message: "Enough replicas",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "replicas.json",
+				Message: "Enough replicas",
+			},
+		},
+		{
+			name: "no values",
+			analyzer: &troubleshootv1beta2.CompareField{
+				CollectorName: "app",
+				FileName:      "replicas.json",
+				Path:          ".missing",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When: "min() < 1",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsWarn:  true,
+				Title:   "replicas.json",
+				Message: `Unable to evaluate "min() < 1": no values found at .missing`,
+			},
+		},
+		{
+			name: "values that are not numbers",
+			analyzer: &troubleshootv1beta2.CompareField{
+				CollectorName: "cluster-resources/pvcs",
+				FileName:      "default.json",
+				Path:          ".items[*].metadata.name",
+			},
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+			req := require.New(t)
+
+			actual, err := analyzeCompareField(test.analyzer, getFile)
+			if test.isError {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,598 @@
+// Package conditional evaluates the conditionals used in analyzer outcomes, such as "count() >= 3",
+// "sum(cpuCapacity) > 16" or "min(memoryAllocatable) between 8Gi 16Gi". The analyzer computes the value of each
+// function(property) expression, and this package parses the conditional and compares the values. Conditionals can
+// be combined with || and &&, as in "count() >= 3 || sum(cpuAllocatable) >= 48".
+package conditional
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ExpressionRegex matches function(property) expressions such as sum(cpuCapacity)
+var ExpressionRegex = regexp.MustCompile(`^(?P<function>[^(]*)\((?P<property>.*)\)$`)
+
+// ValueFunc computes the value of a function(property) expression. Values are an int, a float64 or a
+// *resource.Quantity.
+type ValueFunc func(expression string) (interface{}, error)
+
+// Evaluate returns whether the conditional matches along with the computed actual value. An empty conditional
+// always matches, and a conditional without an expression, such as "> 3", compares count().
+func Evaluate(conditional string, findValue ValueFunc) (bool, interface{}, error) {
+	return EvaluateWithTolerance(conditional, findValue, nil)
+}
+
+// EvaluateWithTolerance is Evaluate where == and != treat values within the tolerance of the desired value as
+// equal. A nil tolerance compares exactly.
+func EvaluateWithTolerance(conditional string, findValue ValueFunc, tolerance *Tolerance) (bool, interface{}, error) {
+	res, actualValue, _, err := EvaluateCombined(conditional, func(single string) (bool, interface{}, error) {
+		return evaluateSingle(single, findValue, tolerance)
+	})
+	return res, actualValue, err
+}
+
+// EvaluateCombined evaluates a conditional combined with || and &&, calling evaluate for each of the conditionals it
+// combines. Every one is evaluated, so that a mistake in one is reported whatever the values of the others. The
+// result is taken from the conditional that decides it, as if evaluation stopped as soon as the result was known:
+// the first that matches for ||, and the first that does not match for &&. Along with whether the conditional
+// matches, this returns the actual value computed by evaluate for the deciding conditional, and the deciding
+// conditional itself. A conditional that is not combined is passed to evaluate as is and decides itself.
+func EvaluateCombined(conditional string, evaluate func(string) (bool, interface{}, error)) (bool, interface{}, string, error) {
+	groups, err := SplitLogical(conditional)
+	if err != nil {
+		return false, nil, "", err
+	}
+	if len(groups) == 1 && len(groups[0]) == 1 {
+		res, actualValue, err := evaluate(conditional)
+		return res, actualValue, conditional, err
+	}
+
+	res := false
+	var actualValue interface{}
+	deciding := ""
+	isDecided := false
+	for _, group := range groups {
+		groupRes := true
+		var groupValue interface{}
+		groupDeciding := ""
+		isGroupDecided := false
+		for _, single := range group {
+			singleRes, singleValue, err := evaluate(single)
+			if err != nil {
+				return false, nil, "", errors.Wrap(err, single)
+			}
+			if isGroupDecided {
+				continue
+			}
+			groupRes, groupValue, groupDeciding = singleRes, singleValue, single
+			isGroupDecided = !singleRes
+		}
+
+		if isDecided {
+			continue
+		}
+		res, actualValue, deciding = groupRes, groupValue, groupDeciding
+		isDecided = groupRes
+	}
+
+	return res, actualValue, deciding, nil
+}
+
+// SplitLogical splits a conditional into the conditionals that || combines, each split into the conditionals that &&
+// combines. && binds more tightly than ||, so "count() >= 3 || sum(cpuAllocatable) >= 48 && count() >= 2" is
+// [["count() >= 3"], ["sum(cpuAllocatable) >= 48", "count() >= 2"]]. The operators must be separated from the
+// conditionals by whitespace, and there must be a conditional on both sides of each. A conditional that is not
+// combined is the only conditional of the only group.
+func SplitLogical(conditional string) ([][]string, error) {
+	groups := [][]string{}
+	group := []string{}
+	current := []string{}
+	for _, part := range Split(strings.TrimSpace(conditional)) {
+		if part != "||" && part != "&&" {
+			current = append(current, part)
+			continue
+		}
+		if len(current) == 0 {
+			return nil, errors.Errorf("%s requires a conditional on both sides", part)
+		}
+		group = append(group, strings.Join(current, " "))
+		current = []string{}
+		if part == "||" {
+			groups = append(groups, group)
+			group = []string{}
+		}
+	}
+
+	if len(current) == 0 && (len(groups) > 0 || len(group) > 0) {
+		operator := "&&"
+		if len(group) == 0 {
+			operator = "||"
+		}
+		return nil, errors.Errorf("%s requires a conditional on both sides", operator)
+	}
+	group = append(group, strings.Join(current, " "))
+	return append(groups, group), nil
+}
+
+// evaluateSingle evaluates a conditional that is not combined with || or &&
+func evaluateSingle(conditional string, findValue ValueFunc, tolerance *Tolerance) (res bool, actualValue interface{}, err error) {
+	res = false
+	err = nil
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("failed to evaluate %q: %v", conditional, r)
+		}
+	}()
+
+	if conditional == "" {
+		res = true
+		return
+	}
+
+	parts := Split(strings.TrimSpace(conditional))
+
+	if len(parts) == 2 {
+		parts = append([]string{"count()"}, parts...)
+	}
+
+	// a factor after the expression, as in "sum(cpuAllocatable) * 2 >= 48", multiplies the actual value
+	var factor *float64
+	if len(parts) >= 3 && parts[1] == "*" {
+		var parsed float64
+		parsed, err = strconv.ParseFloat(parts[2], 64)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to parse factor %q", parts[2])
+			return
+		}
+		factor = &parsed
+		parts = append([]string{parts[0]}, parts[3:]...)
+	}
+	findActualValue := func(expression string) (interface{}, error) {
+		value, err := findValue(expression)
+		if err != nil || factor == nil {
+			return value, err
+		}
+		return multiplyValue(value, *factor)
+	}
+
+	if len(parts) >= 2 && parts[1] == "between" {
+		if len(parts) != 4 {
+			err = errors.New("between requires a lower and an upper bound, e.g. count() between 3 5")
+			return
+		}
+		res, actualValue, err = evaluateRange(parts[0], parts[2], parts[3], findActualValue, findValue)
+		return
+	}
+
+	isMultiplied := len(parts) == 5 && parts[3] == "*"
+	if len(parts) != 3 && !isMultiplied {
+		err = errors.New("unable to parse conditional")
+		return
+	}
+
+	operator := parts[1]
+
+	desiredValue, err := resolveDesiredValue(parts[2:], findValue)
+	if err != nil {
+		return
+	}
+
+	actualValue, err = findActualValue(parts[0])
+	if err != nil {
+		return
+	}
+
+	cmp, err := Compare(actualValue, desiredValue)
+	if err != nil {
+		return
+	}
+	if cmp != 0 && tolerance != nil && isEqualityOperator(operator) {
+		var isWithin bool
+		isWithin, err = tolerance.contains(actualValue, desiredValue)
+		if err != nil {
+			return
+		}
+		if isWithin {
+			cmp = 0
+		}
+	}
+
+	res, ok := MatchesOperator(cmp, operator)
+	if !ok {
+		err = errors.Errorf("unexpected operator %q", operator)
+	}
+	return
+}
+
+// evaluateRange returns whether the expression, computed by findActualValue, is within the inclusive bounds
+func evaluateRange(expression string, lowerBound string, upperBound string, findActualValue ValueFunc, findValue ValueFunc) (bool, interface{}, error) {
+	actualValue, err := findActualValue(expression)
+	if err != nil {
+		return false, nil, err
+	}
+
+	lowerValue, err := resolveDesiredValue([]string{lowerBound}, findValue)
+	if err != nil {
+		return false, nil, errors.Wrap(err, "failed to resolve lower bound")
+	}
+	lowerCmp, err := Compare(actualValue, lowerValue)
+	if err != nil {
+		return false, nil, errors.Wrap(err, "failed to compare lower bound")
+	}
+
+	upperValue, err := resolveDesiredValue([]string{upperBound}, findValue)
+	if err != nil {
+		return false, nil, errors.Wrap(err, "failed to resolve upper bound")
+	}
+	upperCmp, err := Compare(actualValue, upperValue)
+	if err != nil {
+		return false, nil, errors.Wrap(err, "failed to compare upper bound")
+	}
+
+	return lowerCmp >= 0 && upperCmp <= 0, actualValue, nil
+}
+
+// resolveDesiredValue evaluates the right-hand side of a conditional. This is either a literal or a
+// function(property) expression, optionally followed by a multiplier, e.g. "sum(cpuCapacity) * 0.9".
+func resolveDesiredValue(parts []string, findValue ValueFunc) (string, error) {
+	expression := parts[0]
+	isExpression := ExpressionRegex.MatchString(expression)
+
+	if len(parts) == 1 && !isExpression {
+		return expression, nil
+	}
+
+	var value interface{}
+	if isExpression {
+		computed, err := findValue(expression)
+		if err != nil {
+			return "", err
+		}
+		value = computed
+	} else if parsed, err := strconv.Atoi(expression); err == nil {
+		value = parsed
+	} else {
+		parsed, err := resource.ParseQuantity(expression)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to parse desired value %q", expression)
+		}
+		value = &parsed
+	}
+
+	if len(parts) == 1 {
+		return FormatValue(value), nil
+	}
+
+	multiplier, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse multiplier %q", parts[2])
+	}
+
+	multiplied, err := multiplyValue(value, multiplier)
+	if err != nil {
+		return "", err
+	}
+	return FormatValue(multiplied), nil
+}
+
+// multiplyValue multiplies a computed value. Integers become decimals and quantities keep their format, so that
+// both sides of a conditional are multiplied the same way.
+func multiplyValue(value interface{}, multiplier float64) (interface{}, error) {
+	switch v := value.(type) {
+	case int:
+		return float64(v) * multiplier, nil
+	case float64:
+		return v * multiplier, nil
+	case *resource.Quantity:
+		return resource.NewMilliQuantity(int64(math.Round(float64(v.MilliValue())*multiplier)), v.Format), nil
+	}
+
+	return nil, errors.Errorf("unexpected value type %T", value)
+}
+
+// Compare returns -1, 0 or 1 as the actual value is less than, equal to or greater than the desired value. Desired
+// values may be integers, decimals or resource quantities.
+func Compare(actualValue interface{}, desiredValue string) (int, error) {
+	switch actual := actualValue.(type) {
+	case int:
+		if parsed, err := strconv.Atoi(desiredValue); err == nil {
+			return compareFloats(float64(actual), float64(parsed)), nil
+		}
+		if parsed, err := strconv.ParseFloat(desiredValue, 64); err == nil {
+			return compareFloats(float64(actual), parsed), nil
+		}
+		parsed, err := resource.ParseQuantity(desiredValue)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to parse desired value %q", desiredValue)
+		}
+		return resource.NewQuantity(int64(actual), resource.DecimalSI).Cmp(parsed), nil
+
+	case float64:
+		parsed, err := strconv.ParseFloat(strings.TrimSuffix(desiredValue, "%"), 64)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to parse desired value %q", desiredValue)
+		}
+		return compareFloats(actual, parsed), nil
+
+	case *resource.Quantity:
+		parsed, err := resource.ParseQuantity(desiredValue)
+		if err == nil {
+			return actual.Cmp(parsed), nil
+		}
+		if parsedFloat, floatErr := strconv.ParseFloat(desiredValue, 64); floatErr == nil {
+			return compareFloats(float64(actual.MilliValue())/1000, parsedFloat), nil
+		}
+		return 0, errors.Wrapf(err, "failed to parse desired value %q", desiredValue)
+	}
+
+	return 0, errors.Errorf("unexpected value type %T", actualValue)
+}
+
+func compareFloats(a float64, b float64) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+// MatchesOperator reports whether a -1, 0 or 1 comparison result satisfies the operator. The second result is
+// false for unknown operators.
+func MatchesOperator(cmp int, operator string) (bool, bool) {
+	switch operator {
+	case "=", "==", "===":
+		return cmp == 0, true
+	case "!=", "<>":
+		return cmp != 0, true
+	case "<":
+		return cmp == -1, true
+	case ">":
+		return cmp == 1, true
+	case "<=":
+		return cmp <= 0, true
+	case ">=":
+		return cmp >= 0, true
+	}
+
+	return false, false
+}
+
+func isEqualityOperator(operator string) bool {
+	switch operator {
+	case "=", "==", "===", "!=", "<>":
+		return true
+	}
+	return false
+}
+
+// Tolerance is how far apart two values can be and still be equal, either as a percentage of the desired value or
+// as a fixed delta. This allows for rounding, such as between binary and decimal SI quantities.
+type Tolerance struct {
+	Percent float64
+	Delta   float64
+}
+
+// ParseTolerance parses a percentage such as "1%" or a delta such as "512Mi" or "0.5". An empty tolerance is nil,
+// which compares exactly.
+func ParseTolerance(tolerance string) (*Tolerance, error) {
+	tolerance = strings.TrimSpace(tolerance)
+	if tolerance == "" {
+		return nil, nil
+	}
+
+	if strings.HasSuffix(tolerance, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(tolerance, "%")), 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse tolerance %q", tolerance)
+		}
+		if percent < 0 {
+			return nil, errors.Errorf("tolerance %q is negative", tolerance)
+		}
+		return &Tolerance{Percent: percent}, nil
+	}
+
+	delta, err := resource.ParseQuantity(tolerance)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse tolerance %q", tolerance)
+	}
+	if delta.Sign() < 0 {
+		return nil, errors.Errorf("tolerance %q is negative", tolerance)
+	}
+	return &Tolerance{Delta: quantityFloat(&delta)}, nil
+}
+
+// contains reports whether the actual value is within the tolerance of the desired value
+func (t *Tolerance) contains(actualValue interface{}, desiredValue string) (bool, error) {
+	var actual float64
+	switch v := actualValue.(type) {
+	case int:
+		actual = float64(v)
+	case float64:
+		actual = v
+	case *resource.Quantity:
+		actual = quantityFloat(v)
+	default:
+		return false, errors.Errorf("unexpected value type %T", actualValue)
+	}
+
+	desired, err := strconv.ParseFloat(strings.TrimSuffix(desiredValue, "%"), 64)
+	if err != nil {
+		parsed, err := resource.ParseQuantity(desiredValue)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse desired value %q", desiredValue)
+		}
+		desired = quantityFloat(&parsed)
+	}
+
+	return t.Within(actual, desired), nil
+}
+
+// Within reports whether the actual value is within the tolerance of the desired value. A nil tolerance compares
+// exactly.
+func (t *Tolerance) Within(actual float64, desired float64) bool {
+	if t == nil {
+		return actual == desired
+	}
+	band := t.Delta + math.Abs(desired)*t.Percent/100
+	return math.Abs(actual-desired) <= band
+}
+
+func quantityFloat(q *resource.Quantity) float64 {
+	return float64(q.MilliValue()) / 1000
+}
+
+// setMembershipRegex matches property set membership such as "cpuAllocatable in [4,8,16]" or
+// "memoryAllocatable notIn [16Gi, 32Gi]"
+var setMembershipRegex = regexp.MustCompile(`^(?P<property>\S.*?)\s+(?P<operator>in|notIn)\s+(?P<set>.*)$`)
+
+// SetMembership is a property compared to a set of values, as in count(cpuAllocatable in [4,8,16])
+type SetMembership struct {
+	Property  string
+	IsNegated bool
+	Values    []resource.Quantity
+}
+
+// ParseSetMembership parses "property in [a,b]" or "property notIn [a,b]". The result is nil if the expression does
+// not use in or notIn, and an error if the set is malformed.
+func ParseSetMembership(expression string) (*SetMembership, error) {
+	match := setMembershipRegex.FindStringSubmatch(strings.TrimSpace(expression))
+	if match == nil {
+		return nil, nil
+	}
+
+	set := strings.TrimSpace(match[3])
+	if !strings.HasPrefix(set, "[") || !strings.HasSuffix(set, "]") {
+		return nil, errors.Errorf("%s requires a set in brackets, e.g. %s %s [4,8,16], got %q", match[2], match[1], match[2], set)
+	}
+
+	membership := &SetMembership{
+		Property:  match[1],
+		IsNegated: match[2] == "notIn",
+	}
+	for _, member := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(set, "["), "]"), ",") {
+		member = strings.TrimSpace(member)
+		if member == "" {
+			return nil, errors.Errorf("set %s has an empty member", set)
+		}
+		value, err := resource.ParseQuantity(member)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse set member %q", member)
+		}
+		membership.Values = append(membership.Values, value)
+	}
+
+	return membership, nil
+}
+
+// Matches reports whether the value is in the set, or not in it for notIn. A nil value never matches.
+func (m *SetMembership) Matches(value *resource.Quantity) bool {
+	if value == nil {
+		return false
+	}
+	for _, member := range m.Values {
+		if value.Cmp(member) == 0 {
+			return !m.IsNegated
+		}
+	}
+	return m.IsNegated
+}
+
+// FormatValue formats a computed value for messages
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case *resource.Quantity:
+		if v == nil {
+			return ""
+		}
+		return v.String()
+	}
+
+	return ""
+}
+
+// SplitActual is Split with a factor applied to the actual value kept with its expression, e.g. "sum(cpuCapacity) * 2"
+// and ">= 48" rather than "sum(cpuCapacity)" and "* 2 >= 48", for describing the actual value in messages
+func SplitActual(conditional string) []string {
+	parts := Split(conditional)
+	if len(parts) >= 3 && parts[1] == "*" {
+		parts = append([]string{strings.Join(parts[:3], " ")}, parts[3:]...)
+	}
+	return parts
+}
+
+// Split splits a conditional on whitespace, keeping function arguments such as
+// "percentile(memoryAllocatable, 90)" together as a single part
+func Split(conditional string) []string {
+	parts := []string{}
+	current := strings.Builder{}
+	depth := 0
+
+	for _, r := range conditional {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			if depth > 0 {
+				depth--
+			}
+		case unicode.IsSpace(r) && depth == 0:
+			if current.Len() > 0 {
+				parts = append(parts, current.String())
+				current.Reset()
+			}
+			continue
+		}
+		current.WriteRune(r)
+	}
+
+	if current.Len() > 0 {
+		parts = append(parts, current.String())
+	}
+
+	return parts
+}
+
+// variableRegex matches ${NAME} references, where NAME is a letter or underscore followed by letters, digits or
+// underscores, as in environment variable names
+var variableRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Interpolate replaces each ${NAME} reference in s with its value, e.g. "count() >= ${MIN_NODES}" becomes
+// "count() >= 3" when MIN_NODES is 3. Values are only ever read from values, never from the environment, so callers
+// decide what can be referenced. A reference to a name that is not in values is an error.
+func Interpolate(s string, values map[string]string) (string, error) {
+	var missing []string
+	interpolated := variableRegex.ReplaceAllStringFunc(s, func(reference string) string {
+		name := variableRegex.FindStringSubmatch(reference)[1]
+		value, ok := values[name]
+		if !ok {
+			for _, m := range missing {
+				if m == name {
+					return reference
+				}
+			}
+			missing = append(missing, name)
+			return reference
+		}
+		return value
+	})
+	if len(missing) == 1 {
+		return "", errors.Errorf("variable %s is not set", missing[0])
+	}
+	if len(missing) > 1 {
+		return "", errors.Errorf("variables %s are not set", strings.Join(missing, ", "))
+	}
+	return interpolated, nil
+}
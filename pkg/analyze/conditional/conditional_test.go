@@ -0,0 +1,286 @@
+package conditional
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestEvaluate(t *testing.T) {
+	findValue := func(expression string) (interface{}, error) {
+		switch expression {
+		case "count()":
+			return 3, nil
+		case "sum(memory)":
+			return resource.NewQuantity(24*1024*1024*1024, resource.BinarySI), nil
+		case "percent()":
+			return 75.0, nil
+		}
+		return nil, errors.Errorf("unsupported expression %q", expression)
+	}
+
+	tests := []struct {
+		name        string
+		conditional string
+		isMatch     bool
+		actualValue string
+		isError     bool
+	}{
+		{
+			name:        "empty",
+			conditional: "",
+			isMatch:     true,
+		},
+		{
+			name:        "implicit count",
+			conditional: ">= 3",
+			isMatch:     true,
+			actualValue: "3",
+		},
+		{
+			name:        "quantity",
+			conditional: "sum(memory) < 16Gi",
+			isMatch:     false,
+			actualValue: "24Gi",
+		},
+		{
+			name:        "multiplied expression",
+			conditional: "sum(memory) > count() * 4",
+			isMatch:     true,
+			actualValue: "24Gi",
+		},
+		{
+			name:        "factor on count",
+			conditional: "count() * 2 >= 6",
+			isMatch:     true,
+			actualValue: "6",
+		},
+		{
+			name:        "factor on quantity",
+			conditional: "sum(memory) * 0.5 == 12Gi",
+			isMatch:     true,
+			actualValue: "12Gi",
+		},
+		{
+			name:        "factor on both sides",
+			conditional: "count() * 2 > count() * 1.5",
+			isMatch:     true,
+			actualValue: "6",
+		},
+		{
+			name:        "factor with between",
+			conditional: "count() * 1.5 between 4 5",
+			isMatch:     true,
+			actualValue: "4.5",
+		},
+		{
+			name:        "invalid factor",
+			conditional: "count() * two > 3",
+			isError:     true,
+		},
+		{
+			name:        "between",
+			conditional: "percent() between 50 75",
+			isMatch:     true,
+			actualValue: "75",
+		},
+		{
+			name:        "between without an upper bound",
+			conditional: "count() between 3",
+			isError:     true,
+		},
+		{
+			name:        "unknown operator",
+			conditional: "count() ~= 3",
+			isError:     true,
+		},
+		{
+			name:        "unknown expression",
+			conditional: "max(memory) > 3",
+			isError:     true,
+		},
+		{
+			name:        "or",
+			conditional: "count() < 2 || percent() >= 75",
+			isMatch:     true,
+			actualValue: "75",
+		},
+		{
+			name:        "or without a match",
+			conditional: "count() < 2 || sum(memory) < 16Gi",
+			isMatch:     false,
+			actualValue: "24Gi",
+		},
+		{
+			name:        "and",
+			conditional: "count() >= 3 && sum(memory) < 16Gi",
+			isMatch:     false,
+			actualValue: "24Gi",
+		},
+		{
+			name:        "and decided by the first conditional",
+			conditional: "count() > 3 && sum(memory) >= 16Gi",
+			isMatch:     false,
+			actualValue: "3",
+		},
+		{
+			name:        "and binds more tightly than or",
+			conditional: "count() > 5 || percent() between 50 75 && sum(memory) > 16Gi",
+			isMatch:     true,
+			actualValue: "24Gi",
+		},
+		{
+			name:        "and binds more tightly than or, without a match",
+			conditional: "count() >= 3 && sum(memory) < 16Gi || percent() < 50",
+			isMatch:     false,
+			actualValue: "75",
+		},
+		{
+			name:        "or without a conditional on the right",
+			conditional: "count() >= 3 ||",
+			isError:     true,
+		},
+		{
+			name:        "error in a conditional that does not decide the result",
+			conditional: "count() >= 3 || max(memory) > 3",
+			isError:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+			req := require.New(t)
+
+			isMatch, actualValue, err := Evaluate(test.conditional, findValue)
+			if test.isError {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			assert.Equal(t, test.isMatch, isMatch)
+			assert.Equal(t, test.actualValue, FormatValue(actualValue))
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	scopetest := scopeagent.StartTest(t)
+	defer scopetest.End()
+	req := require.New(t)
+
+	req.Equal([]string{"percentile(memoryAllocatable, 90)", ">", "8Gi"}, Split("percentile(memoryAllocatable, 90)  > 8Gi"))
+	req.Equal([]string{}, Split(""))
+	req.Equal([]string{"sum(cpuAllocatable) * 2", ">=", "48"}, SplitActual("sum(cpuAllocatable) * 2 >= 48"))
+	req.Equal([]string{"sum(cpuAllocatable)", ">=", "count()", "*", "2"}, SplitActual("sum(cpuAllocatable) >= count() * 2"))
+}
+
+func TestSplitLogical(t *testing.T) {
+	scopetest := scopeagent.StartTest(t)
+	defer scopetest.End()
+	req := require.New(t)
+
+	groups, err := SplitLogical("count() >= 3")
+	req.NoError(err)
+	req.Equal([][]string{{"count() >= 3"}}, groups)
+
+	groups, err = SplitLogical("count() >= 3 || sum(cpuAllocatable) >= 48 && percentile(memoryAllocatable, 90)  >= 8Gi")
+	req.NoError(err)
+	req.Equal([][]string{{"count() >= 3"}, {"sum(cpuAllocatable) >= 48", "percentile(memoryAllocatable, 90) >= 8Gi"}}, groups)
+
+	_, err = SplitLogical("|| count() >= 3")
+	req.EqualError(err, "|| requires a conditional on both sides")
+
+	_, err = SplitLogical("count() >= 3 &&")
+	req.EqualError(err, "&& requires a conditional on both sides")
+}
+
+func TestInterpolate(t *testing.T) {
+	scopetest := scopeagent.StartTest(t)
+	defer scopetest.End()
+	req := require.New(t)
+
+	values := map[string]string{
+		"MIN_NODES":  "3",
+		"MIN_MEMORY": "8Gi",
+	}
+
+	interpolated, err := Interpolate("count() >= ${MIN_NODES}", values)
+	req.NoError(err)
+	req.Equal("count() >= 3", interpolated)
+
+	interpolated, err = Interpolate("min(memoryCapacity) between ${MIN_MEMORY} 16Gi", values)
+	req.NoError(err)
+	req.Equal("min(memoryCapacity) between 8Gi 16Gi", interpolated)
+
+	interpolated, err = Interpolate("costs $5 or $MIN_NODES", values)
+	req.NoError(err)
+	req.Equal("costs $5 or $MIN_NODES", interpolated)
+
+	_, err = Interpolate("count() between ${LOWER} ${UPPER} ${LOWER}", values)
+	req.EqualError(err, "variables LOWER, UPPER are not set")
+
+	_, err = Interpolate("count() >= ${MIN_NODES}", nil)
+	req.EqualError(err, "variable MIN_NODES is not set")
+}
+
+func TestParseTolerance(t *testing.T) {
+	scopetest := scopeagent.StartTest(t)
+	defer scopetest.End()
+	req := require.New(t)
+
+	tolerance, err := ParseTolerance("")
+	req.NoError(err)
+	req.Nil(tolerance)
+
+	tolerance, err = ParseTolerance("1.5%")
+	req.NoError(err)
+	req.Equal(&Tolerance{Percent: 1.5}, tolerance)
+
+	tolerance, err = ParseTolerance("1Ki")
+	req.NoError(err)
+	req.Equal(&Tolerance{Delta: 1024}, tolerance)
+
+	_, err = ParseTolerance("-1%")
+	req.Error(err)
+
+	_, err = ParseTolerance("a lot")
+	req.Error(err)
+}
+
+func TestParseSetMembership(t *testing.T) {
+	scopetest := scopeagent.StartTest(t)
+	defer scopetest.End()
+	req := require.New(t)
+
+	membership, err := ParseSetMembership("cpuAllocatable")
+	req.NoError(err)
+	req.Nil(membership)
+
+	membership, err = ParseSetMembership("cpuAllocatable in [4, 8,16]")
+	req.NoError(err)
+	req.Equal("cpuAllocatable", membership.Property)
+	req.False(membership.IsNegated)
+	req.Len(membership.Values, 3)
+	req.True(membership.Matches(resource.NewQuantity(8, resource.DecimalSI)))
+	req.False(membership.Matches(resource.NewQuantity(6, resource.DecimalSI)))
+	req.False(membership.Matches(nil))
+
+	membership, err = ParseSetMembership("memoryAllocatable notIn [16Gi]")
+	req.NoError(err)
+	req.True(membership.IsNegated)
+	quantity := resource.MustParse("32Gi")
+	req.True(membership.Matches(&quantity))
+	req.False(membership.Matches(nil))
+
+	for _, malformed := range []string{"cpuAllocatable in 4,8", "cpuAllocatable in [4,8", "cpuAllocatable in []", "cpuAllocatable in [4,,8]", "cpuAllocatable notIn [four]"} {
+		_, err = ParseSetMembership(malformed)
+		req.Error(err, malformed)
+	}
+}
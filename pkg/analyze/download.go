@@ -2,7 +2,9 @@ package analyzer
 
 import (
 	"archive/tar"
+	"bytes"
 	"compress/gzip"
+	"context"
 	"io"
 	"io/ioutil"
 	"os"
@@ -29,10 +31,11 @@ func AnalyzeLocal(localBundlePath string, analyzers []*troubleshootv1beta2.Analy
 	}
 
 	fcp := fileContentProvider{rootDir: rootDir}
+	cache := NewCollectedObjectCache(fcp.getFileContents)
 
 	analyzeResults := []*AnalyzeResult{}
 	for _, analyzer := range analyzers {
-		analyzeResult, err := Analyze(analyzer, fcp.getFileContents, fcp.getChildFileContents)
+		analyzeResult, err := AnalyzeWithCache(context.Background(), analyzer, fcp.getFileContents, fcp.getChildFileContents, cache)
 		if err != nil {
 			logger.Printf("an analyzer failed to run: %v\n", err)
 			continue
@@ -247,7 +250,32 @@ func FindBundleRootDir(localBundlePath string) (string, error) {
 }
 
 func (f fileContentProvider) getFileContents(fileName string) ([]byte, error) {
-	return ioutil.ReadFile(filepath.Join(f.rootDir, fileName))
+	contents, err := ioutil.ReadFile(filepath.Join(f.rootDir, fileName))
+	if os.IsNotExist(err) {
+		gzipped, gzErr := ioutil.ReadFile(filepath.Join(f.rootDir, fileName+".gz"))
+		if gzErr != nil {
+			return nil, err
+		}
+		return GunzipCollectedFile(fileName+".gz", gzipped)
+	}
+	return contents, err
+}
+
+// GunzipCollectedFile decompresses a collected file that was stored gzipped, such as nodes.json.gz, so that it can
+// be read by analyzers under its uncompressed name
+func GunzipCollectedFile(fileName string, contents []byte) ([]byte, error) {
+	gzReader, err := gzip.NewReader(bytes.NewReader(contents))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create gzip reader for %s", fileName)
+	}
+	defer gzReader.Close()
+
+	decompressed, err := ioutil.ReadAll(gzReader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decompress %s", fileName)
+	}
+
+	return decompressed, nil
 }
 
 func (f fileContentProvider) getChildFileContents(dirName string) (map[string][]byte, error) {
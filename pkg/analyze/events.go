@@ -0,0 +1,303 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/analyze/conditional"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// eventSelectorFields are the fields that count() can select events by, named as in kubectl field selectors
+var eventSelectorFields = map[string]func(corev1.Event) string{
+	"reason":              func(e corev1.Event) string { return e.Reason },
+	"type":                func(e corev1.Event) string { return e.Type },
+	"namespace":           func(e corev1.Event) string { return e.Namespace },
+	"metadata.namespace":  func(e corev1.Event) string { return e.Namespace },
+	"involvedObject.kind": func(e corev1.Event) string { return e.InvolvedObject.Kind },
+	"involvedObject.name": func(e corev1.Event) string { return e.InvolvedObject.Name },
+}
+
+type eventSelector struct {
+	Field     string
+	Value     string
+	IsNegated bool
+}
+
+type eventsConditional struct {
+	// Selector is the selector as written, e.g. "type=Warning,reason!=BackOff"
+	Selector  string
+	Selectors []eventSelector
+	Operator  string
+	Value     string
+}
+
+type eventsMessageData struct {
+	Count    int
+	Selector string
+	Lookback string
+	// Reason, Message and Object describe the most recent matching event
+	Reason  string
+	Message string
+	Object  string
+}
+
+// analyzeEvents matches an outcome when the number of events selected by its conditional satisfies it, e.g.
+// "count(reason=FailedScheduling) > 0". With a lookback, only events seen within that long before now are counted.
+func analyzeEvents(analyzer *troubleshootv1beta2.EventsAnalyze, getFile getCollectedFileContents, now time.Time) (*AnalyzeResult, error) {
+	contents, err := getFile("cluster-resources/events.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected events")
+	}
+
+	events := []corev1.Event{}
+	if err := json.Unmarshal(contents, &events); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal events")
+	}
+
+	events, err = filterEventsByLookback(events, analyzer.Lookback, now)
+	if err != nil {
+		return nil, err
+	}
+
+	title := analyzer.CheckName
+	if title == "" {
+		title = "Events"
+	}
+	result := &AnalyzeResult{
+		Title: title,
+	}
+
+	// ordering is important for passthrough
+	for _, outcome := range analyzer.Outcomes {
+		single := outcome.Fail
+		if single == nil {
+			single = outcome.Warn
+		}
+		if single == nil {
+			single = outcome.Pass
+		}
+		if single == nil {
+			continue
+		}
+
+		isMatch, data, err := evaluateEventsConditional(single.When, events)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to evaluate %q", single.When)
+		}
+		if !isMatch {
+			continue
+		}
+		data.Lookback = analyzer.Lookback
+
+		result.IsFail = single == outcome.Fail
+		result.IsWarn = single == outcome.Warn
+		result.IsPass = single == outcome.Pass
+		result.URI = single.URI
+
+		result.Message, err = renderEventsMessage(single.Message, single.When, data, !result.IsPass)
+		if err != nil {
+			return nil, err
+		}
+
+		return result, nil
+	}
+
+	return result, nil
+}
+
+// filterEventsByLookback keeps the events last seen no longer than lookback before now. Events without a
+// timestamp are only kept when there is no lookback.
+func filterEventsByLookback(events []corev1.Event, lookback string, now time.Time) ([]corev1.Event, error) {
+	if lookback == "" {
+		return events, nil
+	}
+
+	duration, err := time.ParseDuration(lookback)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse lookback %q", lookback)
+	}
+	since := now.Add(-duration)
+
+	filtered := []corev1.Event{}
+	for _, event := range events {
+		timestamp := eventTimestamp(event)
+		if timestamp.IsZero() || timestamp.Before(since) {
+			continue
+		}
+		filtered = append(filtered, event)
+	}
+
+	return filtered, nil
+}
+
+// eventTimestamp is the last time the event was seen. Events from the events.k8s.io API only set eventTime, and
+// keep the time of the latest occurrence in their series.
+func eventTimestamp(event corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if event.Series != nil && !event.Series.LastObservedTime.IsZero() {
+		return event.Series.LastObservedTime.Time
+	}
+	return event.EventTime.Time
+}
+
+func evaluateEventsConditional(when string, events []corev1.Event) (bool, eventsMessageData, error) {
+	data := eventsMessageData{}
+	if when == "" {
+		return true, data, nil
+	}
+
+	parsed, err := parseEventsConditional(when)
+	if err != nil {
+		return false, data, err
+	}
+	data.Selector = parsed.Selector
+
+	var latest *corev1.Event
+	for i, event := range events {
+		if !eventMatchesSelectors(event, parsed.Selectors) {
+			continue
+		}
+		data.Count++
+		if latest == nil || eventTimestamp(event).After(eventTimestamp(*latest)) {
+			latest = &events[i]
+		}
+	}
+	if latest != nil {
+		data.Reason = latest.Reason
+		data.Message = latest.Message
+		data.Object = fmt.Sprintf("%s/%s", latest.InvolvedObject.Kind, latest.InvolvedObject.Name)
+	}
+
+	cmp, err := conditional.Compare(data.Count, parsed.Value)
+	if err != nil {
+		return false, data, err
+	}
+
+	isMatch, _ := conditional.MatchesOperator(cmp, parsed.Operator)
+	return isMatch, data, nil
+}
+
+// parseEventsConditional uses the same conditional syntax as nodeResources, where count() is the only supported
+// function and takes comma separated field selectors, e.g. "count(type=Warning,reason!=BackOff) >= 3"
+func parseEventsConditional(when string) (*eventsConditional, error) {
+	parts := conditional.Split(strings.TrimSpace(when))
+	if len(parts) == 2 {
+		parts = append([]string{"count()"}, parts...)
+	}
+	if len(parts) != 3 {
+		return nil, errors.New("unable to parse events conditional, expected count(<selectors>) <operator> <value>")
+	}
+
+	match := conditional.ExpressionRegex.FindStringSubmatch(parts[0])
+	if match == nil || match[1] != "count" {
+		return nil, errors.Errorf("unsupported expression %q, expected count(<selectors>)", parts[0])
+	}
+
+	if _, ok := conditional.MatchesOperator(0, parts[1]); !ok {
+		return nil, errors.Errorf("unexpected operator %q", parts[1])
+	}
+
+	selectors, err := parseEventSelectors(match[2])
+	if err != nil {
+		return nil, err
+	}
+
+	return &eventsConditional{
+		Selector:  strings.TrimSpace(match[2]),
+		Selectors: selectors,
+		Operator:  parts[1],
+		Value:     parts[2],
+	}, nil
+}
+
+func parseEventSelectors(selectors string) ([]eventSelector, error) {
+	parsed := []eventSelector{}
+	if strings.TrimSpace(selectors) == "" {
+		return parsed, nil
+	}
+
+	for _, selector := range strings.Split(selectors, ",") {
+		selector = strings.TrimSpace(selector)
+
+		isNegated := false
+		var parts []string
+		switch {
+		case strings.Contains(selector, "!="):
+			isNegated = true
+			parts = strings.SplitN(selector, "!=", 2)
+		case strings.Contains(selector, "=="):
+			parts = strings.SplitN(selector, "==", 2)
+		default:
+			parts = strings.SplitN(selector, "=", 2)
+		}
+		if len(parts) != 2 {
+			return nil, errors.Errorf("unable to parse event selector %q, expected field=value", selector)
+		}
+
+		field := strings.TrimSpace(parts[0])
+		if _, ok := eventSelectorFields[field]; !ok {
+			return nil, errors.Errorf("unsupported event selector field %q", field)
+		}
+
+		parsed = append(parsed, eventSelector{
+			Field:     field,
+			Value:     strings.TrimSpace(parts[1]),
+			IsNegated: isNegated,
+		})
+	}
+
+	return parsed, nil
+}
+
+func eventMatchesSelectors(event corev1.Event, selectors []eventSelector) bool {
+	for _, selector := range selectors {
+		isEqual := eventSelectorFields[selector.Field](event) == selector.Value
+		if isEqual == selector.IsNegated {
+			return false
+		}
+	}
+	return true
+}
+
+// renderEventsMessage renders the outcome message as a template with the matching events. Fail and warn outcomes
+// without a message get a default one, e.g. "Found 2 events matching reason=FailedScheduling in the last 15m".
+func renderEventsMessage(message string, when string, data eventsMessageData, isProblem bool) (string, error) {
+	if message == "" {
+		if !isProblem || when == "" {
+			return "", nil
+		}
+		message = fmt.Sprintf("Found %d events", data.Count)
+		if data.Selector != "" {
+			message = fmt.Sprintf("%s matching %s", message, data.Selector)
+		}
+		if data.Lookback != "" {
+			message = fmt.Sprintf("%s in the last %s", message, data.Lookback)
+		}
+		return message, nil
+	}
+
+	if !strings.Contains(message, "{{") {
+		return message, nil
+	}
+
+	tmpl, err := template.New("message").Parse(message)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse message template")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", errors.Wrap(err, "failed to execute message template")
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,169 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_analyzeEvents(t *testing.T) {
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	events := []corev1.Event{
+		{
+			Reason:         "FailedScheduling",
+			Type:           "Warning",
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-0"},
+			LastTimestamp:  metav1.NewTime(now.Add(-5 * time.Minute)),
+		},
+		{
+			Reason:         "FailedScheduling",
+			Type:           "Warning",
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-1"},
+			EventTime:      metav1.NewMicroTime(now.Add(-2 * time.Minute)),
+		},
+		{
+			Reason:         "FailedScheduling",
+			Type:           "Warning",
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-2"},
+			LastTimestamp:  metav1.NewTime(now.Add(-time.Hour)),
+		},
+		{
+			Reason:         "BackOff",
+			Type:           "Warning",
+			Message:        "Back-off restarting failed container",
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "api-0"},
+			LastTimestamp:  metav1.NewTime(now.Add(-time.Minute)),
+		},
+		{
+			Reason:         "Scheduled",
+			Type:           "Normal",
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "api-0"},
+			LastTimestamp:  metav1.NewTime(now.Add(-time.Minute)),
+		},
+	}
+	contents, err := json.Marshal(events)
+	require.NoError(t, err)
+
+	getFile := func(path string) ([]byte, error) {
+		return contents, nil
+	}
+
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.EventsAnalyze
+		expected *AnalyzeResult
+		isError  bool
+	}{
+		{
+			name: "default fail message within lookback",
+			analyzer: &troubleshootv1beta2.EventsAnalyze{
+				Lookback: "15m",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When: "count(reason=FailedScheduling) > 0",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "No pods failed to schedule",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Events",
+				Message: "Found 2 events matching reason=FailedScheduling in the last 15m",
+			},
+		},
+		{
+			name: "templated warn message",
+			analyzer: &troubleshootv1beta2.EventsAnalyze{
+				AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{
+					CheckName: "Warning Events",
+				},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "count(type=Warning, reason!=FailedScheduling) >= 1",
+							Message: "{{ .Count }} {{ .Reason }} on {{ .Object }}: {{ .Message }}",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsWarn:  true,
+				Title:   "Warning Events",
+				Message: "1 BackOff on Pod/api-0: Back-off restarting failed container",
+			},
+		},
+		{
+			name: "old events are not counted",
+			analyzer: &troubleshootv1beta2.EventsAnalyze{
+				Lookback: "1m",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When: "count(reason=FailedScheduling) > 0",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "No pods failed to schedule",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "Events",
+				Message: "No pods failed to schedule",
+			},
+		},
+		{
+			name: "invalid lookback",
+			analyzer: &troubleshootv1beta2.EventsAnalyze{
+				Lookback: "15 minutes",
+			},
+			isError: true,
+		},
+		{
+			name: "unsupported function",
+			analyzer: &troubleshootv1beta2.EventsAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When: "sum(reason=FailedScheduling) > 0",
+						},
+					},
+				},
+			},
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+			req := require.New(t)
+
+			actual, err := analyzeEvents(test.analyzer, getFile, now)
+			if test.isError {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
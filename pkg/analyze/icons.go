@@ -0,0 +1,129 @@
+package analyzer
+
+type analyzerIcon struct {
+	IconKey string
+	IconURI string
+}
+
+// defaultAnalyzerIcons are the icons for results of each analyzer kind that do not set their own
+var defaultAnalyzerIcons = map[string]analyzerIcon{
+	"clusterVersion": {
+		IconKey: "kubernetes_cluster_version",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+	},
+	"storageClass": {
+		IconKey: "kubernetes_storage_class",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/storage-class.svg?w=12&h=12",
+	},
+	"customResourceDefinition": {
+		IconKey: "kubernetes_custom_resource_definition",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/custom-resource-definition.svg?w=13&h=16",
+	},
+	"ingress": {
+		IconKey: "kubernetes_ingress",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/ingress-controller.svg?w=20&h=13",
+	},
+	"secret": {
+		IconKey: "kubernetes_analyze_secret",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/secret.svg?w=13&h=16",
+	},
+	"imagePullSecret": {
+		IconKey: "kubernetes_image_pull_secret",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/image-pull-secret.svg?w=16&h=14",
+	},
+	"deploymentStatus": {
+		IconKey: "kubernetes_deployment_status",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17",
+	},
+	"statefulsetStatus": {
+		IconKey: "kubernetes_statefulset_status",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/statefulset-status.svg?w=23&h=14",
+	},
+	"containerRuntime": {
+		IconKey: "kubernetes_container_runtime",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/container-runtime.svg?w=23&h=16",
+	},
+	"distribution": {
+		IconKey: "kubernetes_distribution",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/distribution.svg?w=20&h=14",
+	},
+	"nodeResources": {
+		IconKey: "kubernetes_node_resources",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+	},
+	"nodeOS": {
+		IconKey: "kubernetes_node_os",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+	},
+	"textAnalyze": {
+		IconKey: "kubernetes_text_analyze",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+	},
+	"postgres": {
+		IconKey: "kubernetes_postgres_analyze",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/postgres-analyze.svg",
+	},
+	"mysql": {
+		IconKey: "kubernetes_mysql_analyze",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/mysql-analyze.svg",
+	},
+	"redis": {
+		IconKey: "kubernetes_redis_analyze",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/redis-analyze.svg",
+	},
+	"cephStatus": {
+		IconKey: "rook",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/rook.svg?w=11&h=16",
+	},
+	"sysctl": {
+		IconKey: "kubernetes_sysctl",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+	},
+	"events": {
+		IconKey: "kubernetes_events",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+	},
+	"compareField": {
+		IconKey: "kubernetes_compare_field",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+	},
+	"kubeletMaxPods": {
+		IconKey: "kubernetes_node_resources",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+	},
+	"certificates": {
+		IconKey: "kubernetes_analyze_secret",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/secret.svg?w=13&h=16",
+	},
+	"apiServerHealth": {
+		IconKey: "kubernetes_cluster_version",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+	},
+	"persistentVolumes": {
+		IconKey: "kubernetes_storage_class",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/storage-class.svg?w=12&h=12",
+	},
+	"ingressHosts": {
+		IconKey: "kubernetes_ingress",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/ingress-controller.svg?w=20&h=13",
+	},
+	"nodeCgroups": {
+		IconKey: "kubernetes_container_runtime",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/container-runtime.svg?w=23&h=16",
+	},
+}
+
+// setDefaultAnalyzerIcon sets the icon of the analyzer kind on a result that has neither an icon key nor a URI, so
+// that a result never ends up with the key of one icon and the URI of another
+func setDefaultAnalyzerIcon(result *AnalyzeResult, kind string) {
+	if result.IconKey != "" || result.IconURI != "" {
+		return
+	}
+
+	icon, ok := defaultAnalyzerIcons[kind]
+	if !ok {
+		return
+	}
+	result.IconKey = icon.IconKey
+	result.IconURI = icon.IconURI
+}
@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.undefinedlabs.com/scopeagent"
+)
+
+func Test_setDefaultAnalyzerIcon(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     string
+		result   AnalyzeResult
+		expected AnalyzeResult
+	}{
+		{
+			name:   "no icon",
+			kind:   "nodeResources",
+			result: AnalyzeResult{Title: "Node Count"},
+			expected: AnalyzeResult{
+				Title:   "Node Count",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:     "own icon",
+			kind:     "nodeResources",
+			result:   AnalyzeResult{IconKey: "custom"},
+			expected: AnalyzeResult{IconKey: "custom"},
+		},
+		{
+			name:     "unknown kind",
+			kind:     "unknown",
+			result:   AnalyzeResult{},
+			expected: AnalyzeResult{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+
+			result := test.result
+			setDefaultAnalyzerIcon(&result, test.kind)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
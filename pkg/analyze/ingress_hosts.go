@@ -0,0 +1,246 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ingressHostsIngress holds the fields of an ingress that are needed to find conflicts. Rules, hosts and paths are
+// the same in networking.k8s.io/v1 and in the older extensions/v1beta1 and networking.k8s.io/v1beta1 versions, only
+// the backends differ.
+type ingressHostsIngress struct {
+	Metadata metav1.ObjectMeta `json:"metadata"`
+	Spec     struct {
+		Rules []struct {
+			Host string `json:"host"`
+			HTTP *struct {
+				Paths []struct {
+					Path    string              `json:"path"`
+					Backend ingressHostsBackend `json:"backend"`
+				} `json:"paths"`
+			} `json:"http"`
+		} `json:"rules"`
+	} `json:"spec"`
+}
+
+// ingressHostsBackend is a backend in either shape, service.name and service.port in networking.k8s.io/v1 or
+// serviceName and servicePort in the beta versions
+type ingressHostsBackend struct {
+	Service *struct {
+		Name string `json:"name"`
+		Port struct {
+			Name   string `json:"name"`
+			Number int32  `json:"number"`
+		} `json:"port"`
+	} `json:"service"`
+	ServiceName string          `json:"serviceName"`
+	ServicePort json.RawMessage `json:"servicePort"`
+}
+
+func (b ingressHostsBackend) String() string {
+	if b.Service != nil {
+		if b.Service.Port.Name != "" {
+			return fmt.Sprintf("%s:%s", b.Service.Name, b.Service.Port.Name)
+		}
+		return fmt.Sprintf("%s:%d", b.Service.Name, b.Service.Port.Number)
+	}
+	if b.ServiceName == "" || len(b.ServicePort) == 0 {
+		return b.ServiceName
+	}
+	// servicePort is either a number or the name of a port
+	return fmt.Sprintf("%s:%s", b.ServiceName, strings.Trim(string(b.ServicePort), `"`))
+}
+
+// ingressHostConflict is a host and path claimed by more than one ingress
+type ingressHostConflict struct {
+	Host string
+	Path string
+	// Ingresses are the conflicting ingresses as namespace/name, with the backend they route to when it is set
+	Ingresses []string
+}
+
+func (c ingressHostConflict) String() string {
+	host := c.Host
+	if host == "" {
+		host = "*"
+	}
+	return fmt.Sprintf("%s%s (%s)", host, c.Path, strings.Join(c.Ingresses, ", "))
+}
+
+type ingressHostsMessageData struct {
+	Count     int
+	Conflicts string
+}
+
+// analyzeIngressHosts fails when more than one ingress, in the same namespace or in different ones, routes the same
+// host and path
+func analyzeIngressHosts(analyzer *troubleshootv1beta2.IngressHostsAnalyze, getFile getCollectedFileContents) (*AnalyzeResult, error) {
+	contents, err := getFile("cluster-resources/ingress.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected ingress")
+	}
+
+	ingresses, err := unmarshalIngressHostsIngresses(contents)
+	if err != nil {
+		return nil, err
+	}
+	conflicts := findIngressHostConflicts(ingresses)
+
+	title := analyzer.CheckName
+	if title == "" {
+		title = "Ingress Hosts"
+	}
+	result := &AnalyzeResult{
+		Title: title,
+	}
+
+	var failOutcome, passOutcome *troubleshootv1beta2.SingleOutcome
+	for _, outcome := range analyzer.Outcomes {
+		if outcome.Fail != nil && failOutcome == nil {
+			failOutcome = outcome.Fail
+		}
+		if outcome.Pass != nil && passOutcome == nil {
+			passOutcome = outcome.Pass
+		}
+	}
+
+	described := []string{}
+	for _, conflict := range conflicts {
+		described = append(described, conflict.String())
+	}
+	data := ingressHostsMessageData{
+		Count:     len(conflicts),
+		Conflicts: strings.Join(described, "; "),
+	}
+	single := passOutcome
+	if len(conflicts) > 0 {
+		result.IsFail = true
+		single = failOutcome
+	} else {
+		result.IsPass = true
+	}
+
+	message := ""
+	if single != nil {
+		message = single.Message
+		result.URI = single.URI
+	}
+	result.Message, err = renderIngressHostsMessage(message, data, result.IsFail)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render message")
+	}
+
+	return result, nil
+}
+
+// unmarshalIngressHostsIngresses accepts a list of ingresses, as collected by clusterResources, or a list object
+// with the ingresses in items, as written by kubectl get ingress -o json
+func unmarshalIngressHostsIngresses(contents []byte) ([]ingressHostsIngress, error) {
+	ingresses := []ingressHostsIngress{}
+	if trimmed := bytes.TrimSpace(contents); len(trimmed) > 0 && trimmed[0] == '{' {
+		list := struct {
+			Items []ingressHostsIngress `json:"items"`
+		}{}
+		if err := json.Unmarshal(contents, &list); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal collected ingress")
+		}
+		return list.Items, nil
+	}
+
+	if err := json.Unmarshal(contents, &ingresses); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal collected ingress")
+	}
+	return ingresses, nil
+}
+
+// findIngressHostConflicts returns the host and path combinations routed by more than one ingress, sorted by host
+// and then path. A rule without a path routes "/".
+func findIngressHostConflicts(ingresses []ingressHostsIngress) []ingressHostConflict {
+	type hostPath struct {
+		host string
+		path string
+	}
+	claims := map[hostPath][]string{}
+	claimedBy := map[hostPath]map[string]bool{}
+	for _, ingress := range ingresses {
+		name := fmt.Sprintf("%s/%s", ingress.Metadata.Namespace, ingress.Metadata.Name)
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, p := range rule.HTTP.Paths {
+				key := hostPath{host: rule.Host, path: p.Path}
+				if key.path == "" {
+					key.path = "/"
+				}
+				if claimedBy[key] == nil {
+					claimedBy[key] = map[string]bool{}
+				}
+				if claimedBy[key][name] {
+					continue
+				}
+				claimedBy[key][name] = true
+
+				claim := name
+				if backend := p.Backend.String(); backend != "" {
+					claim = fmt.Sprintf("%s -> %s", name, backend)
+				}
+				claims[key] = append(claims[key], claim)
+			}
+		}
+	}
+
+	conflicts := []ingressHostConflict{}
+	for key, claim := range claims {
+		if len(claim) < 2 {
+			continue
+		}
+		conflicts = append(conflicts, ingressHostConflict{
+			Host:      key.host,
+			Path:      key.path,
+			Ingresses: claim,
+		})
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].Host != conflicts[j].Host {
+			return conflicts[i].Host < conflicts[j].Host
+		}
+		return conflicts[i].Path < conflicts[j].Path
+	})
+	return conflicts
+}
+
+// renderIngressHostsMessage renders the outcome message as a template. Fail outcomes without a message get a default
+// one.
+func renderIngressHostsMessage(message string, data ingressHostsMessageData, isProblem bool) (string, error) {
+	if message == "" {
+		if !isProblem {
+			return "", nil
+		}
+		return fmt.Sprintf("More than one ingress routes the same host and path: %s", data.Conflicts), nil
+	}
+
+	if !strings.Contains(message, "{{") {
+		return message, nil
+	}
+
+	tmpl, err := template.New("message").Parse(message)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse message template")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", errors.Wrap(err, "failed to execute message template")
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,105 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+)
+
+func Test_analyzeIngressHosts(t *testing.T) {
+	// default/web is networking.k8s.io/v1 and staging/web is extensions/v1beta1
+	v1 := `{"metadata": {"name": "web", "namespace": "default"}, "spec": {"rules": [{"host": "app.example.com", "http": {"paths": [{"path": "/", "pathType": "Prefix", "backend": {"service": {"name": "web", "port": {"number": 80}}}}, {"path": "/api", "pathType": "Prefix", "backend": {"service": {"name": "api", "port": {"name": "http"}}}}]}}]}}`
+	beta := `{"metadata": {"name": "web", "namespace": "staging"}, "spec": {"rules": [{"host": "app.example.com", "http": {"paths": [{"backend": {"serviceName": "web-staging", "servicePort": 8080}}]}}]}}`
+	other := `{"metadata": {"name": "docs", "namespace": "default"}, "spec": {"rules": [{"host": "docs.example.com", "http": {"paths": [{"path": "/", "backend": {"serviceName": "docs", "servicePort": "http"}}]}}]}}`
+
+	outcomes := []*troubleshootv1beta2.Outcome{
+		{
+			Fail: &troubleshootv1beta2.SingleOutcome{
+				URI: "https://kubernetes.io/docs/concepts/services-networking/ingress/",
+			},
+		},
+		{
+			Pass: &troubleshootv1beta2.SingleOutcome{
+				Message: "No ingresses conflict",
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		contents string
+		outcomes []*troubleshootv1beta2.Outcome
+		expected *AnalyzeResult
+	}{
+		{
+			name:     "no conflicts",
+			contents: "[" + v1 + ", " + other + "]",
+			outcomes: outcomes,
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "Ingress Hosts",
+				Message: "No ingresses conflict",
+			},
+		},
+		{
+			name:     "conflict across namespaces and versions",
+			contents: "[" + v1 + ", " + beta + ", " + other + "]",
+			outcomes: outcomes,
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Ingress Hosts",
+				Message: "More than one ingress routes the same host and path: app.example.com/ (default/web -> web:80, staging/web -> web-staging:8080)",
+				URI:     "https://kubernetes.io/docs/concepts/services-networking/ingress/",
+			},
+		},
+		{
+			name:     "list object",
+			contents: `{"apiVersion": "v1", "kind": "List", "items": [` + other + ", " + other + `]}`,
+			outcomes: outcomes,
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "Ingress Hosts",
+				Message: "No ingresses conflict",
+			},
+		},
+		{
+			name:     "templated message",
+			contents: "[" + beta + ", " + v1 + "]",
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{
+					Fail: &troubleshootv1beta2.SingleOutcome{
+						Message: "{{ .Count }} conflicting hosts: {{ .Conflicts }}",
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Ingress Hosts",
+				Message: "1 conflicting hosts: app.example.com/ (staging/web -> web-staging:8080, default/web -> web:80)",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+			req := require.New(t)
+
+			getFile := func(string) ([]byte, error) {
+				return []byte(test.contents), nil
+			}
+
+			analyzer := &troubleshootv1beta2.IngressHostsAnalyze{
+				Outcomes: test.outcomes,
+			}
+			actual, err := analyzeIngressHosts(analyzer, getFile)
+			req.NoError(err)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
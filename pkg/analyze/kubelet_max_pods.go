@@ -0,0 +1,180 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/analyze/conditional"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// kubeletConfigz is the part of the kubelet configz response that is analyzed
+type kubeletConfigz struct {
+	KubeletConfig struct {
+		MaxPods *int64 `json:"maxPods"`
+	} `json:"kubeletconfig"`
+}
+
+type kubeletMaxPodsMessageData struct {
+	NodeName         string
+	MaxPods          int64
+	AllocatablePods  int64
+	UnreachableNodes string
+}
+
+// analyzeKubeletMaxPods compares the maxPods of each kubelet's configz with the pods the node reports as
+// allocatable. It fails when they differ by more than the tolerance on any node, and warns when the kubelet config
+// of some nodes was not collected.
+func analyzeKubeletMaxPods(analyzer *troubleshootv1beta2.KubeletMaxPodsAnalyze, getObject getCollectedObject, findFiles getChildCollectedFileContents) (*AnalyzeResult, error) {
+	tolerance, err := conditional.ParseTolerance(analyzer.Tolerance)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []corev1.Node{}
+	if err := getObject("cluster-resources/nodes.json", &nodes); err != nil {
+		return nil, errors.Wrap(err, "failed to get node list")
+	}
+	// the cached node list is shared with other analyzers, so sort a copy
+	nodes = append([]corev1.Node{}, nodes...)
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	maxPods, err := getKubeletMaxPods(findFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	title := analyzer.CheckName
+	if title == "" {
+		title = "Kubelet Max Pods"
+	}
+	result := &AnalyzeResult{
+		Title: title,
+	}
+
+	var diverged *kubeletMaxPodsMessageData
+	unreachableNodes := []string{}
+	for _, node := range nodes {
+		configured, ok := maxPods[node.Name]
+		if !ok {
+			unreachableNodes = append(unreachableNodes, node.Name)
+			continue
+		}
+
+		allocatable := node.Status.Allocatable.Pods().Value()
+		if !tolerance.Within(float64(allocatable), float64(configured)) {
+			diverged = &kubeletMaxPodsMessageData{
+				NodeName:        node.Name,
+				MaxPods:         configured,
+				AllocatablePods: allocatable,
+			}
+			break
+		}
+	}
+
+	var failOutcome, warnOutcome, passOutcome *troubleshootv1beta2.SingleOutcome
+	for _, outcome := range analyzer.Outcomes {
+		if outcome.Fail != nil && failOutcome == nil {
+			failOutcome = outcome.Fail
+		}
+		if outcome.Warn != nil && warnOutcome == nil {
+			warnOutcome = outcome.Warn
+		}
+		if outcome.Pass != nil && passOutcome == nil {
+			passOutcome = outcome.Pass
+		}
+	}
+
+	data := kubeletMaxPodsMessageData{}
+	single := passOutcome
+	switch {
+	case diverged != nil:
+		result.IsFail = true
+		data = *diverged
+		single = failOutcome
+	case len(unreachableNodes) > 0:
+		result.IsWarn = true
+		data.UnreachableNodes = strings.Join(unreachableNodes, ", ")
+		single = warnOutcome
+	default:
+		result.IsPass = true
+	}
+
+	message := ""
+	if single != nil {
+		message = single.Message
+		result.URI = single.URI
+	}
+	result.Message, err = renderKubeletMaxPodsMessage(message, data, !result.IsPass)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render message")
+	}
+
+	return result, nil
+}
+
+// getKubeletMaxPods reads the maxPods of the kubelet-config/<node>.json files written by the kubeletConfig
+// collector. Nodes whose config does not report maxPods are left out.
+func getKubeletMaxPods(findFiles getChildCollectedFileContents) (map[string]int64, error) {
+	files, err := findFiles("kubelet-config/*.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find kubelet config files")
+	}
+
+	maxPods := map[string]int64{}
+	for fileName, contents := range files {
+		if filepath.Base(fileName) == "errors.json" {
+			continue
+		}
+
+		configz := kubeletConfigz{}
+		if err := json.Unmarshal(contents, &configz); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", fileName)
+		}
+		if configz.KubeletConfig.MaxPods == nil {
+			continue
+		}
+		maxPods[strings.TrimSuffix(filepath.Base(fileName), ".json")] = *configz.KubeletConfig.MaxPods
+	}
+
+	return maxPods, nil
+}
+
+// renderKubeletMaxPodsMessage renders the outcome message as a template. Fail and warn outcomes without a message
+// get a default one.
+func renderKubeletMaxPodsMessage(message string, data kubeletMaxPodsMessageData, isProblem bool) (string, error) {
+	if message == "" {
+		if !isProblem {
+			return "", nil
+		}
+		if data.NodeName != "" {
+			return fmt.Sprintf("Node %s has %d allocatable pods, but the kubelet is configured with maxPods %d", data.NodeName, data.AllocatablePods, data.MaxPods), nil
+		}
+		return fmt.Sprintf("Unable to read the kubelet config of %s", data.UnreachableNodes), nil
+	}
+
+	if !strings.Contains(message, "{{") {
+		return message, nil
+	}
+
+	tmpl, err := template.New("message").Parse(message)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse message template")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", errors.Wrap(err, "failed to execute message template")
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,134 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_analyzeKubeletMaxPods(t *testing.T) {
+	node := func(name string, pods string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourcePods: resource.MustParse(pods),
+				},
+			},
+		}
+	}
+
+	outcomes := []*troubleshootv1beta2.Outcome{
+		{
+			Fail: &troubleshootv1beta2.SingleOutcome{
+				URI: "https://kubernetes.io/docs/reference/config-api/kubelet-config.v1beta1/",
+			},
+		},
+		{
+			Warn: &troubleshootv1beta2.SingleOutcome{
+				Message: "Could not check maxPods on {{ .UnreachableNodes }}",
+			},
+		},
+		{
+			Pass: &troubleshootv1beta2.SingleOutcome{
+				Message: "maxPods matches the allocatable pods of every node",
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		nodes     []corev1.Node
+		configs   map[string][]byte
+		tolerance string
+		expected  *AnalyzeResult
+	}{
+		{
+			name:  "matching",
+			nodes: []corev1.Node{node("node-b", "110"), node("node-a", "250")},
+			configs: map[string][]byte{
+				"kubelet-config/node-a.json": []byte(`{"kubeletconfig": {"maxPods": 250}}`),
+				"kubelet-config/node-b.json": []byte(`{"kubeletconfig": {"maxPods": 110}}`),
+			},
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "Kubelet Max Pods",
+				Message: "maxPods matches the allocatable pods of every node",
+			},
+		},
+		{
+			name:  "diverged",
+			nodes: []corev1.Node{node("node-a", "100"), node("node-b", "110")},
+			configs: map[string][]byte{
+				"kubelet-config/node-a.json": []byte(`{"kubeletconfig": {"maxPods": 110}}`),
+				"kubelet-config/node-b.json": []byte(`{"kubeletconfig": {"maxPods": 110}}`),
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Kubelet Max Pods",
+				Message: "Node node-a has 100 allocatable pods, but the kubelet is configured with maxPods 110",
+				URI:     "https://kubernetes.io/docs/reference/config-api/kubelet-config.v1beta1/",
+			},
+		},
+		{
+			name:      "within tolerance",
+			nodes:     []corev1.Node{node("node-a", "100")},
+			tolerance: "10%",
+			configs: map[string][]byte{
+				"kubelet-config/node-a.json": []byte(`{"kubeletconfig": {"maxPods": 110}}`),
+			},
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "Kubelet Max Pods",
+				Message: "maxPods matches the allocatable pods of every node",
+			},
+		},
+		{
+			name:  "configz unreachable",
+			nodes: []corev1.Node{node("node-a", "110"), node("node-b", "110"), node("node-c", "110")},
+			configs: map[string][]byte{
+				"kubelet-config/node-a.json": []byte(`{"kubeletconfig": {"maxPods": 110}}`),
+				"kubelet-config/node-b.json": []byte(`{"kubeletconfig": {}}`),
+				"kubelet-config/errors.json": []byte(`["failed to get configz of node node-c: the server could not find the requested resource"]`),
+			},
+			expected: &AnalyzeResult{
+				IsWarn:  true,
+				Title:   "Kubelet Max Pods",
+				Message: "Could not check maxPods on node-b, node-c",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+			req := require.New(t)
+
+			nodes, err := json.Marshal(test.nodes)
+			req.NoError(err)
+			cache := NewCollectedObjectCache(func(string) ([]byte, error) {
+				return nodes, nil
+			})
+			findFiles := func(string) (map[string][]byte, error) {
+				return test.configs, nil
+			}
+
+			analyzer := &troubleshootv1beta2.KubeletMaxPodsAnalyze{
+				Outcomes:  outcomes,
+				Tolerance: test.tolerance,
+			}
+			actual, err := analyzeKubeletMaxPods(analyzer, cache.GetCollectedObject, findFiles)
+			req.NoError(err)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package analyzer
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/analyze/conditional"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// LintAnalyzer returns warnings for analyzer specs that are valid but are likely mistakes, such as nodeResources
+// outcomes whose conditional can never fail, like "count() >= 0", or can never match, like "count() < 0". These are
+// not errors, the analyzer still runs.
+func LintAnalyzer(analyzer *troubleshootv1beta2.Analyze) []error {
+	warnings := []error{}
+
+	if analyzer.NodeResources != nil {
+		warnings = append(warnings, lintNodeResourcesOutcomes("outcomes", analyzer.NodeResources.Outcomes)...)
+		warnings = append(warnings, lintNodeResourcesOutcomes("onInstall", analyzer.NodeResources.OnInstall)...)
+		warnings = append(warnings, lintNodeResourcesOutcomes("onUpdate", analyzer.NodeResources.OnUpdate)...)
+	}
+
+	return warnings
+}
+
+func lintNodeResourcesOutcomes(field string, outcomes []*troubleshootv1beta2.Outcome) []error {
+	warnings := []error{}
+	for i, outcome := range outcomes {
+		for _, single := range []struct {
+			name    string
+			outcome *troubleshootv1beta2.SingleOutcome
+		}{
+			{name: "fail", outcome: outcome.Fail},
+			{name: "warn", outcome: outcome.Warn},
+			{name: "pass", outcome: outcome.Pass},
+		} {
+			if single.outcome == nil {
+				continue
+			}
+			when, isNegated, err := nodeResourcesOutcomeConditional(single.outcome)
+			if err != nil || when == "" {
+				// problems are reported by ValidateAnalyzer, and outcomes without a conditional are meant to match
+				continue
+			}
+			conditionalField := "when"
+			if isNegated {
+				conditionalField = "whenNot"
+			}
+
+			isAlwaysTrue, ok := isConstantNodeResourceConditional(when)
+			if !ok {
+				continue
+			}
+			matches := "never matches"
+			if isAlwaysTrue != isNegated {
+				matches = "always matches"
+			}
+			warnings = append(warnings, errors.Errorf("%s[%d].%s.%s %q %s", field, i, single.name, conditionalField, when, matches))
+		}
+	}
+	return warnings
+}
+
+// isConstantNodeResourceConditional reports whether the conditional is true, or false, however many nodes match
+// and whatever their resources. The second result is false when the conditional depends on the nodes. Conditionals
+// combined with || and && are constant when the ones that decide the result are, e.g. "count() >= 0 || ..." is
+// always true.
+func isConstantNodeResourceConditional(when string) (bool, bool) {
+	groups, err := conditional.SplitLogical(when)
+	if err != nil {
+		return false, false
+	}
+
+	isAnyUnknown := false
+	for _, group := range groups {
+		isGroupTrue, isGroupConstant := true, true
+		for _, single := range group {
+			isTrue, ok := isConstantNodeResourceComparison(single)
+			if ok && !isTrue {
+				isGroupTrue, isGroupConstant = false, true
+				break
+			}
+			if !ok {
+				isGroupConstant = false
+			}
+		}
+
+		if isGroupConstant && isGroupTrue {
+			return true, true
+		}
+		if !isGroupConstant {
+			isAnyUnknown = true
+		}
+	}
+	if isAnyUnknown {
+		return false, false
+	}
+	return false, true
+}
+
+// isConstantNodeResourceComparison is isConstantNodeResourceConditional for a conditional that is not combined.
+// Only conditionals comparing an expression to literals are checked, using the range of values the expression can
+// have.
+func isConstantNodeResourceComparison(when string) (bool, bool) {
+	parts := conditional.Split(strings.TrimSpace(when))
+	if len(parts) == 2 {
+		parts = append([]string{"count()"}, parts...)
+	}
+	if len(parts) < 3 {
+		return false, false
+	}
+
+	lower, upper, ok := nodeResourceExpressionRange(parts[0])
+	if !ok {
+		return false, false
+	}
+
+	if parts[1] == "between" {
+		if len(parts) != 4 {
+			return false, false
+		}
+		lowerBound, ok := nodeResourceLiteral(parts[2])
+		if !ok {
+			return false, false
+		}
+		upperBound, ok := nodeResourceLiteral(parts[3])
+		if !ok {
+			return false, false
+		}
+		if lowerBound > upperBound || upperBound < lower || lowerBound > upper {
+			return false, true
+		}
+		if lowerBound <= lower && upperBound >= upper {
+			return true, true
+		}
+		return false, false
+	}
+
+	if len(parts) != 3 {
+		return false, false
+	}
+	desired, ok := nodeResourceLiteral(parts[2])
+	if !ok {
+		return false, false
+	}
+
+	switch parts[1] {
+	case ">=":
+		if desired <= lower {
+			return true, true
+		}
+		if desired > upper {
+			return false, true
+		}
+	case ">":
+		if desired < lower {
+			return true, true
+		}
+		if desired >= upper {
+			return false, true
+		}
+	case "<=":
+		if desired >= upper {
+			return true, true
+		}
+		if desired < lower {
+			return false, true
+		}
+	case "<":
+		if desired > upper {
+			return true, true
+		}
+		if desired <= lower {
+			return false, true
+		}
+	case "=", "==", "===":
+		if desired < lower || desired > upper {
+			return false, true
+		}
+	case "!=", "<>":
+		if desired < lower || desired > upper {
+			return true, true
+		}
+	}
+
+	return false, false
+}
+
+// nodeResourceExpressionRange returns the lowest and highest values an expression can have. Node counts and the
+// resources that nodes report cannot be negative, and percent() is at most 100. Annotations can hold any value, so
+// their range is unknown.
+func nodeResourceExpressionRange(expression string) (float64, float64, bool) {
+	match := conditional.ExpressionRegex.FindStringSubmatch(expression)
+	if match == nil {
+		return 0, 0, false
+	}
+
+	function := match[1]
+	property := strings.TrimSpace(match[2])
+	switch function {
+	case "count":
+		if property != "" {
+			return 0, 0, false
+		}
+		return 0, math.Inf(1), true
+	case "countAll":
+		return 0, math.Inf(1), true
+	case "percent":
+		return 0, 100, true
+	case "min", "max", "sum", "avg", "median":
+	case "percentile":
+		percentileProperty, _, err := parsePercentileArguments(property)
+		if err != nil {
+			return 0, 0, false
+		}
+		property = percentileProperty
+	default:
+		return 0, 0, false
+	}
+
+	if annotationPropertyRegex.MatchString(property) || !isKnownNodeResourceProperty(property) {
+		return 0, 0, false
+	}
+	return 0, math.Inf(1), true
+}
+
+// nodeResourceLiteral parses a desired value that is a number or a quantity
+func nodeResourceLiteral(value string) (float64, bool) {
+	if parsed, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64); err == nil {
+		return parsed, true
+	}
+	parsed, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, false
+	}
+	return float64(parsed.MilliValue()) / 1000, true
+}
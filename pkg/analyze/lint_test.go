@@ -0,0 +1,133 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+)
+
+func TestLintAnalyzer(t *testing.T) {
+	nodeResources := func(when string) *troubleshootv1beta2.Analyze {
+		return &troubleshootv1beta2.Analyze{
+			NodeResources: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When: when,
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.Analyze
+		expected []string
+	}{
+		{
+			name:     "depends on nodes",
+			analyzer: nodeResources("count() < 3"),
+			expected: []string{},
+		},
+		{
+			name:     "count compared to a negative number",
+			analyzer: nodeResources("count() > -1"),
+			expected: []string{`outcomes[0].fail.when "count() > -1" always matches`},
+		},
+		{
+			name:     "count less than zero",
+			analyzer: nodeResources("count() < 0"),
+			expected: []string{`outcomes[0].fail.when "count() < 0" never matches`},
+		},
+		{
+			name:     "implicit count",
+			analyzer: nodeResources(">= 0"),
+			expected: []string{`outcomes[0].fail.when ">= 0" always matches`},
+		},
+		{
+			name:     "percent above 100",
+			analyzer: nodeResources("percent() > 100"),
+			expected: []string{`outcomes[0].fail.when "percent() > 100" never matches`},
+		},
+		{
+			name:     "quantity",
+			analyzer: nodeResources("min(memoryCapacity) >= 0Gi"),
+			expected: []string{`outcomes[0].fail.when "min(memoryCapacity) >= 0Gi" always matches`},
+		},
+		{
+			name:     "empty between",
+			analyzer: nodeResources("count() between 5 3"),
+			expected: []string{`outcomes[0].fail.when "count() between 5 3" never matches`},
+		},
+		{
+			name:     "annotations can be negative",
+			analyzer: nodeResources("min(annotation(example.com/offset)) < 0"),
+			expected: []string{},
+		},
+		{
+			name:     "factor",
+			analyzer: nodeResources("count() * 2 >= 0"),
+			expected: []string{},
+		},
+		{
+			name:     "combined with a conditional that always matches",
+			analyzer: nodeResources("count() >= 0 || sum(cpuCapacity) < 4"),
+			expected: []string{`outcomes[0].fail.when "count() >= 0 || sum(cpuCapacity) < 4" always matches`},
+		},
+		{
+			name:     "combined with a conditional that never matches",
+			analyzer: nodeResources("count() < 0 && sum(cpuCapacity) < 4"),
+			expected: []string{`outcomes[0].fail.when "count() < 0 && sum(cpuCapacity) < 4" never matches`},
+		},
+		{
+			name:     "combined conditionals that depend on nodes",
+			analyzer: nodeResources("count() < 0 || sum(cpuCapacity) < 4"),
+			expected: []string{},
+		},
+		{
+			name:     "invalid conditional is left to validation",
+			analyzer: nodeResources("coutn() < 0"),
+			expected: []string{},
+		},
+		{
+			name: "whenNot",
+			analyzer: &troubleshootv1beta2.Analyze{
+				NodeResources: &troubleshootv1beta2.NodeResources{
+					OnInstall: []*troubleshootv1beta2.Outcome{
+						{
+							Warn: &troubleshootv1beta2.SingleOutcome{
+								WhenNot: "count() >= 0",
+							},
+						},
+					},
+				},
+			},
+			expected: []string{`onInstall[0].warn.whenNot "count() >= 0" never matches`},
+		},
+		{
+			name:     "other analyzers",
+			analyzer: &troubleshootv1beta2.Analyze{},
+			expected: []string{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+			req := require.New(t)
+
+			actual := []string{}
+			for _, err := range LintAnalyzer(test.analyzer) {
+				actual = append(actual, err.Error())
+			}
+			req.Equal(test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type nodeCgroupsMessageData struct {
+	// Nodes are the nodes running cgroup v1 with their detected version and container runtime, e.g.
+	// "node-a (cgroup v1, containerd://1.4.3)"
+	Nodes string
+	// UndetectedNodes are the nodes whose cgroup version was not collected or not recognized
+	UndetectedNodes string
+}
+
+// analyzeNodeCgroups fails when any node matching the filters runs cgroup v1, and warns when the cgroup version of
+// some nodes could not be detected. Filter groups are not used.
+func analyzeNodeCgroups(analyzer *troubleshootv1beta2.NodeCgroupsAnalyze, getObject getCollectedObject, findFiles getChildCollectedFileContents) (*AnalyzeResult, error) {
+	nodes := []corev1.Node{}
+	if err := getObject("cluster-resources/nodes.json", &nodes); err != nil {
+		return nil, errors.Wrap(err, "failed to get node list")
+	}
+
+	matchingNodes := []corev1.Node{}
+	for _, node := range nodes {
+		isMatch, err := nodeMatchesFilters(node, analyzer.Filters)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to check if node matches filter")
+		}
+		if isMatch {
+			matchingNodes = append(matchingNodes, node)
+		}
+	}
+	sort.Slice(matchingNodes, func(i, j int) bool {
+		return matchingNodes[i].Name < matchingNodes[j].Name
+	})
+
+	cgroups, err := getNodeCgroups(findFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	title := analyzer.CheckName
+	if title == "" {
+		title = "Node Cgroups"
+	}
+	result := &AnalyzeResult{
+		Title: title,
+	}
+
+	v1Nodes := []string{}
+	undetectedNodes := []string{}
+	for _, node := range matchingNodes {
+		info, ok := cgroups[node.Name]
+		if !ok || info.Version == "" {
+			undetectedNodes = append(undetectedNodes, node.Name)
+			continue
+		}
+		if info.Version != "v1" {
+			continue
+		}
+
+		detected := fmt.Sprintf("cgroup %s", info.Version)
+		if runtime := node.Status.NodeInfo.ContainerRuntimeVersion; runtime != "" {
+			detected = fmt.Sprintf("%s, %s", detected, runtime)
+		}
+		v1Nodes = append(v1Nodes, fmt.Sprintf("%s (%s)", node.Name, detected))
+	}
+
+	var failOutcome, warnOutcome, passOutcome *troubleshootv1beta2.SingleOutcome
+	for _, outcome := range analyzer.Outcomes {
+		if outcome.Fail != nil && failOutcome == nil {
+			failOutcome = outcome.Fail
+		}
+		if outcome.Warn != nil && warnOutcome == nil {
+			warnOutcome = outcome.Warn
+		}
+		if outcome.Pass != nil && passOutcome == nil {
+			passOutcome = outcome.Pass
+		}
+	}
+
+	data := nodeCgroupsMessageData{}
+	single := passOutcome
+	switch {
+	case len(v1Nodes) > 0:
+		result.IsFail = true
+		data.Nodes = strings.Join(v1Nodes, ", ")
+		single = failOutcome
+	case len(undetectedNodes) > 0:
+		result.IsWarn = true
+		data.UndetectedNodes = strings.Join(undetectedNodes, ", ")
+		single = warnOutcome
+	default:
+		result.IsPass = true
+	}
+
+	message := ""
+	if single != nil {
+		message = single.Message
+		result.URI = single.URI
+	}
+	result.Message, err = renderNodeCgroupsMessage(message, data, !result.IsPass)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render message")
+	}
+
+	return result, nil
+}
+
+// getNodeCgroups reads the node-cgroups/<node>.json files written by the nodeCgroups collector
+func getNodeCgroups(findFiles getChildCollectedFileContents) (map[string]collect.NodeCgroupsInfo, error) {
+	files, err := findFiles("node-cgroups/*.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find node cgroups files")
+	}
+
+	cgroups := map[string]collect.NodeCgroupsInfo{}
+	for fileName, contents := range files {
+		info := collect.NodeCgroupsInfo{}
+		if err := json.Unmarshal(contents, &info); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", fileName)
+		}
+		cgroups[strings.TrimSuffix(filepath.Base(fileName), ".json")] = info
+	}
+
+	return cgroups, nil
+}
+
+// renderNodeCgroupsMessage renders the outcome message as a template. Fail and warn outcomes without a message get
+// a default one.
+func renderNodeCgroupsMessage(message string, data nodeCgroupsMessageData, isProblem bool) (string, error) {
+	if message == "" {
+		if !isProblem {
+			return "", nil
+		}
+		if data.Nodes != "" {
+			return fmt.Sprintf("Nodes are running cgroup v1, cgroup v2 is required: %s", data.Nodes), nil
+		}
+		return fmt.Sprintf("Unable to detect the cgroup version of %s", data.UndetectedNodes), nil
+	}
+
+	if !strings.Contains(message, "{{") {
+		return message, nil
+	}
+
+	tmpl, err := template.New("message").Parse(message)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse message template")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", errors.Wrap(err, "failed to execute message template")
+	}
+
+	return buf.String(), nil
+}
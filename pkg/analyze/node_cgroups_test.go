@@ -0,0 +1,164 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_analyzeNodeCgroups(t *testing.T) {
+	node := func(name string, runtime string, pool string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"pool": pool},
+			},
+			Status: corev1.NodeStatus{
+				NodeInfo: corev1.NodeSystemInfo{
+					ContainerRuntimeVersion: runtime,
+				},
+			},
+		}
+	}
+	nodes := []corev1.Node{
+		node("node-b", "docker://19.3.11", "app"),
+		node("node-a", "containerd://1.4.3", "app"),
+		node("node-c", "containerd://1.4.3", "db"),
+	}
+
+	outcomes := []*troubleshootv1beta2.Outcome{
+		{
+			Fail: &troubleshootv1beta2.SingleOutcome{
+				URI: "https://kubernetes.io/docs/concepts/architecture/cgroups/",
+			},
+		},
+		{
+			Warn: &troubleshootv1beta2.SingleOutcome{},
+		},
+		{
+			Pass: &troubleshootv1beta2.SingleOutcome{
+				Message: "All nodes are running cgroup v2",
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		cgroups  map[string][]byte
+		filters  *troubleshootv1beta2.NodeResourceFilters
+		outcomes []*troubleshootv1beta2.Outcome
+		expected *AnalyzeResult
+	}{
+		{
+			name: "cgroup v2",
+			cgroups: map[string][]byte{
+				"node-cgroups/node-a.json": []byte(`{"filesystem": "cgroup2fs", "version": "v2"}`),
+				"node-cgroups/node-b.json": []byte(`{"filesystem": "cgroup2fs", "version": "v2"}`),
+				"node-cgroups/node-c.json": []byte(`{"filesystem": "cgroup2fs", "version": "v2"}`),
+			},
+			outcomes: outcomes,
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "Node Cgroups",
+				Message: "All nodes are running cgroup v2",
+			},
+		},
+		{
+			name: "cgroup v1",
+			cgroups: map[string][]byte{
+				"node-cgroups/node-a.json": []byte(`{"filesystem": "tmpfs", "version": "v1"}`),
+				"node-cgroups/node-b.json": []byte(`{"filesystem": "tmpfs", "version": "v1"}`),
+				"node-cgroups/node-c.json": []byte(`{"filesystem": "cgroup2fs", "version": "v2"}`),
+			},
+			outcomes: outcomes,
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Node Cgroups",
+				Message: "Nodes are running cgroup v1, cgroup v2 is required: node-a (cgroup v1, containerd://1.4.3), node-b (cgroup v1, docker://19.3.11)",
+				URI:     "https://kubernetes.io/docs/concepts/architecture/cgroups/",
+			},
+		},
+		{
+			name: "filtered out",
+			cgroups: map[string][]byte{
+				"node-cgroups/node-a.json": []byte(`{"filesystem": "cgroup2fs", "version": "v2"}`),
+				"node-cgroups/node-b.json": []byte(`{"filesystem": "cgroup2fs", "version": "v2"}`),
+				"node-cgroups/node-c.json": []byte(`{"filesystem": "tmpfs", "version": "v1"}`),
+			},
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Selector: &troubleshootv1beta2.NodeResourceSelectors{
+					MatchLabel: map[string]string{"pool": "app"},
+				},
+			},
+			outcomes: outcomes,
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "Node Cgroups",
+				Message: "All nodes are running cgroup v2",
+			},
+		},
+		{
+			name: "undetected",
+			cgroups: map[string][]byte{
+				"node-cgroups/node-a.json": []byte(`{"filesystem": "cgroup2fs", "version": "v2"}`),
+				"node-cgroups/node-b.json": []byte(`{"filesystem": "UNKNOWN (0x1234)", "version": ""}`),
+			},
+			outcomes: outcomes,
+			expected: &AnalyzeResult{
+				IsWarn:  true,
+				Title:   "Node Cgroups",
+				Message: "Unable to detect the cgroup version of node-b, node-c",
+			},
+		},
+		{
+			name: "templated message",
+			cgroups: map[string][]byte{
+				"node-cgroups/node-a.json": []byte(`{"filesystem": "tmpfs", "version": "v1"}`),
+			},
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{
+					Fail: &troubleshootv1beta2.SingleOutcome{
+						Message: "Upgrade {{ .Nodes }} to cgroup v2",
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Node Cgroups",
+				Message: "Upgrade node-a (cgroup v1, containerd://1.4.3) to cgroup v2",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+			req := require.New(t)
+
+			contents, err := json.Marshal(nodes)
+			req.NoError(err)
+			cache := NewCollectedObjectCache(func(string) ([]byte, error) {
+				return contents, nil
+			})
+			findFiles := func(string) (map[string][]byte, error) {
+				return test.cgroups, nil
+			}
+
+			analyzer := &troubleshootv1beta2.NodeCgroupsAnalyze{
+				Outcomes: test.outcomes,
+				Filters:  test.filters,
+			}
+			actual, err := analyzeNodeCgroups(analyzer, cache.GetCollectedObject, findFiles)
+			req.NoError(err)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// analyzeNodeOS matches an outcome when any node satisfies its conditional, so that a single node running an
+// unsupported operating system or runtime is enough to fail
+func analyzeNodeOS(analyzer *troubleshootv1beta2.NodeOS, getObject getCollectedObject) (*AnalyzeResult, error) {
+	nodes := []corev1.Node{}
+	if err := getObject("cluster-resources/nodes.json", &nodes); err != nil {
+		return nil, errors.Wrap(err, "failed to get node list")
+	}
+
+	title := analyzer.CheckName
+	if title == "" {
+		title = "Node Operating System"
+	}
+	result := &AnalyzeResult{
+		Title:   title,
+		IconKey: "kubernetes_node_os",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+	}
+
+	// ordering is important for passthrough
+	for _, outcome := range analyzer.Outcomes {
+		if outcome.Fail != nil {
+			isMatch, err := anyNodeMatchesOSConditional(outcome.Fail.When, nodes)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to compare node os conditional")
+			}
+
+			if isMatch {
+				result.IsFail = true
+				result.Message = outcome.Fail.Message
+				result.URI = outcome.Fail.URI
+
+				return result, nil
+			}
+		} else if outcome.Warn != nil {
+			isMatch, err := anyNodeMatchesOSConditional(outcome.Warn.When, nodes)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to compare node os conditional")
+			}
+
+			if isMatch {
+				result.IsWarn = true
+				result.Message = outcome.Warn.Message
+				result.URI = outcome.Warn.URI
+
+				return result, nil
+			}
+		} else if outcome.Pass != nil {
+			isMatch, err := anyNodeMatchesOSConditional(outcome.Pass.When, nodes)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to compare node os conditional")
+			}
+
+			if isMatch {
+				result.IsPass = true
+				result.Message = outcome.Pass.Message
+				result.URI = outcome.Pass.URI
+
+				return result, nil
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func anyNodeMatchesOSConditional(conditional string, nodes []corev1.Node) (bool, error) {
+	if conditional == "" {
+		return true, nil
+	}
+
+	for _, node := range nodes {
+		isMatch, err := compareNodeOSConditionalToActual(conditional, node.Status.NodeInfo)
+		if err != nil {
+			return false, err
+		}
+		if isMatch {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// compareNodeOSConditionalToActual evaluates a conditional such as "osImage =~ ^Ubuntu 20\.04" against the node info.
+// The value is everything after the operator, so it may contain spaces. =~ and !~ match the value as a regex.
+func compareNodeOSConditionalToActual(conditional string, nodeInfo corev1.NodeSystemInfo) (bool, error) {
+	parts := strings.SplitN(strings.TrimSpace(conditional), " ", 3)
+	if len(parts) != 3 {
+		return false, errors.New("unable to parse node os conditional")
+	}
+
+	var actual string
+	switch parts[0] {
+	case "osImage":
+		actual = nodeInfo.OSImage
+	case "kernelVersion":
+		actual = nodeInfo.KernelVersion
+	case "containerRuntimeVersion":
+		actual = nodeInfo.ContainerRuntimeVersion
+	case "kubeletVersion":
+		actual = nodeInfo.KubeletVersion
+	case "operatingSystem":
+		actual = nodeInfo.OperatingSystem
+	case "architecture":
+		actual = nodeInfo.Architecture
+	default:
+		return false, errors.Errorf("unknown node os property %q", parts[0])
+	}
+
+	expected := strings.TrimSpace(parts[2])
+
+	switch parts[1] {
+	case "=", "==", "===":
+		return actual == expected, nil
+	case "!=", "!==":
+		return actual != expected, nil
+	case "=~", "!~":
+		re, err := regexp.Compile(expected)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to compile regex %q", expected)
+		}
+		return re.MatchString(actual) == (parts[1] == "=~"), nil
+	}
+
+	return false, errors.Errorf("unexpected operator %q in node os conditional", parts[1])
+}
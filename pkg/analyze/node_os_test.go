@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_compareNodeOSConditionalToActual(t *testing.T) {
+	nodeInfo := corev1.NodeSystemInfo{
+		OSImage:                 "Ubuntu 20.04.1 LTS",
+		KernelVersion:           "5.4.0-1029-aws",
+		ContainerRuntimeVersion: "containerd://1.3.7",
+		KubeletVersion:          "v1.19.3",
+		OperatingSystem:         "linux",
+		Architecture:            "amd64",
+	}
+
+	tests := []struct {
+		name        string
+		conditional string
+		expected    bool
+		isError     bool
+	}{
+		{
+			name:        "os image with spaces",
+			conditional: "osImage == Ubuntu 20.04.1 LTS",
+			expected:    true,
+		},
+		{
+			name:        "os image family regex",
+			conditional: `osImage =~ ^(Red Hat|CentOS|Flatcar)`,
+			expected:    false,
+		},
+		{
+			name:        "supported runtime regex",
+			conditional: `containerRuntimeVersion !~ ^containerd://1\.[4-9]\.`,
+			expected:    true,
+		},
+		{
+			name:        "kernel version",
+			conditional: "kernelVersion != 5.4.0-1029-aws",
+			expected:    false,
+		},
+		{
+			name:        "architecture",
+			conditional: "architecture = amd64",
+			expected:    true,
+		},
+		{
+			name:        "unknown property",
+			conditional: "distro == ubuntu",
+			isError:     true,
+		},
+		{
+			name:        "invalid regex",
+			conditional: "osImage =~ (",
+			isError:     true,
+		},
+		{
+			name:        "missing value",
+			conditional: "osImage ==",
+			isError:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+			req := require.New(t)
+
+			actual, err := compareNodeOSConditionalToActual(test.conditional, nodeInfo)
+			if test.isError {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func Test_analyzeNodeOS(t *testing.T) {
+	scopetest := scopeagent.StartTest(t)
+	defer scopetest.End()
+	req := require.New(t)
+
+	nodes := []corev1.Node{
+		{Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{OSImage: "Ubuntu 20.04.1 LTS", ContainerRuntimeVersion: "containerd://1.4.1"}}},
+		{Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{OSImage: "Flatcar Container Linux by Kinvolk 2605.7.0 (Oklo)", ContainerRuntimeVersion: "docker://19.3.12"}}},
+	}
+	getCollectedFileContents := func(name string) ([]byte, error) {
+		if name == "cluster-resources/nodes.json" {
+			return json.Marshal(nodes)
+		}
+		return nil, errors.Errorf("file %s was not collected", name)
+	}
+
+	analyzer := &troubleshootv1beta2.NodeOS{
+		Outcomes: []*troubleshootv1beta2.Outcome{
+			{
+				Fail: &troubleshootv1beta2.SingleOutcome{
+					When:    `osImage !~ ^(Ubuntu|Red Hat|Flatcar)`,
+					Message: "Unsupported operating system",
+				},
+			},
+			{
+				Warn: &troubleshootv1beta2.SingleOutcome{
+					When:    `containerRuntimeVersion !~ ^containerd://`,
+					Message: "Docker is deprecated",
+				},
+			},
+			{
+				Pass: &troubleshootv1beta2.SingleOutcome{
+					Message: "Supported",
+				},
+			},
+		},
+	}
+
+	actual, err := analyzeNodeOS(analyzer, NewCollectedObjectCache(getCollectedFileContents).GetCollectedObject)
+	req.NoError(err)
+
+	assert.Equal(t, &AnalyzeResult{
+		IsWarn:  true,
+		Title:   "Node Operating System",
+		Message: "Docker is deprecated",
+		IconKey: "kubernetes_node_os",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+	}, actual)
+}
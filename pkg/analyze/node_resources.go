@@ -1,29 +1,204 @@
 package analyzer
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"math"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/analyze/conditional"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
-func analyzeNodeResources(analyzer *troubleshootv1beta2.NodeResources, getCollectedFileContents func(string) ([]byte, error)) (*AnalyzeResult, error) {
-	collected, err := getCollectedFileContents("cluster-resources/nodes.json")
+// analyzeNodeResources evaluates the outcomes against the matching nodes. With filter groups, such as one per node
+// pool, the outcomes are evaluated against the matching nodes of each group, returning one result per group.
+func analyzeNodeResources(ctx context.Context, analyzer *troubleshootv1beta2.NodeResources, getCollectedFileContents func(string) ([]byte, error), getObject getCollectedObject) ([]*AnalyzeResult, error) {
+	if err := validateNodeResourcesProperties(analyzer); err != nil {
+		return nil, err
+	}
+
+	nodes, err := getNodeResourcesNodes(getObject)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get contents of nodes.json")
+		return nil, err
+	}
+
+	outcomes, err := selectNodeResourcesOutcomes(analyzer, getCollectedFileContents)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select outcomes")
+	}
+	if outcomes == nil {
+		return []*AnalyzeResult{skippedNodeResourcesResult(newNodeResourcesResult(nodeResourcesTitle(analyzer)))}, nil
+	}
+
+	return evaluateNodeResources(ctx, nodes, analyzer, outcomes)
+}
+
+// EvaluateNodeResources evaluates the outcomes of a nodeResources analyzer against nodes that have already been
+// loaded, for callers that do not work with a collected bundle. It returns one result, or one per filter group.
+// There are no collected workloads to check, so onInstall and onUpdate are ignored and the outcomes are always used,
+// and properties read from collected usage, such as cpuRequests, only have values if the nodes carry the
+// annotations that the analyzer would otherwise add.
+func EvaluateNodeResources(nodes []corev1.Node, analyzer *troubleshootv1beta2.NodeResources) ([]*AnalyzeResult, error) {
+	if analyzer == nil {
+		return nil, errors.New("analyzer is required")
+	}
+	if err := validateNodeResourcesProperties(analyzer); err != nil {
+		return nil, err
+	}
+
+	return evaluateNodeResources(context.Background(), nodes, analyzer, analyzer.Outcomes)
+}
+
+// evaluateNodeResources evaluates the outcomes against the matching nodes of each group, stopping once ctx is done
+func evaluateNodeResources(ctx context.Context, nodes []corev1.Node, analyzer *troubleshootv1beta2.NodeResources, outcomes []*troubleshootv1beta2.Outcome) ([]*AnalyzeResult, error) {
+	groups, err := getNodeResourcesGroups(analyzer, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	title := nodeResourcesTitle(analyzer)
+
+	results := []*AnalyzeResult{}
+	for _, group := range groups {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result := newNodeResourcesResult(title)
+		if group.name != "" {
+			result.Title = fmt.Sprintf("%s (%s)", title, group.name)
+		}
+
+		result, err = evaluateNodeResourcesOutcomes(result, outcomes, analyzer, group.matchingNodes, len(nodes))
+		if err != nil {
+			if group.name != "" {
+				return nil, errors.Wrapf(err, "failed to evaluate group %s", group.name)
+			}
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// analyzeNodeResourcesPerNode evaluates the outcomes against each matching node on its own,
+// returning one result per node, and per group for the nodes of each filter group, stopping once ctx is done
+func analyzeNodeResourcesPerNode(ctx context.Context, analyzer *troubleshootv1beta2.NodeResources, getCollectedFileContents func(string) ([]byte, error), getObject getCollectedObject) ([]*AnalyzeResult, error) {
+	if err := validateNodeResourcesProperties(analyzer); err != nil {
+		return nil, err
+	}
+
+	nodes, err := getNodeResourcesNodes(getObject)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := getNodeResourcesGroups(analyzer, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	title := nodeResourcesTitle(analyzer)
+
+	outcomes, err := selectNodeResourcesOutcomes(analyzer, getCollectedFileContents)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select outcomes")
+	}
+	if outcomes == nil {
+		return []*AnalyzeResult{skippedNodeResourcesResult(newNodeResourcesResult(title))}, nil
+	}
+
+	results := []*AnalyzeResult{}
+	for _, group := range groups {
+		for _, node := range group.matchingNodes {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			result := newNodeResourcesResult(fmt.Sprintf("%s (%s)", title, node.Name))
+			if group.name != "" {
+				result.Title = fmt.Sprintf("%s (%s, %s)", title, group.name, node.Name)
+			}
+
+			result, err = evaluateNodeResourcesOutcomes(result, outcomes, analyzer, []corev1.Node{node}, len(nodes))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to evaluate node %s", node.Name)
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// nodeResourcesGroup is the name of a filter group and the nodes matching it. The group of an analyzer without
+// filter groups has no name.
+type nodeResourcesGroup struct {
+	name          string
+	matchingNodes []corev1.Node
+}
+
+// getNodeResourcesGroups returns the matching nodes of each filter group. A node is in a group when it matches both
+// the analyzer filters and the selector of the group.
+func getNodeResourcesGroups(analyzer *troubleshootv1beta2.NodeResources, nodes []corev1.Node) ([]nodeResourcesGroup, error) {
+	matchingNodes, err := getMatchingNodes(analyzer, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	if analyzer.Filters == nil || len(analyzer.Filters.Groups) == 0 {
+		return []nodeResourcesGroup{{matchingNodes: matchingNodes}}, nil
+	}
+
+	groups := []nodeResourcesGroup{}
+	for _, filterGroup := range analyzer.Filters.Groups {
+		group := nodeResourcesGroup{
+			name:          filterGroup.Name,
+			matchingNodes: []corev1.Node{},
+		}
+		for _, node := range matchingNodes {
+			isMatch, err := nodeMatchesFilters(node, &troubleshootv1beta2.NodeResourceFilters{Selector: filterGroup.Selector})
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to check if node matches group %s", filterGroup.Name)
+			}
+			if isMatch {
+				group.matchingNodes = append(group.matchingNodes, node)
+			}
+		}
+		groups = append(groups, group)
 	}
 
+	return groups, nil
+}
+
+// getNodeResourcesNodes returns the collected nodes with their usage. The nodes are shared with other analyzers
+// through getObject and must not be modified.
+func getNodeResourcesNodes(getObject getCollectedObject) ([]corev1.Node, error) {
 	nodes := []corev1.Node{}
-	if err := json.Unmarshal(collected, &nodes); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal node list")
+	if err := getObject("cluster-resources/nodes.json", &nodes); err != nil {
+		return nil, errors.Wrap(err, "failed to get node list")
 	}
+	return withNodeUsage(nodes, getObject), nil
+}
 
+// getMatchingNodes returns the nodes matching the analyzer filters
+func getMatchingNodes(analyzer *troubleshootv1beta2.NodeResources, nodes []corev1.Node) ([]corev1.Node, error) {
 	matchingNodes := []corev1.Node{}
 
 	for _, node := range nodes {
@@ -37,112 +212,563 @@ func analyzeNodeResources(analyzer *troubleshootv1beta2.NodeResources, getCollec
 		}
 	}
 
-	title := analyzer.CheckName
-	if title == "" {
-		title = "Node Resources"
+	return matchingNodes, nil
+}
+
+// nodeMetrics is the part of a metrics.k8s.io NodeMetrics that the analyzer uses
+type nodeMetrics struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Usage             corev1.ResourceList `json:"usage"`
+}
+
+const (
+	nodeCPUUsageAnnotation       = "troubleshoot.sh/cpu-usage"
+	nodeMemoryUsageAnnotation    = "troubleshoot.sh/memory-usage"
+	nodeCPURequestsAnnotation    = "troubleshoot.sh/cpu-requests"
+	nodeMemoryRequestsAnnotation = "troubleshoot.sh/memory-requests"
+	nodeCPULimitsAnnotation      = "troubleshoot.sh/cpu-limits"
+	nodeMemoryLimitsAnnotation   = "troubleshoot.sh/memory-limits"
+)
+
+// withNodeUsage returns the nodes with their usage from cluster-resources/node-metrics.json and the totals of their
+// pods' requests and limits from cluster-resources/node-pod-resources.json recorded in annotations, so that these can
+// be read like any other node property. Nodes missing from the files, or all nodes if the files were not collected,
+// are returned as they are.
+func withNodeUsage(nodes []corev1.Node, getObject getCollectedObject) []corev1.Node {
+	annotationsByNode := map[string]map[string]string{}
+	addNodeAnnotation := func(nodeName string, annotation string, quantity resource.Quantity) {
+		if annotationsByNode[nodeName] == nil {
+			annotationsByNode[nodeName] = map[string]string{}
+		}
+		annotationsByNode[nodeName][annotation] = quantity.String()
+	}
+
+	metricsList := []nodeMetrics{}
+	if err := getObject("cluster-resources/node-metrics.json", &metricsList); err == nil {
+		for _, metrics := range metricsList {
+			if cpu, ok := metrics.Usage[corev1.ResourceCPU]; ok {
+				addNodeAnnotation(metrics.Name, nodeCPUUsageAnnotation, cpu)
+			}
+			if memory, ok := metrics.Usage[corev1.ResourceMemory]; ok {
+				addNodeAnnotation(metrics.Name, nodeMemoryUsageAnnotation, memory)
+			}
+		}
+	}
+
+	// nodes without any pods and pods without requests or limits count as zero
+	podResourcesList := []collect.NodePodResources{}
+	if err := getObject("cluster-resources/node-pod-resources.json", &podResourcesList); err == nil {
+		podResourcesByNode := map[string]collect.NodePodResources{}
+		for _, podResources := range podResourcesList {
+			podResourcesByNode[podResources.NodeName] = podResources
+		}
+		for _, node := range nodes {
+			podResources := podResourcesByNode[node.Name]
+			addNodeAnnotation(node.Name, nodeCPURequestsAnnotation, *podResources.Requests.Cpu())
+			addNodeAnnotation(node.Name, nodeMemoryRequestsAnnotation, *podResources.Requests.Memory())
+			addNodeAnnotation(node.Name, nodeCPULimitsAnnotation, *podResources.Limits.Cpu())
+			addNodeAnnotation(node.Name, nodeMemoryLimitsAnnotation, *podResources.Limits.Memory())
+		}
+	}
+
+	if len(annotationsByNode) == 0 {
+		return nodes
 	}
 
-	result := &AnalyzeResult{
+	withUsage := make([]corev1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		added, ok := annotationsByNode[node.Name]
+		if !ok {
+			withUsage = append(withUsage, node)
+			continue
+		}
+
+		// the nodes are shared with other analyzers, so the annotations are copied before adding to them
+		annotations := map[string]string{}
+		for k, v := range node.Annotations {
+			annotations[k] = v
+		}
+		for k, v := range added {
+			annotations[k] = v
+		}
+		node.Annotations = annotations
+
+		withUsage = append(withUsage, node)
+	}
+
+	return withUsage
+}
+
+func nodeResourcesTitle(analyzer *troubleshootv1beta2.NodeResources) string {
+	if analyzer.CheckName != "" {
+		return analyzer.CheckName
+	}
+	return "Node Resources"
+}
+
+func newNodeResourcesResult(title string) *AnalyzeResult {
+	return &AnalyzeResult{
 		Title:   title,
 		IconKey: "kubernetes_node_resources",
 		IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
 	}
+}
 
-	for _, outcome := range analyzer.Outcomes {
-		if outcome.Fail != nil {
-			isWhenMatch, err := compareNodeResourceConditionalToActual(outcome.Fail.When, matchingNodes, len(nodes))
-			if err != nil {
-				return nil, errors.Wrap(err, "failed to parse when")
-			}
+func skippedNodeResourcesResult(result *AnalyzeResult) *AnalyzeResult {
+	result.IsWarn = true
+	result.IsSkipped = true
+	result.Message = "Skipped: the workload already exists and no onUpdate outcomes were provided"
+	return result
+}
 
-			if isWhenMatch {
-				result.IsFail = true
-				result.Message = outcome.Fail.Message
-				result.URI = outcome.Fail.URI
+// evaluateNodeResourcesOutcomes sets the result from the first outcome whose conditional matches, or with
+// mostSevere from the matching outcome with the highest severity, fail before warn before pass. When none match,
+// the result is left blank, or fails in strict mode.
+func evaluateNodeResourcesOutcomes(result *AnalyzeResult, outcomes []*troubleshootv1beta2.Outcome, analyzer *troubleshootv1beta2.NodeResources, matchingNodes []corev1.Node, totalNodeCount int) (*AnalyzeResult, error) {
+	tolerance, err := conditional.ParseTolerance(analyzer.Tolerance)
+	if err != nil {
+		return nil, err
+	}
 
-				return result, nil
-			}
-		} else if outcome.Warn != nil {
-			isWhenMatch, err := compareNodeResourceConditionalToActual(outcome.Warn.When, matchingNodes, len(nodes))
-			if err != nil {
-				return nil, errors.Wrap(err, "failed to parse when")
-			}
+	var matched *AnalyzeResult
+	for _, outcome := range outcomes {
+		outcomeResult, err := evaluateNodeResourcesOutcome(*result, outcome, matchingNodes, totalNodeCount, tolerance)
+		if err != nil {
+			return nil, err
+		}
+		if outcomeResult == nil {
+			continue
+		}
 
-			if isWhenMatch {
-				result.IsWarn = true
-				result.Message = outcome.Warn.Message
-				result.URI = outcome.Warn.URI
+		if !analyzer.MostSevere {
+			return outcomeResult, nil
+		}
+		if matched == nil || resultSeverity(outcomeResult) > resultSeverity(matched) {
+			matched = outcomeResult
+		}
+	}
 
-				return result, nil
-			}
-		} else if outcome.Pass != nil {
-			isWhenMatch, err := compareNodeResourceConditionalToActual(outcome.Pass.When, matchingNodes, len(nodes))
-			if err != nil {
-				return nil, errors.Wrap(err, "failed to parse when")
-			}
+	if matched != nil {
+		return matched, nil
+	}
 
-			if isWhenMatch {
-				result.IsPass = true
-				result.Message = outcome.Pass.Message
-				result.URI = outcome.Pass.URI
+	if analyzer.Strict {
+		result.IsFail = true
+		result.Message = "No outcome matched"
+	}
 
-				return result, nil
+	return result, nil
+}
+
+// evaluateNodeResourcesOutcome returns a copy of result set from the outcome, or nil if its conditional does not
+// match
+func evaluateNodeResourcesOutcome(result AnalyzeResult, outcome *troubleshootv1beta2.Outcome, matchingNodes []corev1.Node, totalNodeCount int, tolerance *conditional.Tolerance) (*AnalyzeResult, error) {
+	single := outcome.Fail
+	if single == nil {
+		single = outcome.Warn
+	}
+	if single == nil {
+		single = outcome.Pass
+	}
+	if single == nil {
+		return nil, nil
+	}
+
+	when, isNegated, err := nodeResourcesOutcomeConditional(single)
+	if err != nil {
+		return nil, err
+	}
+
+	isWhenMatch, actualValue, deciding, err := evaluateNodeResourceConditional(when, matchingNodes, totalNodeCount, tolerance)
+	if errors.Cause(err) == errNoNodeResourceValue {
+		return noNodeResourceValueResult(&result, when), nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse when")
+	}
+	if isNegated {
+		isWhenMatch = !isWhenMatch
+	}
+	if !isWhenMatch {
+		return nil, nil
+	}
+
+	message, err := renderNodeResourcesMessage(single.Message, deciding, isNegated, matchingNodes, actualValue)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render message")
+	}
+
+	result.IsFail = single == outcome.Fail
+	result.IsWarn = single == outcome.Warn
+	result.IsPass = single == outcome.Pass
+	result.Message = message
+	result.URI = single.URI
+
+	return &result, nil
+}
+
+// nodeResourcesOutcomeConditional returns the when or whenNot conditional of the outcome, and whether it is whenNot
+func nodeResourcesOutcomeConditional(single *troubleshootv1beta2.SingleOutcome) (string, bool, error) {
+	if single.When != "" && single.WhenNot != "" {
+		return "", false, errors.Errorf("when %q and whenNot %q cannot both be set", single.When, single.WhenNot)
+	}
+	if single.WhenNot != "" {
+		return single.WhenNot, true, nil
+	}
+	return single.When, false, nil
+}
+
+// resultSeverity orders results from pass to fail
+func resultSeverity(result *AnalyzeResult) int {
+	switch {
+	case result.IsFail:
+		return 3
+	case result.IsWarn:
+		return 2
+	case result.IsPass:
+		return 1
+	}
+	return 0
+}
+
+// selectNodeResourcesOutcomes uses the onUpdate outcomes when the referenced workload already exists, and the
+// onInstall outcomes when it does not. A nil result means the workload exists but there are no onUpdate outcomes.
+func selectNodeResourcesOutcomes(analyzer *troubleshootv1beta2.NodeResources, getCollectedFileContents func(string) ([]byte, error)) ([]*troubleshootv1beta2.Outcome, error) {
+	workloadsDir, workload, err := getNodeResourcesWorkload(analyzer)
+	if err != nil {
+		return nil, err
+	}
+
+	if workload == nil {
+		return analyzer.Outcomes, nil
+	}
+
+	exists, err := workloadExists(workloadsDir, workload, getCollectedFileContents)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if %s %s exists", workloadsDir, describeWorkload(workload))
+	}
+
+	if exists {
+		if len(analyzer.OnUpdate) == 0 {
+			return nil, nil
+		}
+		return analyzer.OnUpdate, nil
+	}
+
+	if len(analyzer.OnInstall) == 0 {
+		return analyzer.Outcomes, nil
+	}
+	return analyzer.OnInstall, nil
+}
+
+// InterpolateAnalyzer returns a copy of the analyzer where ${NAME} references in the when, whenNot and message of
+// nodeResources outcomes are replaced with their values, so that a spec can take thresholds such as
+// "count() >= ${MIN_NODES}" as parameters. Referencing a name that is not in values is an error. Other analyzers are
+// returned as they are.
+func InterpolateAnalyzer(analyzer *troubleshootv1beta2.Analyze, values map[string]string) (*troubleshootv1beta2.Analyze, error) {
+	if analyzer.NodeResources == nil {
+		return analyzer, nil
+	}
+
+	interpolated := analyzer.DeepCopy()
+	for _, outcomes := range []struct {
+		field    string
+		outcomes []*troubleshootv1beta2.Outcome
+	}{
+		{field: "outcomes", outcomes: interpolated.NodeResources.Outcomes},
+		{field: "onInstall", outcomes: interpolated.NodeResources.OnInstall},
+		{field: "onUpdate", outcomes: interpolated.NodeResources.OnUpdate},
+	} {
+		for i, outcome := range outcomes.outcomes {
+			for _, single := range []struct {
+				name    string
+				outcome *troubleshootv1beta2.SingleOutcome
+			}{
+				{name: "fail", outcome: outcome.Fail},
+				{name: "warn", outcome: outcome.Warn},
+				{name: "pass", outcome: outcome.Pass},
+			} {
+				if single.outcome == nil {
+					continue
+				}
+				for _, field := range []struct {
+					name  string
+					value *string
+				}{
+					{name: "when", value: &single.outcome.When},
+					{name: "whenNot", value: &single.outcome.WhenNot},
+					{name: "message", value: &single.outcome.Message},
+				} {
+					value, err := conditional.Interpolate(*field.value, values)
+					if err != nil {
+						return nil, errors.Wrapf(err, "%s[%d].%s.%s", outcomes.field, i, single.name, field.name)
+					}
+					*field.value = value
+				}
 			}
 		}
 	}
 
-	return result, nil
+	return interpolated, nil
 }
 
-func compareNodeResourceConditionalToActual(conditional string, matchingNodes []corev1.Node, totalNodeCount int) (res bool, err error) {
-	res = false
-	err = nil
+// getNodeResourcesWorkload returns the collected directory and reference of the workload used to pick
+// between install and update outcomes
+func getNodeResourcesWorkload(analyzer *troubleshootv1beta2.NodeResources) (string, *troubleshootv1beta2.NodeResourcesWorkload, error) {
+	workloadsDir := ""
+	var workload *troubleshootv1beta2.NodeResourcesWorkload
 
-	defer func() {
-		if r := recover(); r != nil {
-			err = errors.Errorf("failed to evaluate %q: %v", conditional, r)
+	references := map[string]*troubleshootv1beta2.NodeResourcesWorkload{
+		"deployments":  analyzer.Deployment,
+		"statefulsets": analyzer.StatefulSet,
+		"daemonsets":   analyzer.DaemonSet,
+	}
+	for dir, reference := range references {
+		if reference == nil {
+			continue
 		}
-	}()
+		if workload != nil {
+			return "", nil, errors.New("only one of deployment, statefulSet or daemonSet may be specified")
+		}
+		workloadsDir = dir
+		workload = reference
+	}
 
-	if conditional == "" {
-		res = true
-		return
+	return workloadsDir, workload, nil
+}
+
+// workloadExists reports whether a collected workload in the namespace matches both the name and the selector
+// of the reference, when they are set
+func workloadExists(workloadsDir string, workload *troubleshootv1beta2.NodeResourcesWorkload, getCollectedFileContents func(string) ([]byte, error)) (bool, error) {
+	if workload.Name == "" && len(workload.Selector) == 0 {
+		return false, errors.New("a name or selector is required")
+	}
+
+	// the namespace file is missing when the namespace does not exist, so the workload does not either
+	collected, err := getCollectedFileContents(filepath.Join("cluster-resources", workloadsDir, fmt.Sprintf("%s.json", workload.Namespace)))
+	if err != nil {
+		return false, nil
 	}
 
-	parts := strings.Fields(strings.TrimSpace(conditional))
+	var workloads []struct {
+		metav1.ObjectMeta `json:"metadata"`
+	}
+	if err := json.Unmarshal(collected, &workloads); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal workload list")
+	}
+
+	selector := labels.SelectorFromSet(workload.Selector)
+	for _, w := range workloads {
+		if workload.Name != "" && w.Name != workload.Name {
+			continue
+		}
+		if !selector.Matches(labels.Set(w.Labels)) {
+			continue
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func describeWorkload(workload *troubleshootv1beta2.NodeResourcesWorkload) string {
+	if len(workload.Selector) == 0 {
+		return workload.Name
+	}
+	if workload.Name == "" {
+		return labels.SelectorFromSet(workload.Selector).String()
+	}
+	return fmt.Sprintf("%s (%s)", workload.Name, labels.SelectorFromSet(workload.Selector))
+}
+
+// errNoNodeResourceValue is returned when an aggregate such as min() has no node values to evaluate
+var errNoNodeResourceValue = errors.New("no matching nodes report a value")
+
+func noNodeResourceValueResult(result *AnalyzeResult, conditional string) *AnalyzeResult {
+	result.IsWarn = true
+	result.Message = fmt.Sprintf("Unable to evaluate %q: no matching nodes report a value", conditional)
+	return result
+}
+
+type nodeResourcesMessageData struct {
+	NodeCount int
+	NodeNames []string
+	Value     string
+}
+
+// renderNodeResourcesMessage renders the outcome message as a template with the matching nodes and computed value.
+// Outcomes without a message get a default one describing the computed value. For a conditional combined with || or
+// &&, when is the conditional that decided the result.
+func renderNodeResourcesMessage(message string, when string, isNegated bool, matchingNodes []corev1.Node, actualValue interface{}) (string, error) {
+	if message == "" {
+		return defaultNodeResourcesMessage(when, isNegated, actualValue), nil
+	}
+
+	if !strings.Contains(message, "{{") {
+		return message, nil
+	}
+
+	tmpl, err := template.New("message").Parse(message)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse message template")
+	}
+
+	data := nodeResourcesMessageData{
+		NodeCount: len(matchingNodes),
+		NodeNames: []string{},
+		Value:     conditional.FormatValue(actualValue),
+	}
+	for _, node := range matchingNodes {
+		data.NodeNames = append(data.NodeNames, node.Name)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", errors.Wrap(err, "failed to execute message template")
+	}
+
+	return buf.String(), nil
+}
+
+// defaultNodeResourcesMessage describes the computed value, e.g. "sum(cpuAllocatable) is 12, which is < 16"
+func defaultNodeResourcesMessage(when string, isNegated bool, actualValue interface{}) string {
+	parts := conditional.SplitActual(strings.TrimSpace(when))
+	if len(parts) == 0 || actualValue == nil {
+		return ""
+	}
 
 	if len(parts) == 2 {
-		parts = append([]string{"count"}, parts...)
+		parts = append([]string{"count()"}, parts...)
+	}
+
+	which := "which is"
+	if isNegated {
+		which = "which is not"
+	}
+
+	return fmt.Sprintf("%s is %s, %s %s", parts[0], conditional.FormatValue(actualValue), which, strings.Join(parts[1:], " "))
+}
+
+func compareNodeResourceConditionalToActual(when string, matchingNodes []corev1.Node, totalNodeCount int, tolerance *conditional.Tolerance) (bool, error) {
+	res, _, _, err := evaluateNodeResourceConditional(when, matchingNodes, totalNodeCount, tolerance)
+	return res, err
+}
+
+// evaluateNodeResourceConditional returns whether the conditional matches along with the computed actual value and
+// the conditional that decided the result, which is one of those combined with || or &&, or else the conditional
+// itself. Equality operators match values within the tolerance, if any.
+func evaluateNodeResourceConditional(when string, matchingNodes []corev1.Node, totalNodeCount int, tolerance *conditional.Tolerance) (bool, interface{}, string, error) {
+	return conditional.EvaluateCombined(when, func(single string) (bool, interface{}, error) {
+		// targets are resolved for the property each conditional compares
+		resolved, err := resolveNodeResourceTargets(single)
+		if err != nil {
+			return false, nil, err
+		}
+
+		return conditional.EvaluateWithTolerance(resolved, func(expression string) (interface{}, error) {
+			return findNodeResourceValue(expression, matchingNodes, totalNodeCount)
+		}, tolerance)
+	})
+}
+
+// nodeResourceTargets are the keywords that can be used in place of a desired value, such as
+// "min(memoryAllocatable) >= maxRequests", so that the threshold comes from the cluster itself. Each is resolved
+// across the matching nodes, for the cpu or memory resource of the property it is compared to:
+//
+//	maxRequests   the largest pod requests on a node, max(cpuRequests) or max(memoryRequests)
+//	maxLimits     the largest pod limits on a node, max(cpuLimits) or max(memoryLimits)
+//	maxUsage      the largest usage of a node, max(cpuUsage) or max(memoryUsage)
+//	totalRequests the pod requests of all nodes, sum(cpuRequests) or sum(memoryRequests)
+//
+// Targets can be multiplied and used as the bounds of between, e.g. "min(memoryAllocatable) >= maxRequests * 1.5".
+var nodeResourceTargets = map[string]string{
+	"maxRequests":   "max(%sRequests)",
+	"maxLimits":     "max(%sLimits)",
+	"maxUsage":      "max(%sUsage)",
+	"totalRequests": "sum(%sRequests)",
+}
+
+// resolveNodeResourceTargets replaces the target keywords in the desired values of the conditional with the
+// expressions that compute them
+func resolveNodeResourceTargets(when string) (string, error) {
+	parts := conditional.Split(strings.TrimSpace(when))
+	if len(parts) < 3 {
+		if len(parts) == 2 {
+			if _, ok := nodeResourceTargets[parts[1]]; ok {
+				return "", errors.Errorf("%s must be compared to a cpu or memory property", parts[1])
+			}
+		}
+		return when, nil
 	}
 
-	if len(parts) != 3 {
-		err = errors.New("unable to parse nodeResources conditional")
-		return
+	isResolved := false
+	for i := 2; i < len(parts); i++ {
+		target, ok := nodeResourceTargets[parts[i]]
+		if !ok {
+			continue
+		}
+
+		resourceName := nodeResourceExpressionResource(parts[0])
+		if resourceName == "" {
+			return "", errors.Errorf("%s must be compared to a cpu or memory property, got %s", parts[i], parts[0])
+		}
+		parts[i] = fmt.Sprintf(target, resourceName)
+		isResolved = true
 	}
 
-	operator := parts[1]
+	if !isResolved {
+		return when, nil
+	}
+	return strings.Join(parts, " "), nil
+}
 
-	var desiredValue interface{}
-	desiredValue = parts[2]
+// nodeResourceExpressionResource returns cpu or memory for expressions over a property of that resource, such as
+// min(memoryAllocatable), or an empty string
+func nodeResourceExpressionResource(expression string) string {
+	match := conditional.ExpressionRegex.FindStringSubmatch(expression)
+	if match == nil {
+		return ""
+	}
 
-	parsedDesiredValue, err := strconv.Atoi(parts[2])
-	if err == nil {
-		desiredValue = parsedDesiredValue
-	} else {
-		err = nil // try parsing as a resource
+	property := strings.TrimSpace(match[2])
+	if match[1] == "percentile" {
+		percentileProperty, _, err := parsePercentileArguments(property)
+		if err != nil {
+			return ""
+		}
+		property = percentileProperty
 	}
 
-	reg := regexp.MustCompile(`(?P<function>.*)\((?P<property>.*)\)`)
-	match := reg.FindStringSubmatch(parts[0])
+	if match := resourcePropertyRegex.FindStringSubmatch(property); match != nil {
+		property = match[2]
+	}
+	if aliased, ok := nodeResourcePropertyAliases[property]; ok {
+		property = aliased
+	}
+	for _, resourceName := range []string{"cpu", "memory"} {
+		if strings.HasPrefix(property, resourceName) {
+			return resourceName
+		}
+	}
+
+	return ""
+}
+
+// findNodeResourceValue computes the value of a function(property) expression across the matching nodes.
+// The result is an int for count() and a *resource.Quantity otherwise. count(property in [a,b]) counts the nodes
+// whose property is one of the values, and notIn those whose property is none of them. Nodes without the property
+// are counted by neither.
+func findNodeResourceValue(expression string, matchingNodes []corev1.Node, totalNodeCount int) (interface{}, error) {
+	reg := conditional.ExpressionRegex
+	match := reg.FindStringSubmatch(expression)
 
 	if match == nil {
 		// We support this as equivalent to the count() function
-		match = reg.FindStringSubmatch(fmt.Sprintf("count() == %s", parts[0]))
+		match = reg.FindStringSubmatch("count()")
 	}
 
 	if match == nil || len(match) != 3 {
-		err = errors.New("conditional does not match pattern of function(property?)")
-		return
+		return nil, errors.New("conditional does not match pattern of function(property?)")
 	}
 
 	function := match[1]
@@ -152,102 +778,287 @@ func compareNodeResourceConditionalToActual(conditional string, matchingNodes []
 
 	switch function {
 	case "count":
-		actualValue = len(matchingNodes)
+		membership, err := conditional.ParseSetMembership(property)
+		if err != nil {
+			return nil, err
+		}
+		if membership == nil {
+			actualValue = len(matchingNodes)
+			break
+		}
+		// each node's own value is compared to the set, so this cannot use an aggregate
+		count := 0
+		for _, node := range matchingNodes {
+			if membership.Matches(getMembershipQuantity(node, membership.Property)) {
+				count++
+			}
+		}
+		actualValue = count
+	case "countAll":
+		actualValue = totalNodeCount
+	case "percent":
+		// the percentage of all nodes that match the filters
+		if totalNodeCount == 0 {
+			return nil, errNoNodeResourceValue
+		}
+		actualValue = float64(len(matchingNodes)) / float64(totalNodeCount) * 100
 	case "min":
 		actualValue = findMin(matchingNodes, property)
 	case "max":
 		actualValue = findMax(matchingNodes, property)
 	case "sum":
 		actualValue = findSum(matchingNodes, property)
+	case "avg":
+		actualValue = findAvg(matchingNodes, property)
+	case "median":
+		actualValue = findPercentile(matchingNodes, property, 50)
+	case "percentile":
+		percentileProperty, percentile, err := parsePercentileArguments(property)
+		if err != nil {
+			return nil, err
+		}
+		actualValue = findPercentile(matchingNodes, percentileProperty, percentile)
+	case "stddev":
+		actualValue = findStddev(matchingNodes, property)
+	default:
+		return nil, errors.Errorf("unsupported function %q", function)
 	}
 
-	switch operator {
-	case "=", "==", "===":
-		if _, ok := actualValue.(int); ok {
-			if _, ok := desiredValue.(int); ok {
-				res = actualValue.(int) == desiredValue.(int)
-				return
-			}
-		}
+	if quantity, ok := actualValue.(*resource.Quantity); ok && quantity == nil {
+		return nil, errNoNodeResourceValue
+	}
 
-		if _, ok := desiredValue.(string); ok {
-			res = actualValue.(*resource.Quantity).Cmp(resource.MustParse(desiredValue.(string))) == 0
-			return
-		}
+	return actualValue, nil
+}
 
-		res = actualValue.(*resource.Quantity).Cmp(resource.MustParse(strconv.Itoa(desiredValue.(int)))) == 0
-		return
+// nodeResourceProperties are the named properties reported by GetNodeResourcesReport
+var nodeResourceProperties = []string{
+	"cpuCapacity",
+	"cpuAllocatable",
+	"memoryCapacity",
+	"memoryAllocatable",
+	"podCapacity",
+	"podAllocatable",
+	"ephemeralStorageCapacity",
+	"ephemeralStorageAllocatable",
+	"gpuCapacity",
+	"gpuAllocatable",
+	"hugepages2MiCapacity",
+	"hugepages2MiAllocatable",
+	"hugepages1GiCapacity",
+	"hugepages1GiAllocatable",
+}
+
+// nodeResourceUsageProperties are the named properties read from the annotations that the clusterResources
+// collector adds to each node
+var nodeResourceUsageProperties = []string{
+	"cpuUsage",
+	"memoryUsage",
+	"cpuRequests",
+	"memoryRequests",
+	"cpuLimits",
+	"memoryLimits",
+}
+
+// nodeResourcePropertyAliases are other names accepted for the usage properties
+var nodeResourcePropertyAliases = map[string]string{
+	"requestedCpu":    "cpuRequests",
+	"requestedMemory": "memoryRequests",
+}
+
+// nodeResourcePropertyPatterns describe the properties that take an argument
+var nodeResourcePropertyPatterns = []string{
+	"annotation(<name>)",
+	"allocatablePercent(<resource>)",
+	"usagePercent(cpu|memory)",
+	"requestsPercent(cpu|memory)",
+	"limitsPercent(cpu|memory)",
+	"capacity[<resource>]",
+	"allocatable[<resource>]",
+}
 
-	case "<":
-		if _, ok := actualValue.(int); ok {
-			if _, ok := desiredValue.(int); ok {
-				res = actualValue.(int) < desiredValue.(int)
-				return
+// validateNodeResourcesProperties returns an error for the first conditional of the analyzer that uses an unknown
+// property. Unknown properties have no value on any node, so a typo such as memoryAllcatable would otherwise only
+// show up as a failure to evaluate.
+func validateNodeResourcesProperties(analyzer *troubleshootv1beta2.NodeResources) error {
+	for _, field := range []struct {
+		name     string
+		outcomes []*troubleshootv1beta2.Outcome
+	}{
+		{name: "outcomes", outcomes: analyzer.Outcomes},
+		{name: "onInstall", outcomes: analyzer.OnInstall},
+		{name: "onUpdate", outcomes: analyzer.OnUpdate},
+	} {
+		for i, outcome := range field.outcomes {
+			for _, single := range []struct {
+				name    string
+				outcome *troubleshootv1beta2.SingleOutcome
+			}{
+				{name: "fail", outcome: outcome.Fail},
+				{name: "warn", outcome: outcome.Warn},
+				{name: "pass", outcome: outcome.Pass},
+			} {
+				if single.outcome == nil {
+					continue
+				}
+				if err := validateNodeResourceConditionalProperties(single.outcome.When); err != nil {
+					return errors.Wrapf(err, "%s[%d].%s.when %q", field.name, i, single.name, single.outcome.When)
+				}
+				if err := validateNodeResourceConditionalProperties(single.outcome.WhenNot); err != nil {
+					return errors.Wrapf(err, "%s[%d].%s.whenNot %q", field.name, i, single.name, single.outcome.WhenNot)
+				}
 			}
 		}
-		if _, ok := desiredValue.(string); ok {
-			res = actualValue.(*resource.Quantity).Cmp(resource.MustParse(desiredValue.(string))) == -1
-			return
-		}
+	}
 
-		res = actualValue.(*resource.Quantity).Cmp(resource.MustParse(strconv.Itoa(desiredValue.(int)))) == -1
-		return
+	return nil
+}
 
-	case ">":
-		if _, ok := actualValue.(int); ok {
-			if _, ok := desiredValue.(int); ok {
-				res = actualValue.(int) > desiredValue.(int)
-				return
+// validateNodeResourceConditionalProperties returns an error listing the valid properties if a function(property)
+// expression in the conditional uses an unknown property, or if a property is used without a function, as in
+// "cpuAllocatable >= cpuCapacity * 0.9", which would otherwise be read as count() or fail to parse
+func validateNodeResourceConditionalProperties(when string) error {
+	for _, part := range conditional.Split(strings.TrimSpace(when)) {
+		match := conditional.ExpressionRegex.FindStringSubmatch(part)
+		if match == nil {
+			if isKnownNodeResourceProperty(part) {
+				return errors.Errorf("property references must use a function, e.g. sum(cpuCapacity), got %s", part)
 			}
-		}
-		if _, ok := desiredValue.(string); ok {
-			res = actualValue.(*resource.Quantity).Cmp(resource.MustParse(desiredValue.(string))) == 1
-			return
+			continue
 		}
 
-		res = actualValue.(*resource.Quantity).Cmp(resource.MustParse(strconv.Itoa(desiredValue.(int)))) == 1
-		return
-
-	case "<=":
-		if _, ok := actualValue.(int); ok {
-			if _, ok := desiredValue.(int); ok {
-				res = actualValue.(int) <= desiredValue.(int)
-				return
+		function := match[1]
+		property := strings.TrimSpace(match[2])
+		switch function {
+		case "min", "max", "sum", "avg", "median", "stddev":
+		case "count":
+			membership, err := conditional.ParseSetMembership(property)
+			if err != nil {
+				return errors.Wrapf(err, "invalid set in %s", part)
+			}
+			if membership == nil {
+				continue
+			}
+			property = membership.Property
+		case "percentile":
+			percentileProperty, _, err := parsePercentileArguments(property)
+			if err != nil {
+				// reported when the conditional is evaluated
+				continue
 			}
+			property = percentileProperty
+		default:
+			continue
 		}
-		if _, ok := desiredValue.(string); ok {
-			res = actualValue.(*resource.Quantity).Cmp(resource.MustParse(desiredValue.(string))) == 0 ||
-				actualValue.(*resource.Quantity).Cmp(resource.MustParse(desiredValue.(string))) == -1
-			return
+
+		if !isKnownNodeResourceProperty(property) {
+			valid := append(append(append([]string{}, nodeResourceProperties...), nodeResourceUsageProperties...), nodeResourcePropertyPatterns...)
+			aliases := []string{}
+			for alias, property := range nodeResourcePropertyAliases {
+				aliases = append(aliases, fmt.Sprintf("%s (%s)", alias, property))
+			}
+			sort.Strings(aliases)
+			valid = append(valid, aliases...)
+			return errors.Errorf("unknown property %q in %s, valid properties are %s", property, part, strings.Join(valid, ", "))
 		}
+	}
 
-		res = actualValue.(*resource.Quantity).Cmp(resource.MustParse(strconv.Itoa(desiredValue.(int)))) == 0 ||
-			actualValue.(*resource.Quantity).Cmp(resource.MustParse(strconv.Itoa(desiredValue.(int)))) == -1
-		return
+	return nil
+}
 
-	case ">=":
-		if _, ok := actualValue.(int); ok {
-			if _, ok := desiredValue.(int); ok {
-				res = actualValue.(int) >= desiredValue.(int)
-				return
-			}
+func isKnownNodeResourceProperty(property string) bool {
+	if _, ok := nodeResourcePropertyAliases[property]; ok {
+		return true
+	}
+
+	for _, known := range append(append([]string{}, nodeResourceProperties...), nodeResourceUsageProperties...) {
+		if property == known {
+			return true
 		}
-		if _, ok := desiredValue.(string); ok {
-			res = actualValue.(*resource.Quantity).Cmp(resource.MustParse(desiredValue.(string))) == 0 ||
-				actualValue.(*resource.Quantity).Cmp(resource.MustParse(desiredValue.(string))) == 1
-			return
+	}
+
+	for _, reg := range []*regexp.Regexp{
+		annotationPropertyRegex,
+		allocatablePercentPropertyRegex,
+		usagePercentPropertyRegex,
+		requestsPercentPropertyRegex,
+		limitsPercentPropertyRegex,
+		resourcePropertyRegex,
+	} {
+		if reg.MatchString(property) {
+			return true
 		}
+	}
+
+	return false
+}
+
+// gpuResourceNames are the vendor extended resources counted by gpuCapacity and gpuAllocatable
+var gpuResourceNames = []corev1.ResourceName{
+	"nvidia.com/gpu",
+	"amd.com/gpu",
+}
 
-		res = actualValue.(*resource.Quantity).Cmp(resource.MustParse(strconv.Itoa(desiredValue.(int)))) == 0 ||
-			actualValue.(*resource.Quantity).Cmp(resource.MustParse(strconv.Itoa(desiredValue.(int)))) == 1
-		return
+// parsePercentileArguments parses the "property, N" arguments of the percentile function
+func parsePercentileArguments(arguments string) (string, float64, error) {
+	idx := strings.LastIndex(arguments, ",")
+	if idx == -1 {
+		return "", 0, errors.New("percentile requires a property and a percentile, e.g. percentile(memoryAllocatable, 90)")
 	}
 
-	err = errors.New("unexpected conditional in nodeResources")
-	return
+	property := strings.TrimSpace(arguments[:idx])
+	percentile, err := strconv.ParseFloat(strings.TrimSpace(arguments[idx+1:]), 64)
+	if err != nil {
+		return "", 0, errors.Wrap(err, "failed to parse percentile")
+	}
+
+	if percentile < 0 || percentile > 100 {
+		return "", 0, errors.Errorf("percentile %v is outside the range 0-100", percentile)
+	}
+
+	return property, percentile, nil
 }
 
+var annotationPropertyRegex = regexp.MustCompile(`^annotation\((?P<name>.+)\)$`)
+var allocatablePercentPropertyRegex = regexp.MustCompile(`^allocatablePercent\((?P<resource>.+)\)$`)
+var usagePercentPropertyRegex = regexp.MustCompile(`^usagePercent\((?P<resource>cpu|memory)\)$`)
+var requestsPercentPropertyRegex = regexp.MustCompile(`^requestsPercent\((?P<resource>cpu|memory)\)$`)
+var limitsPercentPropertyRegex = regexp.MustCompile(`^limitsPercent\((?P<resource>cpu|memory)\)$`)
+var resourcePropertyRegex = regexp.MustCompile(`^(?P<list>capacity|allocatable)\["?(?P<name>[^"\]]+)"?\]$`)
+
 func getQuantity(node corev1.Node, property string) *resource.Quantity {
+	if aliased, ok := nodeResourcePropertyAliases[property]; ok {
+		property = aliased
+	}
+
+	if match := annotationPropertyRegex.FindStringSubmatch(property); match != nil {
+		return getAnnotationQuantity(node, match[1])
+	}
+
+	if match := allocatablePercentPropertyRegex.FindStringSubmatch(property); match != nil {
+		return getAllocatablePercent(node, match[1])
+	}
+
+	if match := usagePercentPropertyRegex.FindStringSubmatch(property); match != nil {
+		return getAllocatedPercent(node, match[1], nodeCPUUsageAnnotation, nodeMemoryUsageAnnotation)
+	}
+
+	if match := requestsPercentPropertyRegex.FindStringSubmatch(property); match != nil {
+		return getAllocatedPercent(node, match[1], nodeCPURequestsAnnotation, nodeMemoryRequestsAnnotation)
+	}
+
+	if match := limitsPercentPropertyRegex.FindStringSubmatch(property); match != nil {
+		return getAllocatedPercent(node, match[1], nodeCPULimitsAnnotation, nodeMemoryLimitsAnnotation)
+	}
+
+	if match := resourcePropertyRegex.FindStringSubmatch(property); match != nil {
+		resources := node.Status.Capacity
+		if match[1] == "allocatable" {
+			resources = node.Status.Allocatable
+		}
+		return getResourceQuantity(resources, corev1.ResourceName(match[2]))
+	}
+
 	switch property {
 	case "cpuCapacity":
 		return node.Status.Capacity.Cpu()
@@ -265,10 +1076,130 @@ func getQuantity(node corev1.Node, property string) *resource.Quantity {
 		return node.Status.Capacity.StorageEphemeral()
 	case "ephemeralStorageAllocatable":
 		return node.Status.Allocatable.StorageEphemeral()
+	case "gpuCapacity":
+		return getGPUQuantity(node.Status.Capacity)
+	case "gpuAllocatable":
+		return getGPUQuantity(node.Status.Allocatable)
+	case "hugepages2MiCapacity":
+		return getResourceQuantity(node.Status.Capacity, "hugepages-2Mi")
+	case "hugepages2MiAllocatable":
+		return getResourceQuantity(node.Status.Allocatable, "hugepages-2Mi")
+	case "hugepages1GiCapacity":
+		return getResourceQuantity(node.Status.Capacity, "hugepages-1Gi")
+	case "hugepages1GiAllocatable":
+		return getResourceQuantity(node.Status.Allocatable, "hugepages-1Gi")
+	case "cpuUsage":
+		return getAnnotationQuantity(node, nodeCPUUsageAnnotation)
+	case "memoryUsage":
+		return getAnnotationQuantity(node, nodeMemoryUsageAnnotation)
+	case "cpuRequests":
+		return getAnnotationQuantity(node, nodeCPURequestsAnnotation)
+	case "memoryRequests":
+		return getAnnotationQuantity(node, nodeMemoryRequestsAnnotation)
+	case "cpuLimits":
+		return getAnnotationQuantity(node, nodeCPULimitsAnnotation)
+	case "memoryLimits":
+		return getAnnotationQuantity(node, nodeMemoryLimitsAnnotation)
 	}
 	return nil
 }
 
+// getAllocatablePercent returns allocatable as a percentage of capacity for the resource.
+// Nodes with zero capacity return nil and are skipped.
+func getAllocatablePercent(node corev1.Node, name string) *resource.Quantity {
+	resourceName := corev1.ResourceName(name)
+	switch name {
+	case "cpu":
+		resourceName = corev1.ResourceCPU
+	case "memory":
+		resourceName = corev1.ResourceMemory
+	case "pods":
+		resourceName = corev1.ResourcePods
+	case "ephemeralStorage":
+		resourceName = corev1.ResourceEphemeralStorage
+	}
+
+	capacity := getResourceQuantity(node.Status.Capacity, resourceName)
+	if capacity.IsZero() {
+		return nil
+	}
+	allocatable := getResourceQuantity(node.Status.Allocatable, resourceName)
+
+	percent := float64(allocatable.MilliValue()) / float64(capacity.MilliValue()) * 100
+	return resource.NewMilliQuantity(int64(math.Round(percent*1000)), resource.DecimalSI)
+}
+
+// getAllocatedPercent returns the cpu or memory quantity recorded in the annotations as a percentage of allocatable.
+// Nodes without the annotation or with zero allocatable return nil and are skipped.
+func getAllocatedPercent(node corev1.Node, name string, cpuAnnotation string, memoryAnnotation string) *resource.Quantity {
+	quantity := getAnnotationQuantity(node, cpuAnnotation)
+	allocatable := node.Status.Allocatable.Cpu()
+	if name == "memory" {
+		quantity = getAnnotationQuantity(node, memoryAnnotation)
+		allocatable = node.Status.Allocatable.Memory()
+	}
+	if quantity == nil || allocatable.IsZero() {
+		return nil
+	}
+
+	percent := float64(quantity.MilliValue()) / float64(allocatable.MilliValue()) * 100
+	return resource.NewMilliQuantity(int64(math.Round(percent*1000)), resource.DecimalSI)
+}
+
+// getMembershipQuantity is getQuantity for set membership, where nodes that do not report a capacity[...] or
+// allocatable[...] resource have no value rather than zero.
+func getMembershipQuantity(node corev1.Node, property string) *resource.Quantity {
+	if match := resourcePropertyRegex.FindStringSubmatch(property); match != nil {
+		resources := node.Status.Capacity
+		if match[1] == "allocatable" {
+			resources = node.Status.Allocatable
+		}
+		if _, ok := resources[corev1.ResourceName(match[2])]; !ok {
+			return nil
+		}
+	}
+
+	return getQuantity(node, property)
+}
+
+// getResourceQuantity looks up any resource by name, such as an extended resource. Missing resources report zero.
+func getResourceQuantity(resources corev1.ResourceList, name corev1.ResourceName) *resource.Quantity {
+	if quant, ok := resources[name]; ok {
+		return &quant
+	}
+
+	return resource.NewQuantity(0, resource.DecimalSI)
+}
+
+// getGPUQuantity totals the GPUs of all known vendors. Nodes without GPUs report zero.
+func getGPUQuantity(resources corev1.ResourceList) *resource.Quantity {
+	total := resource.NewQuantity(0, resource.DecimalSI)
+
+	for _, name := range gpuResourceNames {
+		if quant, ok := resources[name]; ok {
+			total.Add(quant)
+		}
+	}
+
+	return total
+}
+
+// getAnnotationQuantity parses the value of the named annotation as a quantity.
+// Nodes without the annotation, or with a non-numeric value, return nil and are skipped.
+func getAnnotationQuantity(node corev1.Node, name string) *resource.Quantity {
+	value, ok := node.Annotations[name]
+	if !ok {
+		return nil
+	}
+
+	parsed, err := resource.ParseQuantity(strings.TrimSpace(value))
+	if err != nil {
+		return nil
+	}
+
+	return &parsed
+}
+
 func findSum(nodes []corev1.Node, property string) *resource.Quantity {
 	sum := resource.Quantity{}
 
@@ -281,6 +1212,84 @@ func findSum(nodes []corev1.Node, property string) *resource.Quantity {
 	return &sum
 }
 
+func findAvg(nodes []corev1.Node, property string) *resource.Quantity {
+	sum := resource.Quantity{}
+	count := int64(0)
+
+	for _, node := range nodes {
+		if quant := getQuantity(node, property); quant != nil {
+			sum.Add(*quant)
+			count++
+		}
+	}
+
+	if count == 0 {
+		return nil
+	}
+
+	return resource.NewMilliQuantity(sum.MilliValue()/count, sum.Format)
+}
+
+// findPercentile returns the linearly interpolated percentile of the property across nodes
+func findPercentile(nodes []corev1.Node, property string, percentile float64) *resource.Quantity {
+	quantities := []*resource.Quantity{}
+
+	for _, node := range nodes {
+		if quant := getQuantity(node, property); quant != nil {
+			quantities = append(quantities, quant)
+		}
+	}
+
+	if len(quantities) == 0 {
+		return nil
+	}
+
+	sort.Slice(quantities, func(i, j int) bool {
+		return quantities[i].Cmp(*quantities[j]) == -1
+	})
+
+	rank := percentile / 100 * float64(len(quantities)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	lowerValue := float64(quantities[lower].MilliValue())
+	upperValue := float64(quantities[upper].MilliValue())
+	value := lowerValue + (upperValue-lowerValue)*(rank-float64(lower))
+
+	return resource.NewMilliQuantity(int64(math.Round(value)), quantities[lower].Format)
+}
+
+// findStddev returns the population standard deviation of the property across nodes
+func findStddev(nodes []corev1.Node, property string) *resource.Quantity {
+	values := []float64{}
+	var format resource.Format
+
+	for _, node := range nodes {
+		if quant := getQuantity(node, property); quant != nil {
+			values = append(values, float64(quant.MilliValue()))
+			format = quant.Format
+		}
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	mean := 0.0
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+
+	variance := 0.0
+	for _, value := range values {
+		variance += (value - mean) * (value - mean)
+	}
+	variance /= float64(len(values))
+
+	return resource.NewMilliQuantity(int64(math.Round(math.Sqrt(variance))), format)
+}
+
 func findMin(nodes []corev1.Node, property string) *resource.Quantity {
 	var min *resource.Quantity
 
@@ -313,6 +1322,33 @@ func findMax(nodes []corev1.Node, property string) *resource.Quantity {
 	return max
 }
 
+// nodePlatform returns the value of the kubernetes.io/os or kubernetes.io/arch label, falling back to what the
+// kubelet reported for nodes that do not have the label
+func nodePlatform(node corev1.Node, label string, reported string) string {
+	if value, ok := node.Labels[label]; ok {
+		return value
+	}
+	return reported
+}
+
+// compareKubeletVersion ignores pre-release and build metadata, so a provider build such as v1.28.3-eks-4f4795d
+// or v1.28.3+k3s1 is treated as 1.28.3
+func compareKubeletVersion(kubeletVersion string, constraint string) (bool, error) {
+	version, err := parseTolerantVersion(kubeletVersion)
+	if err != nil {
+		return false, err
+	}
+	version.Pre = nil
+	version.Build = nil
+
+	matches, err := parseVersionConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+
+	return matches(version), nil
+}
+
 func nodeMatchesFilters(node corev1.Node, filters *troubleshootv1beta2.NodeResourceFilters) (bool, error) {
 	if filters == nil {
 		return true, nil
@@ -322,11 +1358,104 @@ func nodeMatchesFilters(node corev1.Node, filters *troubleshootv1beta2.NodeResou
 	if filters.Selector != nil {
 		for k, v := range filters.Selector.MatchLabel {
 			if l, found := node.Labels[k]; !found || l != v {
-				return false, errors.Errorf("failed to match label %s", k)
+				return false, nil
+			}
+		}
+		for k, v := range filters.Selector.MatchAnnotation {
+			if a, found := node.Annotations[k]; !found || a != v {
+				return false, nil
+			}
+		}
+		for _, expression := range filters.Selector.MatchExpressions {
+			isMatch, err := nodeMatchesExpression(node, expression)
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to evaluate match expression for key %s", expression.Key)
+			}
+			if !isMatch {
+				return false, nil
 			}
 		}
 	}
 
+	if filters.Ready && !isNodeReady(node) {
+		return false, nil
+	}
+
+	// readyFor skips nodes that only just became ready, such as after a restart, as they may not be ready for long
+	if filters.ReadyFor != "" {
+		readyFor, err := time.ParseDuration(filters.ReadyFor)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to parse ready for")
+		}
+		condition := nodeCondition(node, corev1.NodeReady)
+		if condition == nil || condition.Status != corev1.ConditionTrue {
+			return false, nil
+		}
+		if time.Since(condition.LastTransitionTime.Time) < readyFor {
+			return false, nil
+		}
+	}
+
+	if filters.Schedulable && node.Spec.Unschedulable {
+		return false, nil
+	}
+
+	for _, excludeTaint := range filters.ExcludeTaints {
+		if nodeHasTaint(node, excludeTaint) {
+			return false, nil
+		}
+	}
+
+	// excludeConditions drops nodes with any of the listed conditions (e.g. DiskPressure) set to True.
+	// The Ready condition is handled separately by the ready filter.
+	for _, excludeCondition := range filters.ExcludeConditions {
+		if nodeHasCondition(node, corev1.NodeConditionType(excludeCondition)) {
+			return false, nil
+		}
+	}
+
+	if filters.MinAge != "" || filters.MaxAge != "" {
+		age := time.Since(node.CreationTimestamp.Time)
+
+		if filters.MinAge != "" {
+			minAge, err := time.ParseDuration(filters.MinAge)
+			if err != nil {
+				return false, errors.Wrap(err, "failed to parse min age")
+			}
+			if age < minAge {
+				return false, nil
+			}
+		}
+		if filters.MaxAge != "" {
+			maxAge, err := time.ParseDuration(filters.MaxAge)
+			if err != nil {
+				return false, errors.Wrap(err, "failed to parse max age")
+			}
+			if age > maxAge {
+				return false, nil
+			}
+		}
+	}
+
+	if filters.OS != "" && nodePlatform(node, corev1.LabelOSStable, node.Status.NodeInfo.OperatingSystem) != filters.OS {
+		return false, nil
+	}
+
+	if filters.Arch != "" && nodePlatform(node, corev1.LabelArchStable, node.Status.NodeInfo.Architecture) != filters.Arch {
+		return false, nil
+	}
+
+	// kubeletVersion is a semver constraint such as ">= 1.28", which is useful for counting upgraded nodes
+	if filters.KubeletVersion != "" {
+		isMatch, err := compareKubeletVersion(node.Status.NodeInfo.KubeletVersion, filters.KubeletVersion)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to compare kubelet version of node %s", node.Name)
+		}
+		if !isMatch {
+			return false, nil
+		}
+	}
+
 	if filters.CPUCapacity != "" {
 		parsed, err := resource.ParseQuantity(filters.CPUCapacity)
 		if err != nil {
@@ -413,3 +1542,136 @@ func nodeMatchesFilters(node corev1.Node, filters *troubleshootv1beta2.NodeResou
 
 	return true, nil
 }
+
+type NodeResourcesReport struct {
+	NodeCount  int
+	Properties []NodeResourcesPropertyReport
+}
+
+type NodeResourcesPropertyReport struct {
+	Property string
+	Sum      *resource.Quantity
+	Min      *resource.Quantity
+	Max      *resource.Quantity
+	Avg      *resource.Quantity
+}
+
+// GetNodeResourcesReport summarizes the capacity of the given nodes, independent of any outcomes.
+// Min and Max are nil for a property that no node reports.
+func GetNodeResourcesReport(nodes []corev1.Node) *NodeResourcesReport {
+	report := &NodeResourcesReport{
+		NodeCount: len(nodes),
+	}
+
+	for _, property := range nodeResourceProperties {
+		report.Properties = append(report.Properties, NodeResourcesPropertyReport{
+			Property: property,
+			Sum:      findSum(nodes, property),
+			Min:      findMin(nodes, property),
+			Max:      findMax(nodes, property),
+			Avg:      findAvg(nodes, property),
+		})
+	}
+
+	return report
+}
+
+// GetNodeResourcesReportFromCollected builds the capacity report from the collected nodes.json
+func GetNodeResourcesReportFromCollected(getCollectedFileContents func(string) ([]byte, error)) (*NodeResourcesReport, error) {
+	collected, err := getCollectedFileContents("cluster-resources/nodes.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get contents of nodes.json")
+	}
+
+	nodes := []corev1.Node{}
+	if err := json.Unmarshal(collected, &nodes); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal node list")
+	}
+
+	return GetNodeResourcesReport(nodes), nil
+}
+
+func isNodeReady(node corev1.Node) bool {
+	return nodeHasCondition(node, corev1.NodeReady)
+}
+
+func nodeHasCondition(node corev1.Node, conditionType corev1.NodeConditionType) bool {
+	condition := nodeCondition(node, conditionType)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+func nodeCondition(node corev1.Node, conditionType corev1.NodeConditionType) *corev1.NodeCondition {
+	for i, condition := range node.Status.Conditions {
+		if condition.Type == conditionType {
+			return &node.Status.Conditions[i]
+		}
+	}
+
+	return nil
+}
+
+// nodeHasTaint matches taints by key, and by value and effect when those are set
+func nodeHasTaint(node corev1.Node, match troubleshootv1beta2.NodeResourceTaint) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key != match.Key {
+			continue
+		}
+		if match.Value != "" && taint.Value != match.Value {
+			continue
+		}
+		if match.Effect != "" && string(taint.Effect) != match.Effect {
+			continue
+		}
+		return true
+	}
+
+	return false
+}
+
+func nodeMatchesExpression(node corev1.Node, expression troubleshootv1beta2.NodeResourceSelectorRequirement) (bool, error) {
+	value, found := node.Labels[expression.Key]
+
+	switch expression.Operator {
+	case "In":
+		if len(expression.Values) == 0 {
+			return false, errors.New("operator In requires at least one value")
+		}
+		if !found {
+			return false, nil
+		}
+		for _, v := range expression.Values {
+			if v == value {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case "NotIn":
+		if len(expression.Values) == 0 {
+			return false, errors.New("operator NotIn requires at least one value")
+		}
+		if !found {
+			return true, nil
+		}
+		for _, v := range expression.Values {
+			if v == value {
+				return false, nil
+			}
+		}
+		return true, nil
+
+	case "Exists":
+		if len(expression.Values) != 0 {
+			return false, errors.New("operator Exists does not accept values")
+		}
+		return found, nil
+
+	case "DoesNotExist":
+		if len(expression.Values) != 0 {
+			return false, errors.New("operator DoesNotExist does not accept values")
+		}
+		return !found, nil
+	}
+
+	return false, errors.Errorf("unsupported operator %q", expression.Operator)
+}
@@ -1,8 +1,13 @@
 package analyzer
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/analyze/conditional"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,6 +25,10 @@ func Test_compareNodeResourceConditionalToActual(t *testing.T) {
 			},
 			ObjectMeta: metav1.ObjectMeta{
 				Name: "node1",
+				Annotations: map[string]string{
+					"example.com/capacity-hint": "4",
+					"example.com/maintenance":   "weekly",
+				},
 			},
 			Status: corev1.NodeStatus{
 				Capacity: corev1.ResourceList{
@@ -27,12 +36,17 @@ func Test_compareNodeResourceConditionalToActual(t *testing.T) {
 					"ephemeral-storage": resource.MustParse("20959212Ki"),
 					"memory":            resource.MustParse("3999Ki"),
 					"pods":              resource.MustParse("15"),
+					"nvidia.com/gpu":    resource.MustParse("2"),
+					"example.com/fpga":  resource.MustParse("3"),
 				},
 				Allocatable: corev1.ResourceList{
 					"cpu":               resource.MustParse("1.5"),
 					"ephemeral-storage": resource.MustParse("19316009748"),
 					"memory":            resource.MustParse("16Ki"),
 					"pods":              resource.MustParse("14"),
+					"nvidia.com/gpu":    resource.MustParse("1"),
+					"hugepages-2Mi":     resource.MustParse("512Mi"),
+					"hugepages-1Gi":     resource.MustParse("2Gi"),
 				},
 			},
 		},
@@ -43,6 +57,9 @@ func Test_compareNodeResourceConditionalToActual(t *testing.T) {
 			},
 			ObjectMeta: metav1.ObjectMeta{
 				Name: "node2",
+				Annotations: map[string]string{
+					"example.com/capacity-hint": "8",
+				},
 			},
 			Status: corev1.NodeStatus{
 				Capacity: corev1.ResourceList{
@@ -50,6 +67,7 @@ func Test_compareNodeResourceConditionalToActual(t *testing.T) {
 					"ephemeral-storage": resource.MustParse("10959212Ki"),
 					"memory":            resource.MustParse("7951376Ki"),
 					"pods":              resource.MustParse("29"),
+					"amd.com/gpu":       resource.MustParse("1"),
 				},
 				Allocatable: corev1.ResourceList{
 					"cpu":               resource.MustParse("3"),
@@ -66,6 +84,7 @@ func Test_compareNodeResourceConditionalToActual(t *testing.T) {
 		conditional    string
 		totalNodeCount int
 		matchingNodes  []corev1.Node
+		tolerance      string
 		expected       bool
 		isError        bool
 	}{
@@ -85,6 +104,22 @@ func Test_compareNodeResourceConditionalToActual(t *testing.T) {
 			expected:       true,
 			isError:        false,
 		},
+		{
+			name:           "count() != 3",
+			conditional:    "count() != 3",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "<> 2",
+			conditional:    "<> 2",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        false,
+		},
 		{
 			name:           "<",
 			conditional:    "< 3",
@@ -141,6 +176,198 @@ func Test_compareNodeResourceConditionalToActual(t *testing.T) {
 			expected:       false,
 			isError:        false,
 		},
+		{
+			name:           "count() >= 2.5 (false)",
+			conditional:    "count() >= 2.5",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        false,
+		},
+		{
+			name:           "count() < 2.5 (true)",
+			conditional:    "count() < 2.5",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "min(cpuAllocatable) >= 1.5 (true)",
+			conditional:    "min(cpuAllocatable) >= 1.5",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "max(cpuAllocatable) > 2.9999 (true)",
+			conditional:    "max(cpuAllocatable) > 2.9999",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "sum(cpuAllocatable) < sum(cpuCapacity) (true)",
+			conditional:    "sum(cpuAllocatable) < sum(cpuCapacity)",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "sum(cpuAllocatable) >= sum(cpuCapacity) * 0.75 (true)",
+			conditional:    "sum(cpuAllocatable) >= sum(cpuCapacity) * 0.75",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "sum(cpuAllocatable) > sum(cpuCapacity) * 0.75 (false)",
+			conditional:    "sum(cpuAllocatable) > sum(cpuCapacity) * 0.75",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        false,
+		},
+		{
+			name:           "min(memoryAllocatable) >= max(memoryAllocatable) (false)",
+			conditional:    "min(memoryAllocatable) >= max(memoryAllocatable)",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        false,
+		},
+		{
+			name:           "count() == sum(gpuCapacity) * 0.5 (false)",
+			conditional:    "count() == sum(gpuCapacity) * 0.5",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        false,
+		},
+		{
+			name:           "max(memoryCapacity) > 4Gi * 1.5 (true)",
+			conditional:    "max(memoryCapacity) > 4Gi * 1.5",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "sum(cpuCapacity) > sum(cpuAllocatable) * x (error)",
+			conditional:    "sum(cpuCapacity) > sum(cpuAllocatable) * x",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        true,
+		},
+		{
+			name:           "count() between 2 5 (true)",
+			conditional:    "count() between 2 5",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "count() between 3 5 (false)",
+			conditional:    "count() between 3 5",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        false,
+		},
+		{
+			name:           "sum(memoryCapacity) between 4Gi 8Gi (true)",
+			conditional:    "sum(memoryCapacity) between 4Gi 8Gi",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "max(cpuCapacity) between 1 3.5 (false)",
+			conditional:    "max(cpuCapacity) between 1 3.5",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        false,
+		},
+		{
+			name:           "count() between 3 (error)",
+			conditional:    "count() between 3",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        true,
+		},
+		{
+			name:           "count(cpuCapacity in [4,8,16]) == 1 (true)",
+			conditional:    "count(cpuCapacity in [4,8,16]) == 1",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "count(cpuAllocatable in [1500m, 3]) == 2 (true)",
+			conditional:    "count(cpuAllocatable in [1500m, 3]) == 2",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "count(cpuCapacity notIn [4,8,16]) > 0 (true)",
+			conditional:    "count(cpuCapacity notIn [4,8,16]) > 0",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "count(capacity[example.com/fpga] notIn [3]) == 0 (true)",
+			conditional:    "count(capacity[example.com/fpga] notIn [3]) == 0",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "count(cpuCapacity in 4) (error)",
+			conditional:    "count(cpuCapacity in 4) > 0",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        true,
+		},
+		{
+			name:           "count(cpuCapacity in [4,,8]) (error)",
+			conditional:    "count(cpuCapacity in [4,,8]) > 0",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        true,
+		},
+		{
+			name:           "sum(cpuAllocatable) * 2 >= 9 (true)",
+			conditional:    "sum(cpuAllocatable) * 2 >= 9",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "sum(cpuAllocatable) * 2 > 9 (false)",
+			conditional:    "sum(cpuAllocatable) * 2 > 9",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        false,
+		},
 		{
 			name:           "min(memoryCapacity) < 4Gi (true)",
 			conditional:    "min(memoryCapacity) < 4Gi",
@@ -358,100 +585,2444 @@ func Test_compareNodeResourceConditionalToActual(t *testing.T) {
 			isError:        false,
 		},
 		{
-			name:           "sum(ephemeralStorageAllocatable) > 19316009748 (error)",
-			conditional:    "sum(ephemeralStorageAllocatable) > \"19316009748\"",
+			name:           "max(memoryCapacity) != 16Gi (true)",
+			conditional:    "max(memoryCapacity) != 16Gi",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "min(cpuCapacity) != 2 (false)",
+			conditional:    "min(cpuCapacity) != 2",
 			matchingNodes:  nodeData,
 			totalNodeCount: len(nodeData),
 			expected:       false,
-			isError:        true,
+			isError:        false,
 		},
-	}
-
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			req := require.New(t)
-
-			actual, err := compareNodeResourceConditionalToActual(test.conditional, test.matchingNodes, test.totalNodeCount)
-			if test.isError {
-				req.Error(err)
-			} else {
-				req.NoError(err)
-			}
-
-			assert.Equal(t, test.expected, actual)
-
-		})
-	}
-}
-
-func Test_nodeMatchesFilters(t *testing.T) {
-	node := corev1.Node{
-		Status: corev1.NodeStatus{
-			Capacity: corev1.ResourceList{
-				"attachable-volumes-aws-ebs": resource.MustParse("25"),
-				"cpu":                        resource.MustParse("2"),
-				"ephemeral-storage":          resource.MustParse("20959212Ki"),
-				"hugepages-1Gi":              resource.MustParse("0"),
-				"hugepages-2Mi":              resource.MustParse("0"),
-				"memory":                     resource.MustParse("7951376Ki"),
-				"pods":                       resource.MustParse("29"),
-			},
-			Allocatable: corev1.ResourceList{
-				"attachable-volumes-aws-ebs": resource.MustParse("25"),
-				"cpu":                        resource.MustParse("2"),
-				"ephemeral-storage":          resource.MustParse("19316009748"),
-				"hugepages-1Gi":              resource.MustParse("0"),
-				"hugepages-2Mi":              resource.MustParse("0"),
-				"memory":                     resource.MustParse("7848976Ki"),
-				"pods":                       resource.MustParse("29"),
-			},
+		{
+			name:           "avg(cpuCapacity) == 3 (true)",
+			conditional:    "avg(cpuCapacity) == 3",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
 		},
-	}
-
-	tests := []struct {
-		name         string
-		node         corev1.Node
-		filters      *troubleshootv1beta2.NodeResourceFilters
-		expectResult bool
-	}{
 		{
-			name:         "true when empty filters",
-			node:         node,
-			filters:      &troubleshootv1beta2.NodeResourceFilters{},
-			expectResult: true,
+			name:           "avg(cpuAllocatable) == 2250m (true)",
+			conditional:    "avg(cpuAllocatable) == 2250m",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
 		},
 		{
-			name:         "true while nil/missing filters",
-			node:         node,
-			expectResult: true,
+			name:           "avg(memoryCapacity) >= 4Gi (false)",
+			conditional:    "avg(memoryCapacity) >= 4Gi",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        false,
 		},
 		{
-			name: "false when allocatable memory is too high",
-			node: node,
-			filters: &troubleshootv1beta2.NodeResourceFilters{
-				MemoryAllocatable: "16Gi",
-			},
-			expectResult: false,
+			name:           "avg(memoryCapacity) >= 3Gi (true)",
+			conditional:    "avg(memoryCapacity) >= 3Gi",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
 		},
 		{
-			name: "true when allocatable memory is available",
-			node: node,
-			filters: &troubleshootv1beta2.NodeResourceFilters{
-				MemoryAllocatable: "4Gi",
-			},
-			expectResult: true,
+			name:           "avg(memoryAllocatable) with no matching nodes (error)",
+			conditional:    "avg(memoryAllocatable) == 0",
+			matchingNodes:  []corev1.Node{},
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        true,
 		},
-	}
-
+		{
+			name:           "median(cpuCapacity) == 3 (true)",
+			conditional:    "median(cpuCapacity) == 3",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "median(podCapacity) == 15 with one node (true)",
+			conditional:    "median(podCapacity) == 15",
+			matchingNodes:  nodeData[:1],
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "percentile(cpuCapacity, 90) == 3800m (true)",
+			conditional:    "percentile(cpuCapacity, 90) == 3800m",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "percentile(cpuCapacity,0) == 2 (true)",
+			conditional:    "percentile(cpuCapacity,0) == 2",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "percentile(memoryCapacity, 100) == 7951376Ki (true)",
+			conditional:    "percentile(memoryCapacity, 100) == 7951376Ki",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "percentile(memoryCapacity, 101) >= 4Gi (error)",
+			conditional:    "percentile(memoryCapacity, 101) >= 4Gi",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        true,
+		},
+		{
+			name:           "percentile(memoryCapacity) >= 4Gi (error)",
+			conditional:    "percentile(memoryCapacity) >= 4Gi",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        true,
+		},
+		{
+			name:           "stddev(cpuCapacity) == 1 (true)",
+			conditional:    "stddev(cpuCapacity) == 1",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "stddev(cpuAllocatable) == 750m (true)",
+			conditional:    "stddev(cpuAllocatable) == 750m",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "stddev(cpuAllocatable) > 2 (false)",
+			conditional:    "stddev(cpuAllocatable) > 2",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        false,
+		},
+		{
+			name:           "stddev(memoryCapacity) == 0 with one node (true)",
+			conditional:    "stddev(memoryCapacity) == 0",
+			matchingNodes:  nodeData[:1],
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "sum(gpuCapacity) == 3 (true)",
+			conditional:    "sum(gpuCapacity) == 3",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "sum(gpuAllocatable) >= 8 (false)",
+			conditional:    "sum(gpuAllocatable) >= 8",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        false,
+		},
+		{
+			name:           "min(gpuAllocatable) == 0 when a node has none (true)",
+			conditional:    "min(gpuAllocatable) == 0",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "max(gpuCapacity) == 2 (true)",
+			conditional:    "max(gpuCapacity) == 2",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "sum(capacity[\"example.com/fpga\"]) == 3 (true)",
+			conditional:    "sum(capacity[\"example.com/fpga\"]) == 3",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "min(capacity[example.com/fpga]) == 0 when a node has none (true)",
+			conditional:    "min(capacity[example.com/fpga]) == 0",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "max(allocatable[\"nvidia.com/gpu\"]) == 1 (true)",
+			conditional:    "max(allocatable[\"nvidia.com/gpu\"]) == 1",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "min(allocatable[\"memory\"]) == 16Ki (true)",
+			conditional:    "min(allocatable[\"memory\"]) == 16Ki",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "min(allocatablePercent(cpu)) == 75 (true)",
+			conditional:    "min(allocatablePercent(cpu)) == 75",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "avg(allocatablePercent(cpu)) == 75 (true)",
+			conditional:    "avg(allocatablePercent(cpu)) == 75",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "max(allocatablePercent(pods)) == 93.333 (true)",
+			conditional:    "max(allocatablePercent(pods)) == 93.333",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "min(allocatablePercent(memory)) >= 80 (false)",
+			conditional:    "min(allocatablePercent(memory)) >= 80",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        false,
+		},
+		{
+			name:           "max(allocatablePercent(memory)) >= 80 (true)",
+			conditional:    "max(allocatablePercent(memory)) >= 80",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "max(allocatablePercent(example.com/fpga)) == 0 skips nodes without capacity (true)",
+			conditional:    "max(allocatablePercent(example.com/fpga)) == 0",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "min(memoryCapacity) with no matching nodes (error)",
+			conditional:    "min(memoryCapacity) >= 4Gi",
+			matchingNodes:  []corev1.Node{},
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        true,
+		},
+		{
+			name:           "unknown(memoryCapacity) (error)",
+			conditional:    "unknown(memoryCapacity) >= 4Gi",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        true,
+		},
+		{
+			name:           "sum(hugepages2MiAllocatable) == 512Mi (true)",
+			conditional:    "sum(hugepages2MiAllocatable) == 512Mi",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "min(hugepages1GiAllocatable) == 0 when a node has none (true)",
+			conditional:    "min(hugepages1GiAllocatable) == 0",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "max(hugepages1GiAllocatable) >= 2Gi (true)",
+			conditional:    "max(hugepages1GiAllocatable) >= 2Gi",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "sum(hugepages2MiCapacity) == 0 (true)",
+			conditional:    "sum(hugepages2MiCapacity) == 0",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "sum(annotation(example.com/capacity-hint)) == 12 (true)",
+			conditional:    "sum(annotation(example.com/capacity-hint)) == 12",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "min(annotation(example.com/capacity-hint)) == 4 (true)",
+			conditional:    "min(annotation(example.com/capacity-hint)) == 4",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "max(annotation(example.com/capacity-hint)) > 8 (false)",
+			conditional:    "max(annotation(example.com/capacity-hint)) > 8",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        false,
+		},
+		{
+			name:           "sum(annotation(example.com/missing)) == 0 (true)",
+			conditional:    "sum(annotation(example.com/missing)) == 0",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "sum(annotation(example.com/maintenance)) == 0 (true)",
+			conditional:    "sum(annotation(example.com/maintenance)) == 0",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "percent() >= 80 when half of the nodes match (false)",
+			conditional:    "percent() >= 80",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData) * 2,
+			expected:       false,
+			isError:        false,
+		},
+		{
+			name:           "percent() == 50% when half of the nodes match (true)",
+			conditional:    "percent() == 50%",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData) * 2,
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "count() >= countAll() * 0.5 (true)",
+			conditional:    "count() >= countAll() * 0.5",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData) * 2,
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "percent() > 0 without nodes (error)",
+			conditional:    "percent() > 0",
+			matchingNodes:  []corev1.Node{},
+			totalNodeCount: 0,
+			expected:       false,
+			isError:        true,
+		},
+		{
+			name:           "max(memoryCapacity) == 8G without a tolerance (false)",
+			conditional:    "max(memoryCapacity) == 8G",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        false,
+		},
+		{
+			name:           "max(memoryCapacity) == 8G within a 2% tolerance (true)",
+			conditional:    "max(memoryCapacity) == 8G",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			tolerance:      "2%",
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "max(memoryCapacity) != 8G within a 2% tolerance (false)",
+			conditional:    "max(memoryCapacity) != 8G",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			tolerance:      "2%",
+			expected:       false,
+			isError:        false,
+		},
+		{
+			name:           "max(memoryCapacity) == 8G outside a 1% tolerance (false)",
+			conditional:    "max(memoryCapacity) == 8G",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			tolerance:      "1%",
+			expected:       false,
+			isError:        false,
+		},
+		{
+			name:           "max(memoryCapacity) == 7951375Ki within a 1Ki tolerance (true)",
+			conditional:    "max(memoryCapacity) == 7951375Ki",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			tolerance:      "1Ki",
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "max(memoryCapacity) > 8G ignores the tolerance (true)",
+			conditional:    "max(memoryCapacity) > 8G",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			tolerance:      "5%",
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "count() >= 3 || sum(cpuAllocatable) >= 4 (true)",
+			conditional:    "count() >= 3 || sum(cpuAllocatable) >= 4",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "count() >= 2 && sum(cpuAllocatable) >= 48 (false)",
+			conditional:    "count() >= 2 && sum(cpuAllocatable) >= 48",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        false,
+		},
+		{
+			name:           "min(memoryCapacity) >= 4Ki && count() == 2 || sum(cpuCapacity) > 100 (true)",
+			conditional:    "min(memoryCapacity) >= 4Ki && count() == 2 || sum(cpuCapacity) > 100",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       true,
+			isError:        false,
+		},
+		{
+			name:           "count() < 2 || min(memoryCapacity) >= 4Gi && max(cpuCapacity) >= 4 (false)",
+			conditional:    "count() < 2 || min(memoryCapacity) >= 4Gi && max(cpuCapacity) >= 4",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        false,
+		},
+		{
+			name:           "count() >= 3 && (error)",
+			conditional:    "count() >= 3 &&",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        true,
+		},
+		{
+			name:           "sum(ephemeralStorageAllocatable) > 19316009748 (error)",
+			conditional:    "sum(ephemeralStorageAllocatable) > \"19316009748\"",
+			matchingNodes:  nodeData,
+			totalNodeCount: len(nodeData),
+			expected:       false,
+			isError:        true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			tolerance, err := conditional.ParseTolerance(test.tolerance)
+			req.NoError(err)
+
+			actual, err := compareNodeResourceConditionalToActual(test.conditional, test.matchingNodes, test.totalNodeCount, tolerance)
+			if test.isError {
+				req.Error(err)
+			} else {
+				req.NoError(err)
+			}
+
+			assert.Equal(t, test.expected, actual)
+
+		})
+	}
+}
+
+func Test_nodeMatchesFilters(t *testing.T) {
+	node := corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				"topology.kubernetes.io/zone": "us-east-1a",
+			},
+			Annotations: map[string]string{
+				"example.com/maintenance": "weekly",
+			},
+		},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				"attachable-volumes-aws-ebs": resource.MustParse("25"),
+				"cpu":                        resource.MustParse("2"),
+				"ephemeral-storage":          resource.MustParse("20959212Ki"),
+				"hugepages-1Gi":              resource.MustParse("0"),
+				"hugepages-2Mi":              resource.MustParse("0"),
+				"memory":                     resource.MustParse("7951376Ki"),
+				"pods":                       resource.MustParse("29"),
+			},
+			Allocatable: corev1.ResourceList{
+				"attachable-volumes-aws-ebs": resource.MustParse("25"),
+				"cpu":                        resource.MustParse("2"),
+				"ephemeral-storage":          resource.MustParse("19316009748"),
+				"hugepages-1Gi":              resource.MustParse("0"),
+				"hugepages-2Mi":              resource.MustParse("0"),
+				"memory":                     resource.MustParse("7848976Ki"),
+				"pods":                       resource.MustParse("29"),
+			},
+		},
+	}
+
+	readyNode := *node.DeepCopy()
+	readyNode.Status.Conditions = []corev1.NodeCondition{
+		{
+			Type:   corev1.NodeReady,
+			Status: corev1.ConditionTrue,
+		},
+	}
+
+	notReadyNode := *node.DeepCopy()
+	notReadyNode.Status.Conditions = []corev1.NodeCondition{
+		{
+			Type:   corev1.NodeReady,
+			Status: corev1.ConditionFalse,
+		},
+	}
+
+	diskPressureNode := *readyNode.DeepCopy()
+	diskPressureNode.Status.Conditions = append(diskPressureNode.Status.Conditions, corev1.NodeCondition{
+		Type:   corev1.NodeDiskPressure,
+		Status: corev1.ConditionTrue,
+	}, corev1.NodeCondition{
+		Type:   corev1.NodeMemoryPressure,
+		Status: corev1.ConditionFalse,
+	})
+
+	flappingNode := *node.DeepCopy()
+	flappingNode.Status.Conditions = []corev1.NodeCondition{
+		{
+			Type:               corev1.NodeReady,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Minute)),
+		},
+	}
+
+	settledNode := *flappingNode.DeepCopy()
+	settledNode.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-time.Hour))
+
+	cordonedNode := *node.DeepCopy()
+	cordonedNode.Spec.Unschedulable = true
+
+	newNode := *node.DeepCopy()
+	newNode.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+
+	oldNode := *node.DeepCopy()
+	oldNode.CreationTimestamp = metav1.NewTime(time.Now().Add(-72 * time.Hour))
+
+	controlPlaneNode := *node.DeepCopy()
+	controlPlaneNode.Spec.Taints = []corev1.Taint{
+		{
+			Key:    "node-role.kubernetes.io/control-plane",
+			Effect: corev1.TaintEffectNoSchedule,
+		},
+	}
+
+	upgradedNode := *node.DeepCopy()
+	upgradedNode.Status.NodeInfo.KubeletVersion = "v1.28.3-eks-4f4795d"
+
+	oldKubeletNode := *node.DeepCopy()
+	oldKubeletNode.Status.NodeInfo.KubeletVersion = "v1.27.7"
+
+	armNode := *node.DeepCopy()
+	armNode.Labels = map[string]string{
+		"kubernetes.io/os":   "linux",
+		"kubernetes.io/arch": "arm64",
+	}
+
+	unlabeledNode := *node.DeepCopy()
+	unlabeledNode.Status.NodeInfo.OperatingSystem = "linux"
+	unlabeledNode.Status.NodeInfo.Architecture = "amd64"
+
+	tests := []struct {
+		name         string
+		node         corev1.Node
+		filters      *troubleshootv1beta2.NodeResourceFilters
+		expectResult bool
+	}{
+		{
+			name:         "true when empty filters",
+			node:         node,
+			filters:      &troubleshootv1beta2.NodeResourceFilters{},
+			expectResult: true,
+		},
+		{
+			name:         "true while nil/missing filters",
+			node:         node,
+			expectResult: true,
+		},
+		{
+			name: "false when allocatable memory is too high",
+			node: node,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				MemoryAllocatable: "16Gi",
+			},
+			expectResult: false,
+		},
+		{
+			name: "true when allocatable memory is available",
+			node: node,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				MemoryAllocatable: "4Gi",
+			},
+			expectResult: true,
+		},
+		{
+			name: "true when annotation matches",
+			node: node,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Selector: &troubleshootv1beta2.NodeResourceSelectors{
+					MatchAnnotation: map[string]string{
+						"example.com/maintenance": "weekly",
+					},
+				},
+			},
+			expectResult: true,
+		},
+		{
+			name: "false when annotation value differs",
+			node: node,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Selector: &troubleshootv1beta2.NodeResourceSelectors{
+					MatchAnnotation: map[string]string{
+						"example.com/maintenance": "daily",
+					},
+				},
+			},
+			expectResult: false,
+		},
+		{
+			name: "false when annotation is absent",
+			node: node,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Selector: &troubleshootv1beta2.NodeResourceSelectors{
+					MatchAnnotation: map[string]string{
+						"example.com/missing": "true",
+					},
+				},
+			},
+			expectResult: false,
+		},
+		{
+			name: "true when node is ready",
+			node: readyNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Ready: true,
+			},
+			expectResult: true,
+		},
+		{
+			name: "false when node is not ready",
+			node: notReadyNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Ready: true,
+			},
+			expectResult: false,
+		},
+		{
+			name: "false when node has no ready condition",
+			node: node,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Ready: true,
+			},
+			expectResult: false,
+		},
+		{
+			name:         "true when node is not ready and ready filter is unset",
+			node:         notReadyNode,
+			filters:      &troubleshootv1beta2.NodeResourceFilters{},
+			expectResult: true,
+		},
+		{
+			name: "false when node became ready within ready for",
+			node: flappingNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				ReadyFor: "5m",
+			},
+			expectResult: false,
+		},
+		{
+			name: "true when node has been ready for longer than ready for",
+			node: settledNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				ReadyFor: "5m",
+			},
+			expectResult: true,
+		},
+		{
+			name: "false when node is not ready with ready for",
+			node: notReadyNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				ReadyFor: "5m",
+			},
+			expectResult: false,
+		},
+		{
+			name: "false when node has an excluded condition",
+			node: diskPressureNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				ExcludeConditions: []string{"MemoryPressure", "DiskPressure"},
+			},
+			expectResult: false,
+		},
+		{
+			name: "true when excluded condition is false",
+			node: diskPressureNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				ExcludeConditions: []string{"MemoryPressure", "PIDPressure"},
+			},
+			expectResult: true,
+		},
+		{
+			name: "true when ready node has no excluded conditions",
+			node: readyNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Ready:             true,
+				ExcludeConditions: []string{"DiskPressure"},
+			},
+			expectResult: true,
+		},
+		{
+			name: "false when node is younger than min age",
+			node: newNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				MinAge: "10m",
+			},
+			expectResult: false,
+		},
+		{
+			name: "true when node is older than min age",
+			node: oldNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				MinAge: "10m",
+			},
+			expectResult: true,
+		},
+		{
+			name: "false when node is older than max age",
+			node: oldNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				MinAge: "10m",
+				MaxAge: "24h",
+			},
+			expectResult: false,
+		},
+		{
+			name: "true when node is younger than max age",
+			node: newNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				MaxAge: "24h",
+			},
+			expectResult: true,
+		},
+		{
+			name: "true when kubelet version is in range",
+			node: upgradedNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				KubeletVersion: ">= 1.28",
+			},
+			expectResult: true,
+		},
+		{
+			name: "false when kubelet version is out of range",
+			node: oldKubeletNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				KubeletVersion: ">= 1.28",
+			},
+			expectResult: false,
+		},
+		{
+			name: "true when os and arch labels match",
+			node: armNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				OS:   "linux",
+				Arch: "arm64",
+			},
+			expectResult: true,
+		},
+		{
+			name: "false when arch label does not match",
+			node: armNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Arch: "amd64",
+			},
+			expectResult: false,
+		},
+		{
+			name: "true when unlabeled node reports a matching arch",
+			node: unlabeledNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				OS:   "linux",
+				Arch: "amd64",
+			},
+			expectResult: true,
+		},
+		{
+			name: "false when node is cordoned",
+			node: cordonedNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Schedulable: true,
+			},
+			expectResult: false,
+		},
+		{
+			name: "true when node is schedulable",
+			node: node,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Schedulable: true,
+			},
+			expectResult: true,
+		},
+		{
+			name:         "true when node is cordoned and schedulable filter is unset",
+			node:         cordonedNode,
+			filters:      &troubleshootv1beta2.NodeResourceFilters{},
+			expectResult: true,
+		},
+		{
+			name: "false when node has an excluded taint",
+			node: controlPlaneNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				ExcludeTaints: []troubleshootv1beta2.NodeResourceTaint{
+					{
+						Key:    "node-role.kubernetes.io/control-plane",
+						Effect: "NoSchedule",
+					},
+				},
+			},
+			expectResult: false,
+		},
+		{
+			name: "false when node has an excluded taint key with any effect",
+			node: controlPlaneNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				ExcludeTaints: []troubleshootv1beta2.NodeResourceTaint{
+					{
+						Key: "node-role.kubernetes.io/control-plane",
+					},
+				},
+			},
+			expectResult: false,
+		},
+		{
+			name: "true when excluded taint effect differs",
+			node: controlPlaneNode,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				ExcludeTaints: []troubleshootv1beta2.NodeResourceTaint{
+					{
+						Key:    "node-role.kubernetes.io/control-plane",
+						Effect: "NoExecute",
+					},
+				},
+			},
+			expectResult: true,
+		},
+		{
+			name: "true when node has no taints",
+			node: node,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				ExcludeTaints: []troubleshootv1beta2.NodeResourceTaint{
+					{
+						Key: "node-role.kubernetes.io/control-plane",
+					},
+				},
+			},
+			expectResult: true,
+		},
+		{
+			name: "true when label matches",
+			node: node,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Selector: &troubleshootv1beta2.NodeResourceSelectors{
+					MatchLabel: map[string]string{
+						"topology.kubernetes.io/zone": "us-east-1a",
+					},
+				},
+			},
+			expectResult: true,
+		},
+		{
+			name: "false when label value differs",
+			node: node,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Selector: &troubleshootv1beta2.NodeResourceSelectors{
+					MatchLabel: map[string]string{
+						"topology.kubernetes.io/zone": "us-east-1b",
+					},
+				},
+			},
+			expectResult: false,
+		},
+		{
+			name: "false when label is missing",
+			node: node,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Selector: &troubleshootv1beta2.NodeResourceSelectors{
+					MatchLabel: map[string]string{
+						"node-role.kubernetes.io/worker": "",
+					},
+				},
+			},
+			expectResult: false,
+		},
+		{
+			name: "true when zone is in the expression values",
+			node: node,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Selector: &troubleshootv1beta2.NodeResourceSelectors{
+					MatchExpressions: []troubleshootv1beta2.NodeResourceSelectorRequirement{
+						{
+							Key:      "topology.kubernetes.io/zone",
+							Operator: "In",
+							Values:   []string{"us-east-1a", "us-east-1b"},
+						},
+					},
+				},
+			},
+			expectResult: true,
+		},
+		{
+			name: "false when zone is not in the expression values",
+			node: node,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Selector: &troubleshootv1beta2.NodeResourceSelectors{
+					MatchExpressions: []troubleshootv1beta2.NodeResourceSelectorRequirement{
+						{
+							Key:      "topology.kubernetes.io/zone",
+							Operator: "In",
+							Values:   []string{"us-east-1c"},
+						},
+					},
+				},
+			},
+			expectResult: false,
+		},
+		{
+			name: "false when zone is in the NotIn values",
+			node: node,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Selector: &troubleshootv1beta2.NodeResourceSelectors{
+					MatchExpressions: []troubleshootv1beta2.NodeResourceSelectorRequirement{
+						{
+							Key:      "topology.kubernetes.io/zone",
+							Operator: "NotIn",
+							Values:   []string{"us-east-1a"},
+						},
+					},
+				},
+			},
+			expectResult: false,
+		},
+		{
+			name: "true when label exists and other label does not",
+			node: node,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Selector: &troubleshootv1beta2.NodeResourceSelectors{
+					MatchExpressions: []troubleshootv1beta2.NodeResourceSelectorRequirement{
+						{
+							Key:      "topology.kubernetes.io/zone",
+							Operator: "Exists",
+						},
+						{
+							Key:      "node-role.kubernetes.io/control-plane",
+							Operator: "DoesNotExist",
+						},
+					},
+				},
+			},
+			expectResult: true,
+		},
+		{
+			name: "true when match labels and expressions both match",
+			node: node,
+			filters: &troubleshootv1beta2.NodeResourceFilters{
+				Selector: &troubleshootv1beta2.NodeResourceSelectors{
+					MatchLabel: map[string]string{
+						"topology.kubernetes.io/zone": "us-east-1a",
+					},
+					MatchExpressions: []troubleshootv1beta2.NodeResourceSelectorRequirement{
+						{
+							Key:      "topology.kubernetes.io/zone",
+							Operator: "NotIn",
+							Values:   []string{"us-east-1b"},
+						},
+					},
+				},
+			},
+			expectResult: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			actual, err := nodeMatchesFilters(test.node, test.filters)
+			req.NoError(err)
+
+			assert.Equal(t, test.expectResult, actual)
+
+		})
+	}
+}
+
+func Test_GetNodeResourcesReport(t *testing.T) {
+	nodes := []corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node1",
+			},
+			Status: corev1.NodeStatus{
+				Capacity: corev1.ResourceList{
+					"cpu":    resource.MustParse("2"),
+					"memory": resource.MustParse("4Gi"),
+					"pods":   resource.MustParse("110"),
+				},
+				Allocatable: corev1.ResourceList{
+					"cpu":    resource.MustParse("1500m"),
+					"memory": resource.MustParse("3Gi"),
+					"pods":   resource.MustParse("100"),
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node2",
+			},
+			Status: corev1.NodeStatus{
+				Capacity: corev1.ResourceList{
+					"cpu":    resource.MustParse("4"),
+					"memory": resource.MustParse("8Gi"),
+					"pods":   resource.MustParse("110"),
+				},
+				Allocatable: corev1.ResourceList{
+					"cpu":    resource.MustParse("3500m"),
+					"memory": resource.MustParse("7Gi"),
+					"pods":   resource.MustParse("100"),
+				},
+			},
+		},
+	}
+
+	req := require.New(t)
+
+	report := GetNodeResourcesReport(nodes)
+	req.Equal(2, report.NodeCount)
+	req.Len(report.Properties, len(nodeResourceProperties))
+
+	byProperty := map[string]NodeResourcesPropertyReport{}
+	for _, property := range report.Properties {
+		byProperty[property.Property] = property
+	}
+
+	cpu := byProperty["cpuCapacity"]
+	assert.Equal(t, 0, cpu.Sum.Cmp(resource.MustParse("6")))
+	assert.Equal(t, 0, cpu.Min.Cmp(resource.MustParse("2")))
+	assert.Equal(t, 0, cpu.Max.Cmp(resource.MustParse("4")))
+	assert.Equal(t, 0, cpu.Avg.Cmp(resource.MustParse("3")))
+
+	cpuAllocatable := byProperty["cpuAllocatable"]
+	assert.Equal(t, 0, cpuAllocatable.Sum.Cmp(resource.MustParse("5")))
+	assert.Equal(t, 0, cpuAllocatable.Avg.Cmp(resource.MustParse("2500m")))
+
+	memory := byProperty["memoryAllocatable"]
+	assert.Equal(t, 0, memory.Sum.Cmp(resource.MustParse("10Gi")))
+	assert.Equal(t, 0, memory.Min.Cmp(resource.MustParse("3Gi")))
+	assert.Equal(t, 0, memory.Max.Cmp(resource.MustParse("7Gi")))
+	assert.Equal(t, 0, memory.Avg.Cmp(resource.MustParse("5Gi")))
+
+	pods := byProperty["podAllocatable"]
+	assert.Equal(t, 0, pods.Sum.Cmp(resource.MustParse("200")))
+	assert.Equal(t, 0, pods.Avg.Cmp(resource.MustParse("100")))
+
+	gpu := byProperty["gpuAllocatable"]
+	assert.True(t, gpu.Sum.IsZero())
+	assert.True(t, gpu.Max.IsZero())
+}
+
+func Test_analyzeNodeResources(t *testing.T) {
+	nodes := []corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node1",
+			},
+			Status: corev1.NodeStatus{
+				Capacity: corev1.ResourceList{
+					"cpu":    resource.MustParse("2"),
+					"memory": resource.MustParse("4Gi"),
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node2",
+			},
+			Status: corev1.NodeStatus{
+				Capacity: corev1.ResourceList{
+					"cpu":    resource.MustParse("4"),
+					"memory": resource.MustParse("8Gi"),
+				},
+			},
+		},
+	}
+
+	statefulsets := `[{"metadata": {"name": "database", "namespace": "default"}}]`
+	deployments := `[{"metadata": {"name": "prod-api", "namespace": "default", "labels": {"app.kubernetes.io/name": "api", "app.kubernetes.io/instance": "prod"}}}]`
+
+	allocatableNodes := []corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node1",
+			},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					"cpu":    resource.MustParse("2"),
+					"memory": resource.MustParse("8Gi"),
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node2",
+			},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					"cpu":    resource.MustParse("2"),
+					"memory": resource.MustParse("8Gi"),
+				},
+			},
+		},
+	}
+	nodeMetrics := `[
+  {"metadata": {"name": "node1"}, "usage": {"cpu": "1500m", "memory": "7680Mi"}},
+  {"metadata": {"name": "node2"}, "usage": {"cpu": "250m", "memory": "2Gi"}}
+]`
+
+	tests := []struct {
+		name     string
+		nodes    []corev1.Node
+		files    map[string]string
+		analyzer *troubleshootv1beta2.NodeResources
+		expected *AnalyzeResult
+		isError  bool
+	}{
+		{
+			name:  "templated message with node names",
+			nodes: nodes,
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "count() < 3",
+							Message: "{{ .NodeCount }} nodes matched: {{ .NodeNames }}",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Node Resources",
+				Message: "2 nodes matched: [node1 node2]",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "templated message with computed value",
+			nodes: nodes,
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    "sum(memoryCapacity) >= 8Gi",
+							Message: "The cluster has {{ .Value }} of memory",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "Node Resources",
+				Message: "The cluster has 12Gi of memory",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "default message for combined conditionals",
+			nodes: nodes,
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When: "count() >= 3 || sum(cpuCapacity) >= 6",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "Node Resources",
+				Message: "sum(cpuCapacity) is 6, which is >= 6",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "default message when outcome message is empty",
+			nodes: nodes,
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When: "sum(cpuCapacity) < 16",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Node Resources",
+				Message: "sum(cpuCapacity) is 6, which is < 16",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "default message for the implicit count",
+			nodes: nodes,
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When: "< 3",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsWarn:  true,
+				Title:   "Node Resources",
+				Message: "count() is 2, which is < 3",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "onUpdate outcomes when the statefulset exists",
+			nodes: nodes,
+			files: map[string]string{
+				"cluster-resources/statefulsets/default.json": statefulsets,
+			},
+			analyzer: &troubleshootv1beta2.NodeResources{
+				StatefulSet: &troubleshootv1beta2.NodeResourcesWorkload{
+					Namespace: "default",
+					Name:      "database",
+				},
+				OnInstall: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							Message: "install",
+						},
+					},
+				},
+				OnUpdate: []*troubleshootv1beta2.Outcome{
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "update",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "Node Resources",
+				Message: "update",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "onInstall outcomes when the statefulset does not exist",
+			nodes: nodes,
+			files: map[string]string{
+				"cluster-resources/statefulsets/default.json": statefulsets,
+			},
+			analyzer: &troubleshootv1beta2.NodeResources{
+				StatefulSet: &troubleshootv1beta2.NodeResourcesWorkload{
+					Namespace: "default",
+					Name:      "cache",
+				},
+				OnInstall: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							Message: "install",
+						},
+					},
+				},
+				OnUpdate: []*troubleshootv1beta2.Outcome{
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "update",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Node Resources",
+				Message: "install",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "skipped when the statefulset exists without onUpdate outcomes",
+			nodes: nodes,
+			files: map[string]string{
+				"cluster-resources/statefulsets/default.json": statefulsets,
+			},
+			analyzer: &troubleshootv1beta2.NodeResources{
+				StatefulSet: &troubleshootv1beta2.NodeResourcesWorkload{
+					Namespace: "default",
+					Name:      "database",
+				},
+				OnInstall: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							Message: "install",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsWarn:    true,
+				IsSkipped: true,
+				Title:     "Node Resources",
+				Message:   "Skipped: the workload already exists and no onUpdate outcomes were provided",
+				IconKey:   "kubernetes_node_resources",
+				IconURI:   "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "onUpdate outcomes when the daemonset exists",
+			nodes: nodes,
+			files: map[string]string{
+				"cluster-resources/daemonsets/kube-system.json": `[{"metadata": {"name": "agent", "namespace": "kube-system"}}]`,
+			},
+			analyzer: &troubleshootv1beta2.NodeResources{
+				DaemonSet: &troubleshootv1beta2.NodeResourcesWorkload{
+					Namespace: "kube-system",
+					Name:      "agent",
+				},
+				OnInstall: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							Message: "install",
+						},
+					},
+				},
+				OnUpdate: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							Message: "update",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsWarn:  true,
+				Title:   "Node Resources",
+				Message: "update",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "onUpdate outcomes when a deployment matches the selector",
+			nodes: nodes,
+			files: map[string]string{
+				"cluster-resources/deployments/default.json": deployments,
+			},
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Deployment: &troubleshootv1beta2.NodeResourcesWorkload{
+					Namespace: "default",
+					Selector: map[string]string{
+						"app.kubernetes.io/name":     "api",
+						"app.kubernetes.io/instance": "prod",
+					},
+				},
+				OnInstall: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							Message: "install",
+						},
+					},
+				},
+				OnUpdate: []*troubleshootv1beta2.Outcome{
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "update",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "Node Resources",
+				Message: "update",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "onInstall outcomes when the named deployment does not match the selector",
+			nodes: nodes,
+			files: map[string]string{
+				"cluster-resources/deployments/default.json": deployments,
+			},
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Deployment: &troubleshootv1beta2.NodeResourcesWorkload{
+					Namespace: "default",
+					Name:      "prod-api",
+					Selector: map[string]string{
+						"app.kubernetes.io/instance": "staging",
+					},
+				},
+				OnInstall: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							Message: "install",
+						},
+					},
+				},
+				OnUpdate: []*troubleshootv1beta2.Outcome{
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "update",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Node Resources",
+				Message: "install",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "onInstall outcomes when the deployment namespace was not collected",
+			nodes: nodes,
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Deployment: &troubleshootv1beta2.NodeResourcesWorkload{
+					Namespace: "missing",
+					Name:      "api",
+				},
+				OnInstall: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							Message: "install",
+						},
+					},
+				},
+				OnUpdate: []*troubleshootv1beta2.Outcome{
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "update",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Node Resources",
+				Message: "install",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "error when the collected deployments cannot be unmarshalled",
+			nodes: nodes,
+			files: map[string]string{
+				"cluster-resources/deployments/default.json": "{",
+			},
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Deployment: &troubleshootv1beta2.NodeResourcesWorkload{
+					Namespace: "default",
+					Name:      "api",
+				},
+			},
+			isError: true,
+		},
+		{
+			name:  "error when both a statefulset and a daemonset are referenced",
+			nodes: nodes,
+			analyzer: &troubleshootv1beta2.NodeResources{
+				StatefulSet: &troubleshootv1beta2.NodeResourcesWorkload{
+					Namespace: "default",
+					Name:      "database",
+				},
+				DaemonSet: &troubleshootv1beta2.NodeResourcesWorkload{
+					Namespace: "kube-system",
+					Name:      "agent",
+				},
+			},
+			isError: true,
+		},
+		{
+			name:  "error when both a deployment and a statefulset are referenced",
+			nodes: nodes,
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Deployment: &troubleshootv1beta2.NodeResourcesWorkload{
+					Namespace: "default",
+					Name:      "api",
+				},
+				StatefulSet: &troubleshootv1beta2.NodeResourcesWorkload{
+					Namespace: "default",
+					Name:      "database",
+				},
+			},
+			isError: true,
+		},
+		{
+			name:  "memory usage percent of allocatable",
+			nodes: allocatableNodes,
+			files: map[string]string{
+				"cluster-resources/node-metrics.json": nodeMetrics,
+			},
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "max(usagePercent(memory)) > 90",
+							Message: "A node is using more than 90% of its memory",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "Memory usage is fine",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsWarn:  true,
+				Title:   "Node Resources",
+				Message: "A node is using more than 90% of its memory",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "total cpu usage",
+			nodes: allocatableNodes,
+			files: map[string]string{
+				"cluster-resources/node-metrics.json": nodeMetrics,
+			},
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "sum(cpuUsage) > 1500m",
+							Message: "Too busy",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "Not busy",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Node Resources",
+				Message: "Too busy",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "cpu requests percent of allocatable",
+			nodes: allocatableNodes,
+			files: map[string]string{
+				"cluster-resources/node-pod-resources.json": `[{"nodeName": "node1", "podCount": 3, "requests": {"cpu": "1900m"}, "limits": {}}]`,
+			},
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "max(requestsPercent(cpu)) > 90",
+							Message: "A node has more than 90% of its cpu requested",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "CPU requests are fine",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsWarn:  true,
+				Title:   "Node Resources",
+				Message: "A node has more than 90% of its cpu requested",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "allocatable memory below the largest requests",
+			nodes: allocatableNodes,
+			files: map[string]string{
+				"cluster-resources/node-pod-resources.json": `[{"nodeName": "node1", "podCount": 3, "requests": {"memory": "6Gi"}, "limits": {}}, {"nodeName": "node2", "podCount": 5, "requests": {"memory": "9Gi"}, "limits": {}}]`,
+			},
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "min(memoryAllocatable) < maxRequests",
+							Message: "A node could not fit the pods of the busiest node",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    "min(memoryAllocatable) >= maxRequests",
+							Message: "Every node can fit the pods of the busiest node",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Node Resources",
+				Message: "A node could not fit the pods of the busiest node",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "requested memory alias",
+			nodes: allocatableNodes,
+			files: map[string]string{
+				"cluster-resources/node-pod-resources.json": `[{"nodeName": "node1", "podCount": 3, "requests": {"memory": "6Gi"}, "limits": {}}, {"nodeName": "node2", "podCount": 5, "requests": {"memory": "9Gi"}, "limits": {}}]`,
+			},
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "max(requestedMemory) > min(memoryAllocatable)",
+							Message: "A node has more memory requested than allocatable",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "Memory requests fit",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsWarn:  true,
+				Title:   "Node Resources",
+				Message: "A node has more memory requested than allocatable",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "allocatable cpu at a multiple of the largest requests",
+			nodes: allocatableNodes,
+			files: map[string]string{
+				"cluster-resources/node-pod-resources.json": `[{"nodeName": "node1", "podCount": 3, "requests": {"cpu": "500m"}, "limits": {}}, {"nodeName": "node2", "podCount": 5, "requests": {"cpu": "1"}, "limits": {}}]`,
+			},
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "min(cpuAllocatable) < maxRequests * 2",
+							Message: "Not enough headroom for the busiest node",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    "sum(cpuAllocatable) between totalRequests 8",
+							Message: "Enough cpu for the requests",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "Node Resources",
+				Message: "Enough cpu for the requests",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "target compared to count",
+			nodes: allocatableNodes,
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When: "count() < maxRequests",
+						},
+					},
+				},
+			},
+			isError: true,
+		},
+		{
+			name:  "nodes without pods have no requests",
+			nodes: allocatableNodes,
+			files: map[string]string{
+				"cluster-resources/node-pod-resources.json": `[{"nodeName": "node1", "podCount": 3, "requests": {"memory": "6Gi"}, "limits": {}}]`,
+			},
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    "min(memoryRequests) == 0",
+							Message: "A node has no memory requested",
+						},
+					},
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							Message: "Every node has memory requested",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "Node Resources",
+				Message: "A node has no memory requested",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "usage without node metrics",
+			nodes: allocatableNodes,
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "max(memoryUsage) > 4Gi",
+							Message: "Too much memory used",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsWarn:  true,
+				Title:   "Node Resources",
+				Message: "Unable to evaluate \"max(memoryUsage) > 4Gi\": no matching nodes report a value",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "no outcome matched",
+			nodes: nodes,
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "count() < 2",
+							Message: "Not enough nodes",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    "count() > 2",
+							Message: "Enough nodes",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				Title:   "Node Resources",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "no outcome matched in strict mode",
+			nodes: nodes,
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Strict: true,
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "count() < 2",
+							Message: "Not enough nodes",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    "count() > 2",
+							Message: "Enough nodes",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Node Resources",
+				Message: "No outcome matched",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "first matching outcome",
+			nodes: nodes,
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    "count() >= 1",
+							Message: "At least one node",
+						},
+					},
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "count() < 5",
+							Message: "Fewer than 5 nodes",
+						},
+					},
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "count() < 3",
+							Message: "Fewer than 3 nodes",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "Node Resources",
+				Message: "At least one node",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "most severe matching outcome",
+			nodes: nodes,
+			analyzer: &troubleshootv1beta2.NodeResources{
+				MostSevere: true,
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    "count() >= 1",
+							Message: "At least one node",
+						},
+					},
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "count() < 5",
+							Message: "Fewer than 5 nodes",
+						},
+					},
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "count() < 3",
+							Message: "Fewer than 3 nodes",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Node Resources",
+				Message: "Fewer than 3 nodes",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "whenNot",
+			nodes: nodes,
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							WhenNot: "count() >= 3",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "Enough nodes",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsWarn:  true,
+				Title:   "Node Resources",
+				Message: "count() is 2, which is not >= 3",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "when and whenNot",
+			nodes: nodes,
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "count() < 3",
+							WhenNot: "count() >= 3",
+						},
+					},
+				},
+			},
+			isError: true,
+		},
+		{
+			name:  "misspelled property",
+			nodes: nodes,
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When: "min(memoryAllcatable) < 4Gi",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "Enough memory",
+						},
+					},
+				},
+			},
+			isError: true,
+		},
+		{
+			name:  "min over an empty node list",
+			nodes: []corev1.Node{},
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "min(memoryCapacity) < 4Gi",
+							Message: "Not enough memory",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "Enough memory",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsWarn:  true,
+				Title:   "Node Resources",
+				Message: "Unable to evaluate \"min(memoryCapacity) < 4Gi\": no matching nodes report a value",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name:  "avg over an empty node list",
+			nodes: []corev1.Node{},
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "avg(gpuCapacity) < 1",
+							Message: "No GPUs",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "GPUs available",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsWarn:  true,
+				Title:   "Node Resources",
+				Message: "Unable to evaluate \"avg(gpuCapacity) < 1\": no matching nodes report a value",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+	}
+
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			req := require.New(t)
 
-			actual, err := nodeMatchesFilters(test.node, test.filters)
+			getCollectedFileContents := func(name string) ([]byte, error) {
+				if name == "cluster-resources/nodes.json" {
+					return json.Marshal(test.nodes)
+				}
+				if contents, ok := test.files[name]; ok {
+					return []byte(contents), nil
+				}
+				return nil, errors.Errorf("file %s was not collected", name)
+			}
+
+			actual, err := analyzeNodeResources(context.Background(), test.analyzer, getCollectedFileContents, NewCollectedObjectCache(getCollectedFileContents).GetCollectedObject)
+			if test.isError {
+				req.Error(err)
+				return
+			}
 			req.NoError(err)
+			req.Len(actual, 1)
 
-			assert.Equal(t, test.expectResult, actual)
+			assert.Equal(t, test.expected, actual[0])
+		})
+	}
+}
+
+func Test_validateNodeResourceConditionalProperties(t *testing.T) {
+	tests := []struct {
+		name     string
+		when     string
+		expected string
+	}{
+		{
+			name: "known properties",
+			when: "sum(cpuAllocatable) >= max(annotation(example.com/capacity-hint)) * 2",
+		},
+		{
+			name: "percentile",
+			when: "percentile(capacity[nvidia.com/gpu], 90) > 0",
+		},
+		{
+			name: "functions without a property",
+			when: "percent() >= 50",
+		},
+		{
+			name:     "misspelled property",
+			when:     "min(memoryAllcatable) < 4Gi",
+			expected: `unknown property "memoryAllcatable" in min(memoryAllcatable), valid properties are cpuCapacity, cpuAllocatable, memoryCapacity, memoryAllocatable`,
+		},
+		{
+			name: "set membership",
+			when: "count(cpuAllocatable in [4,8,16]) >= 1",
+		},
+		{
+			name: "aliases",
+			when: "sum(requestedCpu) > sum(cpuAllocatable) || max(requestedMemory) >= maxRequests",
+		},
+		{
+			name:     "misspelled alias",
+			when:     "sum(requestedCPU) > 4",
+			expected: `, requestedCpu (cpuRequests), requestedMemory (memoryRequests)`,
+		},
+		{
+			name:     "misspelled set membership property",
+			when:     "count(cpuAlocatable notIn [4,8]) == 0",
+			expected: `unknown property "cpuAlocatable" in count(cpuAlocatable notIn [4,8])`,
+		},
+		{
+			name:     "malformed set",
+			when:     "count(cpuAllocatable in [4,eight]) == 0",
+			expected: `failed to parse set member "eight"`,
+		},
+		{
+			name:     "misspelled percentile property",
+			when:     "count() > percentile(memoryCapcity, 50)",
+			expected: `unknown property "memoryCapcity" in percentile(memoryCapcity, 50)`,
+		},
+		{
+			name:     "property without a function",
+			when:     "cpuAllocatable >= cpuCapacity * 0.9",
+			expected: `property references must use a function, e.g. sum(cpuCapacity), got cpuAllocatable`,
+		},
+		{
+			name:     "desired property without a function",
+			when:     "min(cpuAllocatable) >= cpuCapacity * 0.7",
+			expected: `property references must use a function, e.g. sum(cpuCapacity), got cpuCapacity`,
+		},
+		{
+			name: "desired expression with a multiplier",
+			when: "sum(cpuAllocatable) >= sum(cpuCapacity) * 0.9",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateNodeResourceConditionalProperties(test.when)
+			if test.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expected)
+		})
+	}
+}
+
+func Test_analyzeNodeResourcesPerNode(t *testing.T) {
+	nodes := []corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node1",
+			},
+			Status: corev1.NodeStatus{
+				Capacity: corev1.ResourceList{
+					"memory": resource.MustParse("4Gi"),
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node2",
+			},
+			Status: corev1.NodeStatus{
+				Capacity: corev1.ResourceList{
+					"memory": resource.MustParse("8Gi"),
+				},
+			},
+		},
+	}
+
+	analyzer := &troubleshootv1beta2.NodeResources{
+		PerNode: true,
+		Outcomes: []*troubleshootv1beta2.Outcome{
+			{
+				Fail: &troubleshootv1beta2.SingleOutcome{
+					When:    "min(memoryCapacity) < 8Gi",
+					Message: "Not enough memory",
+				},
+			},
+			{
+				Pass: &troubleshootv1beta2.SingleOutcome{
+					Message: "Enough memory",
+				},
+			},
+		},
+	}
+
+	getCollectedFileContents := func(string) ([]byte, error) {
+		return json.Marshal(nodes)
+	}
+
+	req := require.New(t)
+
+	actual, err := analyzeNodeResourcesPerNode(context.Background(), analyzer, getCollectedFileContents, NewCollectedObjectCache(getCollectedFileContents).GetCollectedObject)
+	req.NoError(err)
+
+	expected := []*AnalyzeResult{
+		{
+			IsFail:  true,
+			Title:   "Node Resources (node1)",
+			Message: "Not enough memory",
+			IconKey: "kubernetes_node_resources",
+			IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+		},
+		{
+			IsPass:  true,
+			Title:   "Node Resources (node2)",
+			Message: "Enough memory",
+			IconKey: "kubernetes_node_resources",
+			IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+		},
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func Test_analyzeNodeResourcesGroups(t *testing.T) {
+	pool := func(name string, pool string, memory string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"pool": pool},
+			},
+			Status: corev1.NodeStatus{
+				Capacity: corev1.ResourceList{
+					"memory": resource.MustParse(memory),
+				},
+			},
+		}
+	}
+	nodes := []corev1.Node{
+		pool("app-1", "app", "16Gi"),
+		pool("app-2", "app", "16Gi"),
+		pool("db-1", "db", "32Gi"),
+		pool("gpu-1", "gpu", "64Gi"),
+	}
+
+	analyzer := &troubleshootv1beta2.NodeResources{
+		Filters: &troubleshootv1beta2.NodeResourceFilters{
+			Groups: []troubleshootv1beta2.NodeResourceGroup{
+				{
+					Name:     "app",
+					Selector: &troubleshootv1beta2.NodeResourceSelectors{MatchLabel: map[string]string{"pool": "app"}},
+				},
+				{
+					Name:     "db",
+					Selector: &troubleshootv1beta2.NodeResourceSelectors{MatchLabel: map[string]string{"pool": "db"}},
+				},
+				{
+					Name:     "search",
+					Selector: &troubleshootv1beta2.NodeResourceSelectors{MatchLabel: map[string]string{"pool": "search"}},
+				},
+			},
+		},
+		Outcomes: []*troubleshootv1beta2.Outcome{
+			{
+				Fail: &troubleshootv1beta2.SingleOutcome{
+					When:    "count() < 2",
+					Message: "Fewer than 2 nodes in the pool",
+				},
+			},
+			{
+				Pass: &troubleshootv1beta2.SingleOutcome{
+					Message: "{{ .NodeCount }} nodes in the pool",
+				},
+			},
+		},
+	}
+
+	getCollectedFileContents := func(string) ([]byte, error) {
+		return json.Marshal(nodes)
+	}
+
+	req := require.New(t)
+
+	actual, err := analyzeNodeResources(context.Background(), analyzer, getCollectedFileContents, NewCollectedObjectCache(getCollectedFileContents).GetCollectedObject)
+	req.NoError(err)
+	req.Len(actual, 3)
+
+	assert.Equal(t, "Node Resources (app)", actual[0].Title)
+	assert.True(t, actual[0].IsPass)
+	assert.Equal(t, "2 nodes in the pool", actual[0].Message)
+	assert.Equal(t, "Node Resources (db)", actual[1].Title)
+	assert.True(t, actual[1].IsFail)
+	assert.Equal(t, "Node Resources (search)", actual[2].Title)
+	assert.True(t, actual[2].IsFail)
+}
+
+func TestEvaluateNodeResources(t *testing.T) {
+	node := func(name string, cpu string, memory string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"pool": "app"},
+			},
+			Status: corev1.NodeStatus{
+				Capacity: corev1.ResourceList{
+					"cpu":    resource.MustParse(cpu),
+					"memory": resource.MustParse(memory),
+				},
+			},
+		}
+	}
+	nodes := []corev1.Node{
+		node("node-1", "4", "16Gi"),
+		node("node-2", "8", "32Gi"),
+	}
+
+	outcomes := []*troubleshootv1beta2.Outcome{
+		{
+			Fail: &troubleshootv1beta2.SingleOutcome{
+				When:    "min(memoryCapacity) < 32Gi",
+				Message: "A node has less than 32Gi of memory",
+			},
+		},
+		{
+			Pass: &troubleshootv1beta2.SingleOutcome{
+				Message: "{{ .NodeCount }} nodes have at least 32Gi of memory",
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.NodeResources
+		expected []*AnalyzeResult
+	}{
+		{
+			name: "all nodes",
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Outcomes: outcomes,
+			},
+			expected: []*AnalyzeResult{
+				{
+					IsFail:  true,
+					Title:   "Node Resources",
+					Message: "A node has less than 32Gi of memory",
+					IconKey: "kubernetes_node_resources",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+				},
+			},
+		},
+		{
+			name: "filtered",
+			analyzer: &troubleshootv1beta2.NodeResources{
+				AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{CheckName: "Large nodes"},
+				Filters: &troubleshootv1beta2.NodeResourceFilters{
+					CPUCapacity: "8",
+				},
+				Outcomes: outcomes,
+			},
+			expected: []*AnalyzeResult{
+				{
+					IsPass:  true,
+					Title:   "Large nodes",
+					Message: "1 nodes have at least 32Gi of memory",
+					IconKey: "kubernetes_node_resources",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+				},
+			},
+		},
+		{
+			name: "groups",
+			analyzer: &troubleshootv1beta2.NodeResources{
+				Filters: &troubleshootv1beta2.NodeResourceFilters{
+					Groups: []troubleshootv1beta2.NodeResourceGroup{
+						{
+							Name:     "app",
+							Selector: &troubleshootv1beta2.NodeResourceSelectors{MatchLabel: map[string]string{"pool": "app"}},
+						},
+					},
+				},
+				Outcomes: outcomes,
+			},
+			expected: []*AnalyzeResult{
+				{
+					IsFail:  true,
+					Title:   "Node Resources (app)",
+					Message: "A node has less than 32Gi of memory",
+					IconKey: "kubernetes_node_resources",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+				},
+			},
+		},
+	}
 
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := EvaluateNodeResources(nodes, test.analyzer)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
 		})
 	}
+
+	_, err := EvaluateNodeResources(nodes, &troubleshootv1beta2.NodeResources{
+		Outcomes: []*troubleshootv1beta2.Outcome{
+			{
+				Fail: &troubleshootv1beta2.SingleOutcome{When: "min(memoryCapcity) < 32Gi"},
+			},
+		},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown property "memoryCapcity"`)
+
+	_, err = EvaluateNodeResources(nodes, nil)
+	require.Error(t, err)
+}
+
+func TestInterpolateAnalyzer(t *testing.T) {
+	req := require.New(t)
+
+	analyzer := &troubleshootv1beta2.Analyze{
+		NodeResources: &troubleshootv1beta2.NodeResources{
+			Outcomes: []*troubleshootv1beta2.Outcome{
+				{
+					Fail: &troubleshootv1beta2.SingleOutcome{
+						When:    "count() < ${MIN_NODES}",
+						Message: "At least ${MIN_NODES} nodes are required",
+					},
+				},
+			},
+			OnUpdate: []*troubleshootv1beta2.Outcome{
+				{
+					Warn: &troubleshootv1beta2.SingleOutcome{
+						WhenNot: "min(memoryCapacity) >= ${MIN_MEMORY}",
+					},
+				},
+			},
+		},
+	}
+
+	interpolated, err := InterpolateAnalyzer(analyzer, map[string]string{"MIN_NODES": "3", "MIN_MEMORY": "8Gi"})
+	req.NoError(err)
+	req.Equal("count() < 3", interpolated.NodeResources.Outcomes[0].Fail.When)
+	req.Equal("At least 3 nodes are required", interpolated.NodeResources.Outcomes[0].Fail.Message)
+	req.Equal("min(memoryCapacity) >= 8Gi", interpolated.NodeResources.OnUpdate[0].Warn.WhenNot)
+
+	// the spec itself is not changed
+	req.Equal("count() < ${MIN_NODES}", analyzer.NodeResources.Outcomes[0].Fail.When)
+
+	results, err := EvaluateNodeResources([]corev1.Node{{}}, interpolated.NodeResources)
+	req.NoError(err)
+	req.Len(results, 1)
+	req.True(results[0].IsFail)
+	req.Equal("At least 3 nodes are required", results[0].Message)
+
+	_, err = InterpolateAnalyzer(analyzer, map[string]string{"MIN_NODES": "3"})
+	req.EqualError(err, "onUpdate[0].warn.whenNot: variable MIN_MEMORY is not set")
+
+	other := &troubleshootv1beta2.Analyze{ClusterVersion: &troubleshootv1beta2.ClusterVersion{}}
+	interpolated, err = InterpolateAnalyzer(other, nil)
+	req.NoError(err)
+	req.Equal(other, interpolated)
 }
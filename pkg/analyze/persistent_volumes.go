@@ -0,0 +1,205 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/analyze/conditional"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// errNoPersistentVolumeValue is returned when an aggregate such as min(storage) has no volumes to evaluate
+var errNoPersistentVolumeValue = errors.New("no persistent volumes found")
+
+type persistentVolumesMessageData struct {
+	Count int
+	Value string
+}
+
+// analyzePersistentVolumes evaluates conditionals such as "sum(storage) >= 1Ti" against the capacity of the
+// collected persistent volumes, optionally only those of a storage class or in a phase, with the count(),
+// sum(storage), min(storage), max(storage) and avg(storage) functions
+func analyzePersistentVolumes(analyzer *troubleshootv1beta2.PersistentVolumesAnalyze, getFile getCollectedFileContents) (*AnalyzeResult, error) {
+	contents, err := getFile("cluster-resources/pvs.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected persistent volumes")
+	}
+
+	pvs := []corev1.PersistentVolume{}
+	if err := json.Unmarshal(contents, &pvs); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal collected persistent volumes")
+	}
+	pvs = filterPersistentVolumes(pvs, analyzer.StorageClassName, analyzer.Phase)
+
+	title := analyzer.CheckName
+	if title == "" {
+		title = "Persistent Volumes"
+	}
+	result := &AnalyzeResult{
+		Title: title,
+	}
+
+	// ordering is important for passthrough
+	for _, outcome := range analyzer.Outcomes {
+		single := outcome.Fail
+		if single == nil {
+			single = outcome.Warn
+		}
+		if single == nil {
+			single = outcome.Pass
+		}
+		if single == nil {
+			continue
+		}
+
+		isMatch, actualValue, err := evaluatePersistentVolumesConditional(single.When, pvs)
+		if errors.Cause(err) == errNoPersistentVolumeValue {
+			result.IsWarn = true
+			result.Message = fmt.Sprintf("Unable to evaluate %q: no persistent volumes found", single.When)
+			return result, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to evaluate %q", single.When)
+		}
+		if !isMatch {
+			continue
+		}
+
+		result.IsFail = single == outcome.Fail
+		result.IsWarn = single == outcome.Warn
+		result.IsPass = single == outcome.Pass
+		result.URI = single.URI
+
+		data := persistentVolumesMessageData{
+			Count: len(pvs),
+			Value: conditional.FormatValue(actualValue),
+		}
+		result.Message, err = renderPersistentVolumesMessage(single.Message, single.When, data, !result.IsPass)
+		if err != nil {
+			return nil, err
+		}
+
+		return result, nil
+	}
+
+	return result, nil
+}
+
+// filterPersistentVolumes keeps the volumes of the storage class and in the phase, when they are set
+func filterPersistentVolumes(pvs []corev1.PersistentVolume, storageClassName string, phase string) []corev1.PersistentVolume {
+	filtered := []corev1.PersistentVolume{}
+	for _, pv := range pvs {
+		if storageClassName != "" && pv.Spec.StorageClassName != storageClassName {
+			continue
+		}
+		if phase != "" && !strings.EqualFold(string(pv.Status.Phase), phase) {
+			continue
+		}
+		filtered = append(filtered, pv)
+	}
+	return filtered
+}
+
+func evaluatePersistentVolumesConditional(when string, pvs []corev1.PersistentVolume) (bool, interface{}, error) {
+	return conditional.Evaluate(when, func(expression string) (interface{}, error) {
+		return findPersistentVolumesValue(expression, pvs)
+	})
+}
+
+// findPersistentVolumesValue computes the value of a function(property) expression across the volumes. The result
+// is an int for count() and a *resource.Quantity otherwise.
+func findPersistentVolumesValue(expression string, pvs []corev1.PersistentVolume) (interface{}, error) {
+	match := conditional.ExpressionRegex.FindStringSubmatch(expression)
+	if match == nil {
+		return nil, errors.Errorf("conditional does not match pattern of function(property), got %q", expression)
+	}
+	function := match[1]
+	property := strings.TrimSpace(match[2])
+
+	if function == "count" {
+		if property != "" {
+			return nil, errors.New("count() does not take a property")
+		}
+		return len(pvs), nil
+	}
+	if property != "storage" {
+		return nil, errors.Errorf("unsupported property %q, only storage is supported", property)
+	}
+
+	switch function {
+	case "sum":
+		sum := resource.Quantity{Format: resource.BinarySI}
+		for _, pv := range pvs {
+			sum.Add(persistentVolumeStorage(pv))
+		}
+		return &sum, nil
+	case "min", "max", "avg":
+		if len(pvs) == 0 {
+			return nil, errNoPersistentVolumeValue
+		}
+	default:
+		return nil, errors.Errorf("unsupported function %q", function)
+	}
+
+	found := persistentVolumeStorage(pvs[0])
+	sum := resource.Quantity{Format: resource.BinarySI}
+	for _, pv := range pvs {
+		storage := persistentVolumeStorage(pv)
+		sum.Add(storage)
+		if (function == "min" && storage.Cmp(found) < 0) || (function == "max" && storage.Cmp(found) > 0) {
+			found = storage
+		}
+	}
+	if function == "avg" {
+		return resource.NewMilliQuantity(sum.MilliValue()/int64(len(pvs)), sum.Format), nil
+	}
+
+	return &found, nil
+}
+
+// persistentVolumeStorage is the storage capacity of the volume, zero if it has none
+func persistentVolumeStorage(pv corev1.PersistentVolume) resource.Quantity {
+	storage, ok := pv.Spec.Capacity[corev1.ResourceStorage]
+	if !ok {
+		return resource.Quantity{Format: resource.BinarySI}
+	}
+	return storage.DeepCopy()
+}
+
+// renderPersistentVolumesMessage renders the outcome message as a template with the number of volumes and the
+// computed value. Fail and warn outcomes without a message get a default one, e.g. "sum(storage) is 500Gi, which is
+// < 1Ti".
+func renderPersistentVolumesMessage(message string, when string, data persistentVolumesMessageData, isProblem bool) (string, error) {
+	if message == "" {
+		parts := conditional.SplitActual(strings.TrimSpace(when))
+		if !isProblem || len(parts) == 0 || data.Value == "" {
+			return "", nil
+		}
+		if len(parts) == 2 {
+			parts = append([]string{"count()"}, parts...)
+		}
+		return fmt.Sprintf("%s is %s, which is %s", parts[0], data.Value, strings.Join(parts[1:], " ")), nil
+	}
+
+	if !strings.Contains(message, "{{") {
+		return message, nil
+	}
+
+	tmpl, err := template.New("message").Parse(message)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse message template")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", errors.Wrap(err, "failed to execute message template")
+	}
+
+	return buf.String(), nil
+}
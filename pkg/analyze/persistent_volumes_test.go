@@ -0,0 +1,178 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_analyzePersistentVolumes(t *testing.T) {
+	pv := func(name string, storageClassName string, phase corev1.PersistentVolumePhase, storage string) corev1.PersistentVolume {
+		return corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeSpec{
+				StorageClassName: storageClassName,
+				Capacity: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(storage),
+				},
+			},
+			Status: corev1.PersistentVolumeStatus{
+				Phase: phase,
+			},
+		}
+	}
+	pvs := []corev1.PersistentVolume{
+		pv("pv-1", "fast", corev1.VolumeBound, "500Gi"),
+		pv("pv-2", "fast", corev1.VolumeAvailable, "524Gi"),
+		pv("pv-3", "slow", corev1.VolumeAvailable, "2Ti"),
+	}
+
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.PersistentVolumesAnalyze
+		expected *AnalyzeResult
+	}{
+		{
+			name: "sum of all volumes",
+			analyzer: &troubleshootv1beta2.PersistentVolumesAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "sum(storage) < 1Ti",
+							Message: "Less than 1Ti of persistent volumes",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "{{ .Count }} persistent volumes",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "Persistent Volumes",
+				Message: "3 persistent volumes",
+			},
+		},
+		{
+			name: "storage class",
+			analyzer: &troubleshootv1beta2.PersistentVolumesAnalyze{
+				StorageClassName: "fast",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When: "sum(storage) < 2Ti",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Persistent Volumes",
+				Message: "sum(storage) is 1Ti, which is < 2Ti",
+			},
+		},
+		{
+			name: "available phase",
+			analyzer: &troubleshootv1beta2.PersistentVolumesAnalyze{
+				Phase: "Available",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "min(storage) < 1Ti",
+							Message: "The smallest available volume is {{ .Value }}",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsWarn:  true,
+				Title:   "Persistent Volumes",
+				Message: "The smallest available volume is 524Gi",
+			},
+		},
+		{
+			name: "count with storage class and phase",
+			analyzer: &troubleshootv1beta2.PersistentVolumesAnalyze{
+				StorageClassName: "slow",
+				Phase:            "Bound",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When: "count() == 0",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Persistent Volumes",
+				Message: "count() is 0, which is == 0",
+			},
+		},
+		{
+			name: "no volumes for max",
+			analyzer: &troubleshootv1beta2.PersistentVolumesAnalyze{
+				StorageClassName: "missing",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When: "max(storage) < 1Ti",
+						},
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsWarn:  true,
+				Title:   "Persistent Volumes",
+				Message: `Unable to evaluate "max(storage) < 1Ti": no persistent volumes found`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+			req := require.New(t)
+
+			contents, err := json.Marshal(pvs)
+			req.NoError(err)
+			getFile := func(string) ([]byte, error) {
+				return contents, nil
+			}
+
+			actual, err := analyzePersistentVolumes(test.analyzer, getFile)
+			req.NoError(err)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func Test_findPersistentVolumesValue(t *testing.T) {
+	scopetest := scopeagent.StartTest(t)
+	defer scopetest.End()
+	req := require.New(t)
+
+	_, err := findPersistentVolumesValue("sum(capacity)", nil)
+	req.Error(err)
+
+	_, err = findPersistentVolumesValue("count(storage)", nil)
+	req.Error(err)
+
+	value, err := findPersistentVolumesValue("sum(storage)", nil)
+	req.NoError(err)
+	req.Equal("0", value.(*resource.Quantity).String())
+}
@@ -0,0 +1,249 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+)
+
+// versionConstraintOperators are checked in order, so that longer operators are matched before their prefixes
+var versionConstraintOperators = []string{">=", "<=", "!=", "==", "=", ">", "<", "^", "~"}
+
+// compareVersionConstraint reports whether version satisfies constraint. A constraint is one or more comparisons
+// separated by spaces or commas, which must all match, and alternatives are separated by "||", e.g.
+// ">= 1.24.0 < 1.27.0 || ^2.1". The operators are <, <=, >, >=, = or ==, !=, ^ and ~, and no operator means =.
+// ^1.2.3 allows changes that do not modify the left-most non-zero component, and ~1.2.3 allows patch changes.
+// Versions may have a "v" prefix and may leave out the minor and patch versions, and constraints may use x or * as a
+// wildcard, e.g. 1.16.x.
+func compareVersionConstraint(version string, constraint string) (bool, error) {
+	actual, err := parseTolerantVersion(version)
+	if err != nil {
+		return false, err
+	}
+
+	matches, err := parseVersionConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+
+	return matches(actual), nil
+}
+
+func parseTolerantVersion(version string) (semver.Version, error) {
+	parsed, err := semver.ParseTolerant(strings.TrimSpace(version))
+	if err != nil {
+		return semver.Version{}, errors.Wrapf(err, "failed to parse version %q", version)
+	}
+	return parsed, nil
+}
+
+// parseVersionConstraint parses a constraint as described by compareVersionConstraint
+func parseVersionConstraint(constraint string) (func(semver.Version) bool, error) {
+	if strings.TrimSpace(constraint) == "" {
+		return nil, errors.New("empty version constraint")
+	}
+
+	alternatives := [][]func(semver.Version) bool{}
+	for _, alternative := range strings.Split(constraint, "||") {
+		comparisons, err := parseVersionComparisons(alternative)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse version constraint %q", constraint)
+		}
+		alternatives = append(alternatives, comparisons)
+	}
+
+	return func(v semver.Version) bool {
+		for _, comparisons := range alternatives {
+			matchesAll := true
+			for _, comparison := range comparisons {
+				if !comparison(v) {
+					matchesAll = false
+					break
+				}
+			}
+			if matchesAll {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func parseVersionComparisons(alternative string) ([]func(semver.Version) bool, error) {
+	// an operator may be separated from its version by a space, as in ">= 1.24.0"
+	tokens := []string{}
+	pendingOperator := ""
+	for _, token := range strings.Fields(strings.Replace(alternative, ",", " ", -1)) {
+		if isVersionConstraintOperator(token) {
+			if pendingOperator != "" {
+				return nil, errors.Errorf("operator %q is not followed by a version", pendingOperator)
+			}
+			pendingOperator = token
+			continue
+		}
+		tokens = append(tokens, pendingOperator+token)
+		pendingOperator = ""
+	}
+	if pendingOperator != "" {
+		return nil, errors.Errorf("operator %q is not followed by a version", pendingOperator)
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("empty alternative")
+	}
+
+	comparisons := []func(semver.Version) bool{}
+	for _, token := range tokens {
+		comparison, err := parseVersionComparison(token)
+		if err != nil {
+			return nil, err
+		}
+		comparisons = append(comparisons, comparison)
+	}
+
+	return comparisons, nil
+}
+
+func isVersionConstraintOperator(token string) bool {
+	for _, operator := range versionConstraintOperators {
+		if token == operator {
+			return true
+		}
+	}
+	return false
+}
+
+func parseVersionComparison(comparison string) (func(semver.Version) bool, error) {
+	operator := ""
+	for _, o := range versionConstraintOperators {
+		if strings.HasPrefix(comparison, o) {
+			operator = o
+			break
+		}
+	}
+
+	versionString := strings.TrimPrefix(comparison, operator)
+	if lower, upper, ok := wildcardVersionBounds(versionString); ok {
+		return wildcardVersionComparison(operator, lower, upper)
+	}
+
+	expected, err := parseTolerantVersion(versionString)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operator {
+	case "", "=", "==":
+		return expected.EQ, nil
+	case "!=":
+		return expected.NE, nil
+	case ">":
+		return func(v semver.Version) bool { return v.GT(expected) }, nil
+	case ">=":
+		return func(v semver.Version) bool { return v.GTE(expected) }, nil
+	case "<":
+		return func(v semver.Version) bool { return v.LT(expected) }, nil
+	case "<=":
+		return func(v semver.Version) bool { return v.LTE(expected) }, nil
+	case "^":
+		upper := caretUpperBound(expected, versionComponents(versionString))
+		return func(v semver.Version) bool { return v.GTE(expected) && v.LT(upper) }, nil
+	case "~":
+		upper := semver.Version{Major: expected.Major, Minor: expected.Minor + 1}
+		if versionComponents(versionString) == 1 {
+			upper = semver.Version{Major: expected.Major + 1}
+		}
+		return func(v semver.Version) bool { return v.GTE(expected) && v.LT(upper) }, nil
+	}
+
+	return nil, errors.Errorf("unexpected operator %q", operator)
+}
+
+// wildcardVersionBounds returns the range of versions matched by a version such as 1.16.x or 2.*, which is every
+// version from lower up to but not including upper
+func wildcardVersionBounds(version string) (semver.Version, semver.Version, bool) {
+	components := strings.Split(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".")
+	if len(components) > 3 {
+		return semver.Version{}, semver.Version{}, false
+	}
+
+	specified := []uint64{}
+	for i, component := range components {
+		if component == "x" || component == "X" || component == "*" {
+			// everything after a wildcard must be a wildcard too
+			for _, rest := range components[i:] {
+				if rest != "x" && rest != "X" && rest != "*" {
+					return semver.Version{}, semver.Version{}, false
+				}
+			}
+			break
+		}
+		parsed, err := semver.ParseTolerant(component)
+		if err != nil {
+			return semver.Version{}, semver.Version{}, false
+		}
+		specified = append(specified, parsed.Major)
+	}
+	if len(specified) == len(components) {
+		return semver.Version{}, semver.Version{}, false
+	}
+
+	lower := semver.Version{}
+	upper := semver.Version{}
+	switch len(specified) {
+	case 0:
+		// * matches every version, which no upper bound can express, so the caller treats it separately
+		return lower, upper, true
+	case 1:
+		lower.Major = specified[0]
+		upper.Major = specified[0] + 1
+	case 2:
+		lower.Major, lower.Minor = specified[0], specified[1]
+		upper.Major, upper.Minor = specified[0], specified[1]+1
+	}
+
+	return lower, upper, true
+}
+
+func wildcardVersionComparison(operator string, lower semver.Version, upper semver.Version) (func(semver.Version) bool, error) {
+	isAny := upper.EQ(semver.Version{})
+	inRange := func(v semver.Version) bool { return isAny || (v.GTE(lower) && v.LT(upper)) }
+
+	switch operator {
+	case "", "=", "==", "^", "~":
+		return inRange, nil
+	case "!=":
+		return func(v semver.Version) bool { return !inRange(v) }, nil
+	case ">":
+		return func(v semver.Version) bool { return !isAny && v.GTE(upper) }, nil
+	case ">=":
+		return func(v semver.Version) bool { return v.GTE(lower) }, nil
+	case "<":
+		return func(v semver.Version) bool { return !isAny && v.LT(lower) }, nil
+	case "<=":
+		return func(v semver.Version) bool { return isAny || v.LT(upper) }, nil
+	}
+
+	return nil, errors.Errorf("unexpected operator %q", operator)
+}
+
+// caretUpperBound is the first version that changes the left-most non-zero component. The components that were
+// left out count as non-zero, so ^0 is <1.0.0 and ^0.0 is <0.1.0.
+func caretUpperBound(v semver.Version, components int) semver.Version {
+	switch {
+	case v.Major > 0 || components == 1:
+		return semver.Version{Major: v.Major + 1}
+	case v.Minor > 0 || components == 2:
+		return semver.Version{Minor: v.Minor + 1}
+	}
+	return semver.Version{Patch: v.Patch + 1}
+}
+
+// versionComponents counts the major, minor and patch components that are written out in a version
+func versionComponents(version string) int {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	return strings.Count(version, ".") + 1
+}
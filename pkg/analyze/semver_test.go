@@ -0,0 +1,115 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+)
+
+func Test_compareVersionConstraint(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    string
+		constraint string
+		isMatch    bool
+		isError    bool
+	}{
+		{
+			name:       "greater or equal with a space",
+			version:    "v1.24.3",
+			constraint: ">= 1.24.0",
+			isMatch:    true,
+		},
+		{
+			name:       "range",
+			version:    "1.27.1",
+			constraint: ">=1.24.0, <1.27.0",
+			isMatch:    false,
+		},
+		{
+			name:       "alternatives",
+			version:    "2.1.4",
+			constraint: ">= 1.24.0 < 1.27.0 || ^2.1",
+			isMatch:    true,
+		},
+		{
+			name:       "caret",
+			version:    "1.9.0",
+			constraint: "^1.2",
+			isMatch:    true,
+		},
+		{
+			name:       "caret below 1.0.0",
+			version:    "0.3.0",
+			constraint: "^0.2.3",
+			isMatch:    false,
+		},
+		{
+			name:       "tilde",
+			version:    "1.2.9",
+			constraint: "~1.2.3",
+			isMatch:    true,
+		},
+		{
+			name:       "tilde next minor",
+			version:    "1.3.0",
+			constraint: "~1.2.3",
+			isMatch:    false,
+		},
+		{
+			name:       "not equal",
+			version:    "1.2.3",
+			constraint: "!=1.2.3",
+			isMatch:    false,
+		},
+		{
+			name:       "wildcard",
+			version:    "1.16.8",
+			constraint: "1.16.x",
+			isMatch:    true,
+		},
+		{
+			name:       "greater than wildcard",
+			version:    "1.16.8",
+			constraint: ">1.16.x",
+			isMatch:    false,
+		},
+		{
+			name:       "malformed version",
+			version:    "latest",
+			constraint: ">=1.0.0",
+			isError:    true,
+		},
+		{
+			name:       "dangling operator",
+			version:    "1.0.0",
+			constraint: ">= 1.0.0 <",
+			isError:    true,
+		},
+		{
+			name:       "empty constraint",
+			version:    "1.0.0",
+			constraint: " ",
+			isError:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+			req := require.New(t)
+
+			actual, err := compareVersionConstraint(test.version, test.constraint)
+			if test.isError {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			assert.Equal(t, test.isMatch, actual)
+		})
+	}
+}
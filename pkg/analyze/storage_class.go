@@ -1,9 +1,14 @@
 package analyzer
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"text/template"
 
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/analyze/conditional"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	storagev1beta1 "k8s.io/api/storage/v1beta1"
 )
@@ -34,6 +39,10 @@ func analyzeStorageClass(analyzer *troubleshootv1beta2.StorageClass, getCollecte
 		IconURI: "https://troubleshoot.sh/images/analyzer-icons/storage-class.svg?w=12&h=12",
 	}
 
+	if analyzer.StorageClassName == "" && hasOutcomeConditionals(analyzer.Outcomes) {
+		return analyzeDefaultStorageClassCount(&result, analyzer.Outcomes, storageClasses)
+	}
+
 	for _, storageClass := range storageClasses {
 		val, _ := storageClass.Annotations["storageclass.kubernetes.io/is-default-class"]
 		if (storageClass.Name == analyzer.StorageClassName) || (analyzer.StorageClassName == "" && val == "true") {
@@ -65,3 +74,130 @@ func analyzeStorageClass(analyzer *troubleshootv1beta2.StorageClass, getCollecte
 
 	return &result, nil
 }
+
+type defaultStorageClassesMessageData struct {
+	Count int
+	Names string
+}
+
+func hasOutcomeConditionals(outcomes []*troubleshootv1beta2.Outcome) bool {
+	for _, outcome := range outcomes {
+		for _, single := range []*troubleshootv1beta2.SingleOutcome{outcome.Fail, outcome.Warn, outcome.Pass} {
+			if single != nil && single.When != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isDefaultStorageClass(storageClass storagev1beta1.StorageClass) bool {
+	return storageClass.Annotations["storageclass.kubernetes.io/is-default-class"] == "true" ||
+		storageClass.Annotations["storageclass.beta.kubernetes.io/is-default-class"] == "true"
+}
+
+// analyzeDefaultStorageClassCount evaluates conditionals such as "count() == 1" or "> 1" against the number of
+// default storage classes. Messages can use {{ .Count }} and {{ .Names }}, and a default message names the classes.
+func analyzeDefaultStorageClassCount(result *AnalyzeResult, outcomes []*troubleshootv1beta2.Outcome, storageClasses []storagev1beta1.StorageClass) (*AnalyzeResult, error) {
+	names := []string{}
+	for _, storageClass := range storageClasses {
+		if isDefaultStorageClass(storageClass) {
+			names = append(names, storageClass.Name)
+		}
+	}
+	data := defaultStorageClassesMessageData{
+		Count: len(names),
+		Names: strings.Join(names, ", "),
+	}
+
+	// ordering is important for passthrough
+	for _, outcome := range outcomes {
+		single := outcome.Fail
+		if single == nil {
+			single = outcome.Warn
+		}
+		if single == nil {
+			single = outcome.Pass
+		}
+		if single == nil {
+			continue
+		}
+
+		isMatch, err := compareDefaultStorageClassCount(single.When, len(names))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to evaluate %q", single.When)
+		}
+		if !isMatch {
+			continue
+		}
+
+		result.IsFail = single == outcome.Fail
+		result.IsWarn = single == outcome.Warn
+		result.IsPass = single == outcome.Pass
+		result.URI = single.URI
+
+		result.Message, err = renderDefaultStorageClassesMessage(single.Message, data)
+		if err != nil {
+			return nil, err
+		}
+
+		return result, nil
+	}
+
+	return result, nil
+}
+
+// compareDefaultStorageClassCount uses the same conditional syntax as nodeResources, where count() is the only
+// supported function and may be left out
+func compareDefaultStorageClassCount(when string, count int) (bool, error) {
+	if when == "" {
+		return true, nil
+	}
+
+	parts := conditional.Split(strings.TrimSpace(when))
+	if len(parts) == 2 {
+		parts = append([]string{"count()"}, parts...)
+	}
+	if len(parts) != 3 || parts[0] != "count()" {
+		return false, errors.New("unable to parse storage class conditional, expected count() <operator> <value>")
+	}
+
+	cmp, err := conditional.Compare(count, parts[2])
+	if err != nil {
+		return false, err
+	}
+
+	isMatch, ok := conditional.MatchesOperator(cmp, parts[1])
+	if !ok {
+		return false, errors.Errorf("unexpected operator %q", parts[1])
+	}
+	return isMatch, nil
+}
+
+func renderDefaultStorageClassesMessage(message string, data defaultStorageClassesMessageData) (string, error) {
+	if message == "" {
+		switch data.Count {
+		case 0:
+			return "No Default Storage Class found", nil
+		case 1:
+			return fmt.Sprintf("Default Storage Class %s found", data.Names), nil
+		}
+		return fmt.Sprintf("%d Default Storage Classes found: %s", data.Count, data.Names), nil
+	}
+
+	if !strings.Contains(message, "{{") {
+		return message, nil
+	}
+
+	tmpl, err := template.New("message").Parse(message)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse message template")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", errors.Wrap(err, "failed to execute message template")
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,115 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+	storagev1beta1 "k8s.io/api/storage/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_analyzeStorageClassDefaultCount(t *testing.T) {
+	storageClass := func(name string, isDefault bool) storagev1beta1.StorageClass {
+		s := storagev1beta1.StorageClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+		}
+		if isDefault {
+			s.Annotations = map[string]string{"storageclass.kubernetes.io/is-default-class": "true"}
+		}
+		return s
+	}
+
+	outcomes := []*troubleshootv1beta2.Outcome{
+		{
+			Fail: &troubleshootv1beta2.SingleOutcome{
+				When: "count() == 0",
+			},
+		},
+		{
+			Fail: &troubleshootv1beta2.SingleOutcome{
+				When:    "> 1",
+				Message: "Only one default storage class may be set, found {{ .Names }}",
+			},
+		},
+		{
+			Pass: &troubleshootv1beta2.SingleOutcome{
+				When: "count() == 1",
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		storageClasses []storagev1beta1.StorageClass
+		expected       *AnalyzeResult
+	}{
+		{
+			name:           "no default",
+			storageClasses: []storagev1beta1.StorageClass{storageClass("standard", false)},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Message: "No Default Storage Class found",
+			},
+		},
+		{
+			name:           "multiple defaults",
+			storageClasses: []storagev1beta1.StorageClass{storageClass("standard", true), storageClass("fast", true), storageClass("slow", false)},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Message: "Only one default storage class may be set, found standard, fast",
+			},
+		},
+		{
+			name:           "one default",
+			storageClasses: []storagev1beta1.StorageClass{storageClass("standard", true), storageClass("slow", false)},
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Message: "Default Storage Class standard found",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+			req := require.New(t)
+
+			getCollectedFileContents := func(name string) ([]byte, error) {
+				if name == "cluster-resources/storage-classes.json" {
+					return json.Marshal(test.storageClasses)
+				}
+				return nil, errors.Errorf("file %s was not collected", name)
+			}
+
+			analyzer := &troubleshootv1beta2.StorageClass{
+				Outcomes: outcomes,
+			}
+			actual, err := analyzeStorageClass(analyzer, getCollectedFileContents)
+			req.NoError(err)
+
+			test.expected.Title = "Default Storage Class"
+			test.expected.IconKey = "kubernetes_storage_class"
+			test.expected.IconURI = "https://troubleshoot.sh/images/analyzer-icons/storage-class.svg?w=12&h=12"
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func Test_compareDefaultStorageClassCount(t *testing.T) {
+	scopetest := scopeagent.StartTest(t)
+	defer scopetest.End()
+	req := require.New(t)
+
+	isMatch, err := compareDefaultStorageClassCount("count() != 1", 2)
+	req.NoError(err)
+	req.True(isMatch)
+
+	_, err = compareDefaultStorageClassCount("sum(cpuCapacity) == 1", 1)
+	req.Error(err)
+}
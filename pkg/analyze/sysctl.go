@@ -0,0 +1,244 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/analyze/conditional"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+)
+
+// sysctlRequiredOperators are the operators that describe the required value when a conditional matches, e.g. a
+// fail outcome with "vm.max_map_count < 262144" requires vm.max_map_count >= 262144
+var sysctlRequiredOperators = map[string]string{
+	"<":   ">=",
+	"<=":  ">",
+	">":   "<=",
+	">=":  "<",
+	"=":   "!=",
+	"==":  "!=",
+	"===": "!=",
+	"!=":  "=",
+	"<>":  "=",
+}
+
+type nodeSysctls struct {
+	NodeName string
+	Values   map[string]string
+}
+
+type sysctlMessageData struct {
+	Name     string
+	NodeName string
+	Value    string
+	Required string
+}
+
+// analyzeSysctl matches an outcome when any node satisfies its conditional, e.g. "vm.max_map_count < 262144"
+func analyzeSysctl(analyzer *troubleshootv1beta2.SysctlAnalyze, findFiles getChildCollectedFileContents) (*AnalyzeResult, error) {
+	nodes, err := getNodeSysctls(findFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	title := analyzer.CheckName
+	if title == "" {
+		title = "Kernel Parameters"
+	}
+	result := &AnalyzeResult{
+		Title:   title,
+		IconKey: "kubernetes_sysctl",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+	}
+
+	// ordering is important for passthrough
+	for _, outcome := range analyzer.Outcomes {
+		single := outcome.Fail
+		if single == nil {
+			single = outcome.Warn
+		}
+		if single == nil {
+			single = outcome.Pass
+		}
+		if single == nil {
+			continue
+		}
+
+		data, err := findNodeMatchingSysctlConditional(single.When, nodes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to compare sysctl conditional")
+		}
+		if data == nil {
+			continue
+		}
+
+		result.IsFail = single == outcome.Fail
+		result.IsWarn = single == outcome.Warn
+		result.IsPass = single == outcome.Pass
+
+		message, err := renderSysctlMessage(single.Message, *data, !result.IsPass)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render message")
+		}
+		result.Message = message
+		result.URI = single.URI
+
+		return result, nil
+	}
+
+	return result, nil
+}
+
+// getNodeSysctls reads the sysctl/<node>.json files written by the sysctl collector, sorted by node name
+func getNodeSysctls(findFiles getChildCollectedFileContents) ([]nodeSysctls, error) {
+	files, err := findFiles("sysctl/*.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find sysctl files")
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no sysctl values were collected")
+	}
+
+	nodes := []nodeSysctls{}
+	for fileName, contents := range files {
+		values := map[string]string{}
+		if err := json.Unmarshal(contents, &values); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", fileName)
+		}
+		nodes = append(nodes, nodeSysctls{
+			NodeName: strings.TrimSuffix(filepath.Base(fileName), ".json"),
+			Values:   values,
+		})
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].NodeName < nodes[j].NodeName
+	})
+
+	return nodes, nil
+}
+
+// findNodeMatchingSysctlConditional returns the first node that matches the conditional, or nil if none do. Nodes
+// that did not report the parameter are skipped, but it is an error if no node reported it.
+func findNodeMatchingSysctlConditional(conditional string, nodes []nodeSysctls) (*sysctlMessageData, error) {
+	if conditional == "" {
+		return &sysctlMessageData{}, nil
+	}
+
+	name, operator, expected, err := parseSysctlConditional(conditional)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for _, node := range nodes {
+		actual, ok := node.Values[name]
+		if !ok {
+			continue
+		}
+		found = true
+
+		isMatch, err := compareSysctlValue(actual, operator, expected)
+		if err != nil {
+			return nil, err
+		}
+		if isMatch {
+			return &sysctlMessageData{
+				Name:     name,
+				NodeName: node.NodeName,
+				Value:    actual,
+				Required: requiredSysctlValue(operator, expected),
+			}, nil
+		}
+	}
+
+	if !found {
+		return nil, errors.Errorf("sysctl %s was not collected from any node", name)
+	}
+
+	return nil, nil
+}
+
+// parseSysctlConditional splits a conditional such as "net.ipv4.ip_local_port_range = 32768 60999" into the
+// parameter name, operator and expected value
+func parseSysctlConditional(conditional string) (string, string, string, error) {
+	parts := strings.SplitN(strings.TrimSpace(conditional), " ", 3)
+	if len(parts) != 3 {
+		return "", "", "", errors.New("unable to parse sysctl conditional")
+	}
+	if _, ok := sysctlRequiredOperators[parts[1]]; !ok {
+		return "", "", "", errors.Errorf("unexpected operator %q in sysctl conditional", parts[1])
+	}
+
+	return parts[0], parts[1], strings.TrimSpace(parts[2]), nil
+}
+
+// compareSysctlValue compares integer values numerically. Other values, such as "32768 60999", can only be
+// compared for equality.
+func compareSysctlValue(actual string, operator string, expected string) (bool, error) {
+	if actualInt, err := strconv.Atoi(actual); err == nil {
+		cmp, err := conditional.Compare(actualInt, expected)
+		if err == nil {
+			isMatch, ok := conditional.MatchesOperator(cmp, operator)
+			if !ok {
+				return false, errors.Errorf("unexpected operator %q in sysctl conditional", operator)
+			}
+			return isMatch, nil
+		}
+	}
+
+	expected = strings.Join(strings.Fields(expected), " ")
+	switch operator {
+	case "=", "==", "===":
+		return actual == expected, nil
+	case "!=", "<>":
+		return actual != expected, nil
+	}
+
+	return false, errors.Errorf("operator %q requires integer values, got %q and %q", operator, actual, expected)
+}
+
+func requiredSysctlValue(operator string, expected string) string {
+	required, ok := sysctlRequiredOperators[operator]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s %s", required, expected)
+}
+
+// renderSysctlMessage renders the outcome message as a template with the matching node and value. Fail and warn
+// outcomes without a message get a default one, e.g. "vm.max_map_count is 65530 on node1, required >= 262144".
+func renderSysctlMessage(message string, data sysctlMessageData, isProblem bool) (string, error) {
+	if message == "" {
+		if !isProblem || data.Name == "" {
+			return "", nil
+		}
+		message = fmt.Sprintf("%s is %s on %s", data.Name, data.Value, data.NodeName)
+		if data.Required != "" {
+			message = fmt.Sprintf("%s, required %s", message, data.Required)
+		}
+		return message, nil
+	}
+
+	if !strings.Contains(message, "{{") {
+		return message, nil
+	}
+
+	tmpl, err := template.New("message").Parse(message)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse message template")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", errors.Wrap(err, "failed to execute message template")
+	}
+
+	return buf.String(), nil
+}
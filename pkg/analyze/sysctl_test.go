@@ -0,0 +1,175 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+)
+
+func Test_compareSysctlValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		actual   string
+		operator string
+		expected string
+		isMatch  bool
+		isError  bool
+	}{
+		{
+			name:     "below minimum",
+			actual:   "65530",
+			operator: "<",
+			expected: "262144",
+			isMatch:  true,
+		},
+		{
+			name:     "at minimum",
+			actual:   "262144",
+			operator: "<",
+			expected: "262144",
+			isMatch:  false,
+		},
+		{
+			name:     "greater or equal",
+			actual:   "4096",
+			operator: ">=",
+			expected: "1024",
+			isMatch:  true,
+		},
+		{
+			name:     "multiple fields",
+			actual:   "32768 60999",
+			operator: "==",
+			expected: "32768   60999",
+			isMatch:  true,
+		},
+		{
+			name:     "string not equal",
+			actual:   "cubic",
+			operator: "!=",
+			expected: "bbr",
+			isMatch:  true,
+		},
+		{
+			name:     "ordering non-integers",
+			actual:   "cubic",
+			operator: "<",
+			expected: "bbr",
+			isError:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+			req := require.New(t)
+
+			actual, err := compareSysctlValue(test.actual, test.operator, test.expected)
+			if test.isError {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			assert.Equal(t, test.isMatch, actual)
+		})
+	}
+}
+
+func Test_analyzeSysctl(t *testing.T) {
+	findFiles := func(prefix string) (map[string][]byte, error) {
+		return map[string][]byte{
+			"sysctl/node1.json": []byte(`{"vm.max_map_count": "262144", "net.core.somaxconn": "4096"}`),
+			"sysctl/node2.json": []byte(`{"vm.max_map_count": "65530", "net.core.somaxconn": "4096"}`),
+		}, nil
+	}
+
+	tests := []struct {
+		name     string
+		outcomes []*troubleshootv1beta2.Outcome
+		expected *AnalyzeResult
+		isError  bool
+	}{
+		{
+			name: "default fail message",
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{
+					Fail: &troubleshootv1beta2.SingleOutcome{
+						When: "vm.max_map_count < 262144",
+					},
+				},
+				{
+					Pass: &troubleshootv1beta2.SingleOutcome{
+						Message: "vm.max_map_count is large enough",
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "Kernel Parameters",
+				Message: "vm.max_map_count is 65530 on node2, required >= 262144",
+				IconKey: "kubernetes_sysctl",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name: "templated warn message",
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{
+					Warn: &troubleshootv1beta2.SingleOutcome{
+						When:    "net.core.somaxconn < 1024",
+						Message: "{{ .Name }} is {{ .Value }} on {{ .NodeName }}",
+					},
+				},
+				{
+					Pass: &troubleshootv1beta2.SingleOutcome{
+						Message: "net.core.somaxconn is large enough",
+					},
+				},
+			},
+			expected: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "Kernel Parameters",
+				Message: "net.core.somaxconn is large enough",
+				IconKey: "kubernetes_sysctl",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
+		{
+			name: "parameter not collected",
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{
+					Fail: &troubleshootv1beta2.SingleOutcome{
+						When: "fs.inotify.max_user_watches < 524288",
+					},
+				},
+			},
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+			req := require.New(t)
+
+			analyzer := &troubleshootv1beta2.SysctlAnalyze{
+				Outcomes: test.outcomes,
+			}
+
+			actual, err := analyzeSysctl(analyzer, findFiles)
+			if test.isError {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -1,17 +1,21 @@
 package analyzer
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"text/template"
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 )
 
-func analyzeTextAnalyze(analyzer *troubleshootv1beta2.TextAnalyze, getCollectedFileContents func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+// analyzeTextAnalyze matches the regex against each collected file, stopping once ctx is done
+func analyzeTextAnalyze(ctx context.Context, analyzer *troubleshootv1beta2.TextAnalyze, getCollectedFileContents func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
 	fullPath := filepath.Join(analyzer.CollectorName, analyzer.FileName)
 	collected, err := getCollectedFileContents(fullPath)
 	if err != nil {
@@ -39,6 +43,10 @@ func analyzeTextAnalyze(analyzer *troubleshootv1beta2.TextAnalyze, getCollectedF
 
 	if analyzer.RegexPattern != "" {
 		for _, fileContents := range collected {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
 			result, err := analyzeRegexPattern(analyzer.RegexPattern, fileContents, analyzer.Outcomes, checkName)
 			if err != nil {
 				return nil, err
@@ -51,6 +59,10 @@ func analyzeTextAnalyze(analyzer *troubleshootv1beta2.TextAnalyze, getCollectedF
 
 	if analyzer.RegexGroups != "" {
 		for _, fileContents := range collected {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
 			result, err := analyzeRegexGroups(analyzer.RegexGroups, fileContents, analyzer.Outcomes, checkName)
 			if err != nil {
 				return nil, err
@@ -134,6 +146,20 @@ func analyzeRegexGroups(pattern string, collected []byte, outcomes []*troublesho
 		}
 	}
 
+	result, err = evaluateRegexGroupsOutcomes(result, outcomes, foundMatches)
+	if err != nil {
+		return result, err
+	}
+
+	result.Message, err = renderRegexGroupsMessage(result.Message, foundMatches)
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func evaluateRegexGroupsOutcomes(result *AnalyzeResult, outcomes []*troubleshootv1beta2.Outcome, foundMatches map[string]string) (*AnalyzeResult, error) {
 	// allow fallthrough
 	for _, outcome := range outcomes {
 		if outcome.Fail != nil {
@@ -205,6 +231,26 @@ func analyzeRegexGroups(pattern string, collected []byte, outcomes []*troublesho
 	return result, nil
 }
 
+// renderRegexGroupsMessage renders the message as a template with the captured groups by name, e.g. {{ .Version }}.
+// Groups that did not match render as "<no value>".
+func renderRegexGroupsMessage(message string, foundMatches map[string]string) (string, error) {
+	if !strings.Contains(message, "{{") {
+		return message, nil
+	}
+
+	tmpl, err := template.New("message").Parse(message)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse message template")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, foundMatches); err != nil {
+		return "", errors.Wrap(err, "failed to execute message template")
+	}
+
+	return buf.String(), nil
+}
+
 func compareRegex(conditional string, foundMatches map[string]string) (bool, error) {
 	parts := strings.Split(strings.TrimSpace(conditional), " ")
 
@@ -250,15 +296,38 @@ func compareRegex(conditional string, foundMatches map[string]string) (bool, err
 
 		case ">=":
 			return foundValueInt >= lookForValueInt, nil
+
+		case "!=":
+			return foundValueInt != lookForValueInt, nil
 		}
 	} else {
-		// all we can support is "=" and "==" and "===" for now
-		if operator != "=" && operator != "==" && operator != "===" {
-			return false, fmt.Errorf("unexpected operator %q in regex comparator, cannot compare %q and %q", operator, foundValue, lookForValue)
+		switch operator {
+		case "=", "==", "===":
+			return foundValue == lookForValue, nil
+		case "!=":
+			return foundValue != lookForValue, nil
+		case "<", ">", "<=", ">=":
+			// versions such as "v1.19.3" or "2.4" are compared as semver
+			return compareRegexVersions(foundValue, operator, lookForValue)
 		}
 
-		return foundValue == lookForValue, nil
+		return false, fmt.Errorf("unexpected operator %q in regex comparator, cannot compare %q and %q", operator, foundValue, lookForValue)
 	}
 
 	return false, nil
 }
+
+func compareRegexVersions(foundValue string, operator string, lookForValue string) (bool, error) {
+	matches, err := parseVersionConstraint(operator + lookForValue)
+	if err != nil {
+		return false, errors.Wrapf(err, "cannot compare %q and %q", foundValue, lookForValue)
+	}
+
+	found, err := parseTolerantVersion(foundValue)
+	if err != nil {
+		// not an error, the captured value just isn't a version
+		return false, nil
+	}
+
+	return matches(found), nil
+}
@@ -1,6 +1,7 @@
 package analyzer
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -304,6 +305,39 @@ func Test_textAnalyze(t *testing.T) {
 				"text-collector-2/cfile-3.txt": []byte("Yes it all succeeded"),
 			},
 		},
+		{
+			name: "regex groups version comparison with a message template",
+			analyzer: troubleshootv1beta2.TextAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "Version < 2.4.0",
+							Message: "Version {{ .Version }} is too old, 2.4.0 or later is required",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "Version {{ .Version }} is supported",
+						},
+					},
+				},
+				CollectorName: "app",
+				FileName:      "app.log",
+				RegexGroups:   `starting server version=v(?P<Version>[0-9.]+)`,
+			},
+			expectResult: []AnalyzeResult{
+				{
+					IsFail:  true,
+					Title:   "app",
+					Message: "Version 2.3.1 is too old, 2.4.0 or later is required",
+					IconKey: "kubernetes_text_analyze",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg?w=13&h=16",
+				},
+			},
+			files: map[string][]byte{
+				"app/app.log": []byte("level=info msg=\"starting server version=v2.3.1\"\n"),
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -332,7 +366,7 @@ func Test_textAnalyze(t *testing.T) {
 				return matching, nil
 			}
 
-			actual, err := analyzeTextAnalyze(&test.analyzer, getFiles)
+			actual, err := analyzeTextAnalyze(context.Background(), &test.analyzer, getFiles)
 			req.NoError(err)
 
 			unPointered := []AnalyzeResult{}
@@ -379,6 +413,30 @@ func Test_compareRegex(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			name:        "Version >= 1.10.0",
+			conditional: "Version >= 1.10.0",
+			foundMatches: map[string]string{
+				"Version": "v1.9.2",
+			},
+			expected: false,
+		},
+		{
+			name:        "Version < 1.10",
+			conditional: "Version < 1.10",
+			foundMatches: map[string]string{
+				"Version": "1.9.2",
+			},
+			expected: true,
+		},
+		{
+			name:        "Hostname != icecream",
+			conditional: "Hostname != icecream",
+			foundMatches: map[string]string{
+				"Hostname": "icecream",
+			},
+			expected: false,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -0,0 +1,252 @@
+package analyzer
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/analyze/conditional"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ValidateAnalyzer checks an analyzer spec without any collected data and returns the problems found, such as
+// conditionals that cannot be parsed
+func ValidateAnalyzer(analyzer *troubleshootv1beta2.Analyze) []error {
+	problems := []error{}
+
+	if analyzer.NodeResources != nil {
+		problems = append(problems, validateNodeResourcesOutcomes("outcomes", analyzer.NodeResources.Outcomes)...)
+		problems = append(problems, validateNodeResourcesOutcomes("onInstall", analyzer.NodeResources.OnInstall)...)
+		problems = append(problems, validateNodeResourcesOutcomes("onUpdate", analyzer.NodeResources.OnUpdate)...)
+		if _, err := conditional.ParseTolerance(analyzer.NodeResources.Tolerance); err != nil {
+			problems = append(problems, err)
+		}
+		if analyzer.NodeResources.Filters != nil {
+			if readyFor := analyzer.NodeResources.Filters.ReadyFor; readyFor != "" {
+				if _, err := time.ParseDuration(readyFor); err != nil {
+					problems = append(problems, errors.Wrapf(err, "filters.readyFor %q", readyFor))
+				}
+			}
+			groupNames := map[string]bool{}
+			for i, group := range analyzer.NodeResources.Filters.Groups {
+				if group.Name == "" {
+					problems = append(problems, errors.Errorf("filters.groups[%d] has no name", i))
+				} else if groupNames[group.Name] {
+					problems = append(problems, errors.Errorf("filters.groups[%d] name %q is used by another group", i, group.Name))
+				}
+				groupNames[group.Name] = true
+			}
+		}
+		return problems
+	}
+	if analyzer.NodeOS != nil {
+		for i, outcome := range analyzer.NodeOS.Outcomes {
+			for _, single := range []*troubleshootv1beta2.SingleOutcome{outcome.Fail, outcome.Warn, outcome.Pass} {
+				if single == nil || single.When == "" {
+					continue
+				}
+				if _, err := compareNodeOSConditionalToActual(single.When, corev1.NodeSystemInfo{}); err != nil {
+					problems = append(problems, errors.Wrapf(err, "outcomes[%d] when %q", i, single.When))
+				}
+			}
+		}
+		return problems
+	}
+	if analyzer.StorageClass != nil && analyzer.StorageClass.StorageClassName == "" {
+		for i, outcome := range analyzer.StorageClass.Outcomes {
+			for _, single := range []*troubleshootv1beta2.SingleOutcome{outcome.Fail, outcome.Warn, outcome.Pass} {
+				if single == nil || single.When == "" {
+					continue
+				}
+				if _, err := compareDefaultStorageClassCount(single.When, 0); err != nil {
+					problems = append(problems, errors.Wrapf(err, "outcomes[%d] when %q", i, single.When))
+				}
+			}
+		}
+		return problems
+	}
+	if analyzer.Sysctl != nil {
+		for i, outcome := range analyzer.Sysctl.Outcomes {
+			for _, single := range []*troubleshootv1beta2.SingleOutcome{outcome.Fail, outcome.Warn, outcome.Pass} {
+				if single == nil || single.When == "" {
+					continue
+				}
+				if _, _, _, err := parseSysctlConditional(single.When); err != nil {
+					problems = append(problems, errors.Wrapf(err, "outcomes[%d] when %q", i, single.When))
+				}
+			}
+		}
+		return problems
+	}
+	if analyzer.Events != nil {
+		if analyzer.Events.Lookback != "" {
+			if _, err := time.ParseDuration(analyzer.Events.Lookback); err != nil {
+				problems = append(problems, errors.Wrapf(err, "lookback %q", analyzer.Events.Lookback))
+			}
+		}
+		for i, outcome := range analyzer.Events.Outcomes {
+			for _, single := range []*troubleshootv1beta2.SingleOutcome{outcome.Fail, outcome.Warn, outcome.Pass} {
+				if single == nil || single.When == "" {
+					continue
+				}
+				if _, _, err := evaluateEventsConditional(single.When, []corev1.Event{}); err != nil {
+					problems = append(problems, errors.Wrapf(err, "outcomes[%d] when %q", i, single.When))
+				}
+			}
+		}
+		return problems
+	}
+	if analyzer.PersistentVolumes != nil {
+		for i, outcome := range analyzer.PersistentVolumes.Outcomes {
+			for _, single := range []*troubleshootv1beta2.SingleOutcome{outcome.Fail, outcome.Warn, outcome.Pass} {
+				if single == nil || single.When == "" {
+					continue
+				}
+				_, _, err := evaluatePersistentVolumesConditional(single.When, []corev1.PersistentVolume{})
+				if err != nil && errors.Cause(err) != errNoPersistentVolumeValue {
+					problems = append(problems, errors.Wrapf(err, "outcomes[%d] when %q", i, single.When))
+				}
+			}
+		}
+		return problems
+	}
+	if analyzer.CompareField != nil {
+		if _, err := findCompareFieldValues([]byte("{}"), analyzer.CompareField.Path); err != nil {
+			problems = append(problems, err)
+		}
+		for i, outcome := range analyzer.CompareField.Outcomes {
+			for _, single := range []*troubleshootv1beta2.SingleOutcome{outcome.Fail, outcome.Warn, outcome.Pass} {
+				if single == nil || single.When == "" {
+					continue
+				}
+				_, _, err := evaluateCompareFieldConditional(single.When, []*resource.Quantity{})
+				if err != nil && errors.Cause(err) != errNoCompareFieldValue {
+					problems = append(problems, errors.Wrapf(err, "outcomes[%d] when %q", i, single.When))
+				}
+			}
+		}
+		return problems
+	}
+	if analyzer.KubeletMaxPods != nil {
+		if _, err := conditional.ParseTolerance(analyzer.KubeletMaxPods.Tolerance); err != nil {
+			problems = append(problems, err)
+		}
+		return problems
+	}
+	if analyzer.Certificates != nil {
+		if _, err := time.ParseDuration(analyzer.Certificates.Threshold); err != nil {
+			problems = append(problems, errors.Wrapf(err, "threshold %q", analyzer.Certificates.Threshold))
+		}
+		return problems
+	}
+	if analyzer.TextAnalyze != nil {
+		if analyzer.TextAnalyze.RegexPattern != "" {
+			if _, err := regexp.Compile(analyzer.TextAnalyze.RegexPattern); err != nil {
+				problems = append(problems, errors.Wrap(err, "failed to compile regex"))
+			}
+		}
+		if analyzer.TextAnalyze.RegexGroups != "" {
+			if _, err := regexp.Compile(analyzer.TextAnalyze.RegexGroups); err != nil {
+				problems = append(problems, errors.Wrap(err, "failed to compile regexGroups"))
+			}
+		}
+		return problems
+	}
+
+	if !isKnownAnalyzer(analyzer) {
+		problems = append(problems, errors.New("invalid analyzer"))
+	}
+	return problems
+}
+
+// validateNodeResourcesOutcomes evaluates each when clause against an empty node list. Conditionals that only fail
+// because no node reports a value are fine, anything else would fail the same way against a real cluster.
+func validateNodeResourcesOutcomes(field string, outcomes []*troubleshootv1beta2.Outcome) []error {
+	problems := []error{}
+	for i, outcome := range outcomes {
+		for _, single := range []struct {
+			name    string
+			outcome *troubleshootv1beta2.SingleOutcome
+		}{
+			{name: "fail", outcome: outcome.Fail},
+			{name: "warn", outcome: outcome.Warn},
+			{name: "pass", outcome: outcome.Pass},
+		} {
+			if single.outcome == nil {
+				continue
+			}
+
+			conditional, isNegated, err := nodeResourcesOutcomeConditional(single.outcome)
+			if err != nil {
+				problems = append(problems, errors.Wrapf(err, "%s[%d].%s", field, i, single.name))
+				continue
+			}
+			conditionalField := "when"
+			if isNegated {
+				conditionalField = "whenNot"
+			}
+
+			if err := validateNodeResourceConditionalProperties(conditional); err != nil {
+				problems = append(problems, errors.Wrapf(err, "%s[%d].%s.%s %q", field, i, single.name, conditionalField, conditional))
+				continue
+			}
+
+			// each combined conditional is checked on its own, as one without a value would stop the others from
+			// being evaluated
+			if err := validateNodeResourceConditional(conditional); err != nil {
+				problems = append(problems, errors.Wrapf(err, "%s[%d].%s.%s %q", field, i, single.name, conditionalField, conditional))
+			}
+		}
+	}
+	return problems
+}
+
+// validateNodeResourceConditional evaluates the conditional, and each of those it combines with || or &&, against an
+// empty node list
+func validateNodeResourceConditional(when string) error {
+	groups, err := conditional.SplitLogical(when)
+	if err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		for _, single := range group {
+			_, err := compareNodeResourceConditionalToActual(single, []corev1.Node{}, 0, nil)
+			if err != nil && errors.Cause(err) != errNoNodeResourceValue {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func isKnownAnalyzer(analyzer *troubleshootv1beta2.Analyze) bool {
+	return analyzer.ClusterVersion != nil ||
+		analyzer.StorageClass != nil ||
+		analyzer.CustomResourceDefinition != nil ||
+		analyzer.Ingress != nil ||
+		analyzer.Secret != nil ||
+		analyzer.ImagePullSecret != nil ||
+		analyzer.DeploymentStatus != nil ||
+		analyzer.StatefulsetStatus != nil ||
+		analyzer.ContainerRuntime != nil ||
+		analyzer.Distribution != nil ||
+		analyzer.NodeResources != nil ||
+		analyzer.NodeOS != nil ||
+		analyzer.TextAnalyze != nil ||
+		analyzer.Postgres != nil ||
+		analyzer.Mysql != nil ||
+		analyzer.Redis != nil ||
+		analyzer.CephStatus != nil ||
+		analyzer.Sysctl != nil ||
+		analyzer.Events != nil ||
+		analyzer.CompareField != nil ||
+		analyzer.KubeletMaxPods != nil ||
+		analyzer.Certificates != nil ||
+		analyzer.APIServerHealth != nil ||
+		analyzer.PersistentVolumes != nil ||
+		analyzer.IngressHosts != nil ||
+		analyzer.NodeCgroups != nil
+}
@@ -0,0 +1,123 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+)
+
+func TestValidateAnalyzer(t *testing.T) {
+	nodeResources := func(when string) *troubleshootv1beta2.Analyze {
+		return &troubleshootv1beta2.Analyze{
+			NodeResources: &troubleshootv1beta2.NodeResources{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When: when,
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.Analyze
+		expected []string
+	}{
+		{
+			name:     "valid count",
+			analyzer: nodeResources("count() < 3"),
+			expected: []string{},
+		},
+		{
+			name:     "quantity without matching nodes",
+			analyzer: nodeResources("min(memoryCapacity) < 8Gi"),
+			expected: []string{},
+		},
+		{
+			name:     "misspelled function",
+			analyzer: nodeResources("coutn() >= 3"),
+			expected: []string{`outcomes[0].fail.when "coutn() >= 3": unsupported function "coutn"`},
+		},
+		{
+			name:     "misspelled function in a combined conditional",
+			analyzer: nodeResources("min(memoryCapacity) < 8Gi || coutn() >= 3"),
+			expected: []string{`outcomes[0].fail.when "min(memoryCapacity) < 8Gi || coutn() >= 3": unsupported function "coutn"`},
+		},
+		{
+			name:     "missing upper bound",
+			analyzer: nodeResources("count() between 3"),
+			expected: []string{`outcomes[0].fail.when "count() between 3": between requires a lower and an upper bound, e.g. count() between 3 5`},
+		},
+		{
+			name:     "property without a function",
+			analyzer: nodeResources("min(cpuAllocatable) >= cpuCapacity * 0.7"),
+			expected: []string{`outcomes[0].fail.when "min(cpuAllocatable) >= cpuCapacity * 0.7": property references must use a function, e.g. sum(cpuCapacity), got cpuCapacity`},
+		},
+		{
+			name: "when and whenNot",
+			analyzer: &troubleshootv1beta2.Analyze{
+				NodeResources: &troubleshootv1beta2.NodeResources{
+					Outcomes: []*troubleshootv1beta2.Outcome{
+						{
+							Warn: &troubleshootv1beta2.SingleOutcome{
+								When:    "count() < 3",
+								WhenNot: "count() >= 3",
+							},
+						},
+					},
+				},
+			},
+			expected: []string{`outcomes[0].warn: when "count() < 3" and whenNot "count() >= 3" cannot both be set`},
+		},
+		{
+			name: "unsupported event selector",
+			analyzer: &troubleshootv1beta2.Analyze{
+				Events: &troubleshootv1beta2.EventsAnalyze{
+					Outcomes: []*troubleshootv1beta2.Outcome{
+						{
+							Fail: &troubleshootv1beta2.SingleOutcome{
+								When: "count(severity=high) > 0",
+							},
+						},
+					},
+				},
+			},
+			expected: []string{`outcomes[0] when "count(severity=high) > 0": unsupported event selector field "severity"`},
+		},
+		{
+			name: "invalid regex",
+			analyzer: &troubleshootv1beta2.Analyze{
+				TextAnalyze: &troubleshootv1beta2.TextAnalyze{
+					RegexPattern: "(",
+				},
+			},
+			expected: []string{"failed to compile regex: error parsing regexp: missing closing ): `(`"},
+		},
+		{
+			name:     "no analyzer",
+			analyzer: &troubleshootv1beta2.Analyze{},
+			expected: []string{"invalid analyzer"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+			req := require.New(t)
+
+			actual := []string{}
+			for _, err := range ValidateAnalyzer(test.analyzer) {
+				actual = append(actual, err.Error())
+			}
+			req.Equal(test.expected, actual)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 type SingleOutcome struct {
 	When    string `json:"when,omitempty" yaml:"when,omitempty"`
+	WhenNot string `json:"whenNot,omitempty" yaml:"whenNot,omitempty"`
 	Message string `json:"message,omitempty" yaml:"message,omitempty"`
 	URI     string `json:"uri,omitempty" yaml:"uri,omitempty"`
 }
@@ -77,10 +78,30 @@ type Distribution struct {
 	Outcomes    []*Outcome `json:"outcomes" yaml:"outcomes"`
 }
 
+type NodeOS struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Outcomes    []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
 type NodeResources struct {
 	AnalyzeMeta `json:",inline" yaml:",inline"`
-	Outcomes    []*Outcome           `json:"outcomes" yaml:"outcomes"`
-	Filters     *NodeResourceFilters `json:"filters,omitempty" yaml:"filters,omitempty"`
+	Outcomes    []*Outcome             `json:"outcomes" yaml:"outcomes"`
+	Filters     *NodeResourceFilters   `json:"filters,omitempty" yaml:"filters,omitempty"`
+	Deployment  *NodeResourcesWorkload `json:"deployment,omitempty" yaml:"deployment,omitempty"`
+	StatefulSet *NodeResourcesWorkload `json:"statefulSet,omitempty" yaml:"statefulSet,omitempty"`
+	DaemonSet   *NodeResourcesWorkload `json:"daemonSet,omitempty" yaml:"daemonSet,omitempty"`
+	OnInstall   []*Outcome             `json:"onInstall,omitempty" yaml:"onInstall,omitempty"`
+	OnUpdate    []*Outcome             `json:"onUpdate,omitempty" yaml:"onUpdate,omitempty"`
+	Tolerance   string                 `json:"tolerance,omitempty" yaml:"tolerance,omitempty"`
+	PerNode     bool                   `json:"perNode,omitempty" yaml:"perNode,omitempty"`
+	Strict      bool                   `json:"strict,omitempty" yaml:"strict,omitempty"`
+	MostSevere  bool                   `json:"mostSevere,omitempty" yaml:"mostSevere,omitempty"`
+}
+
+type NodeResourcesWorkload struct {
+	Namespace string            `json:"namespace" yaml:"namespace"`
+	Name      string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Selector  map[string]string `json:"selector,omitempty" yaml:"selector,omitempty"`
 }
 
 type NodeResourceFilters struct {
@@ -93,10 +114,41 @@ type NodeResourceFilters struct {
 	EphemeralStorageCapacity    string                 `json:"ephemeralStorageCapacity,omitempty" yaml:"ephemeralStorageCapacity,omitempty"`
 	EphemeralStorageAllocatable string                 `json:"ephemeralStorageAllocatable,omitempty" yaml:"ephemeralStorageAllocatable,omitempty"`
 	Selector                    *NodeResourceSelectors `json:"selector,omitempty" yaml:"selector,omitempty"`
+	Ready                       bool                   `json:"ready,omitempty" yaml:"ready,omitempty"`
+	ReadyFor                    string                 `json:"readyFor,omitempty" yaml:"readyFor,omitempty"`
+	Schedulable                 bool                   `json:"schedulable,omitempty" yaml:"schedulable,omitempty"`
+	ExcludeTaints               []NodeResourceTaint    `json:"excludeTaints,omitempty" yaml:"excludeTaints,omitempty"`
+	ExcludeConditions           []string               `json:"excludeConditions,omitempty" yaml:"excludeConditions,omitempty"`
+	MinAge                      string                 `json:"minAge,omitempty" yaml:"minAge,omitempty"`
+	MaxAge                      string                 `json:"maxAge,omitempty" yaml:"maxAge,omitempty"`
+	KubeletVersion              string                 `json:"kubeletVersion,omitempty" yaml:"kubeletVersion,omitempty"`
+	OS                          string                 `json:"os,omitempty" yaml:"os,omitempty"`
+	Arch                        string                 `json:"arch,omitempty" yaml:"arch,omitempty"`
+	Groups                      []NodeResourceGroup    `json:"groups,omitempty" yaml:"groups,omitempty"`
+}
+
+type NodeResourceGroup struct {
+	Name     string                 `json:"name" yaml:"name"`
+	Selector *NodeResourceSelectors `json:"selector,omitempty" yaml:"selector,omitempty"`
+}
+
+type NodeResourceTaint struct {
+	Key    string `json:"key" yaml:"key"`
+	Value  string `json:"value,omitempty" yaml:"value,omitempty"`
+	Effect string `json:"effect,omitempty" yaml:"effect,omitempty"`
 }
 
 type NodeResourceSelectors struct {
-	MatchLabel map[string]string `json:"matchLabel,omitempty" yaml:"matchLabel,omitempty"`
+	MatchLabel       map[string]string                 `json:"matchLabel,omitempty" yaml:"matchLabel,omitempty"`
+	MatchAnnotation  map[string]string                 `json:"matchAnnotation,omitempty" yaml:"matchAnnotation,omitempty"`
+	MatchExpressions []NodeResourceSelectorRequirement `json:"matchExpressions,omitempty" yaml:"matchExpressions,omitempty"`
+}
+
+// NodeResourceSelectorRequirement mirrors the Kubernetes LabelSelectorRequirement
+type NodeResourceSelectorRequirement struct {
+	Key      string   `json:"key" yaml:"key"`
+	Operator string   `json:"operator" yaml:"operator"`
+	Values   []string `json:"values,omitempty" yaml:"values,omitempty"`
 }
 
 type TextAnalyze struct {
@@ -128,6 +180,60 @@ type CephStatusAnalyze struct {
 	Namespace     string     `json:"namespace" yaml:"namespace"`
 }
 
+type SysctlAnalyze struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Outcomes    []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+type EventsAnalyze struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Outcomes    []*Outcome `json:"outcomes" yaml:"outcomes"`
+	Lookback    string     `json:"lookback,omitempty" yaml:"lookback,omitempty"`
+}
+
+type CompareField struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	FileName      string     `json:"fileName" yaml:"fileName"`
+	Path          string     `json:"path" yaml:"path"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+type APIServerHealthAnalyze struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Outcomes    []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+type PersistentVolumesAnalyze struct {
+	AnalyzeMeta      `json:",inline" yaml:",inline"`
+	Outcomes         []*Outcome `json:"outcomes" yaml:"outcomes"`
+	StorageClassName string     `json:"storageClassName,omitempty" yaml:"storageClassName,omitempty"`
+	Phase            string     `json:"phase,omitempty" yaml:"phase,omitempty"`
+}
+
+type IngressHostsAnalyze struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Outcomes    []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+type NodeCgroupsAnalyze struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Outcomes    []*Outcome           `json:"outcomes" yaml:"outcomes"`
+	Filters     *NodeResourceFilters `json:"filters,omitempty" yaml:"filters,omitempty"`
+}
+
+type CertificatesAnalyze struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Outcomes    []*Outcome `json:"outcomes" yaml:"outcomes"`
+	Threshold   string     `json:"threshold" yaml:"threshold"`
+}
+
+type KubeletMaxPodsAnalyze struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Outcomes    []*Outcome `json:"outcomes" yaml:"outcomes"`
+	Tolerance   string     `json:"tolerance,omitempty" yaml:"tolerance,omitempty"`
+}
+
 type AnalyzeMeta struct {
 	CheckName string                 `json:"checkName,omitempty" yaml:"checkName,omitempty"`
 	Exclude   multitype.BoolOrString `json:"exclude,omitempty" yaml:"exclude,omitempty"`
@@ -145,9 +251,19 @@ type Analyze struct {
 	ContainerRuntime         *ContainerRuntime         `json:"containerRuntime,omitempty" yaml:"containerRuntime,omitempty"`
 	Distribution             *Distribution             `json:"distribution,omitempty" yaml:"distribution,omitempty"`
 	NodeResources            *NodeResources            `json:"nodeResources,omitempty" yaml:"nodeResources,omitempty"`
+	NodeOS                   *NodeOS                   `json:"nodeOS,omitempty" yaml:"nodeOS,omitempty"`
 	TextAnalyze              *TextAnalyze              `json:"textAnalyze,omitempty" yaml:"textAnalyze,omitempty"`
 	Postgres                 *DatabaseAnalyze          `json:"postgres,omitempty" yaml:"postgres,omitempty"`
 	Mysql                    *DatabaseAnalyze          `json:"mysql,omitempty" yaml:"mysql,omitempty"`
 	Redis                    *DatabaseAnalyze          `json:"redis,omitempty" yaml:"redis,omitempty"`
 	CephStatus               *CephStatusAnalyze        `json:"cephStatus,omitempty" yaml:"cephStatus,omitempty"`
+	Sysctl                   *SysctlAnalyze            `json:"sysctl,omitempty" yaml:"sysctl,omitempty"`
+	Events                   *EventsAnalyze            `json:"events,omitempty" yaml:"events,omitempty"`
+	CompareField             *CompareField             `json:"compareField,omitempty" yaml:"compareField,omitempty"`
+	KubeletMaxPods           *KubeletMaxPodsAnalyze    `json:"kubeletMaxPods,omitempty" yaml:"kubeletMaxPods,omitempty"`
+	Certificates             *CertificatesAnalyze      `json:"certificates,omitempty" yaml:"certificates,omitempty"`
+	APIServerHealth          *APIServerHealthAnalyze   `json:"apiServerHealth,omitempty" yaml:"apiServerHealth,omitempty"`
+	PersistentVolumes        *PersistentVolumesAnalyze `json:"persistentVolumes,omitempty" yaml:"persistentVolumes,omitempty"`
+	IngressHosts             *IngressHostsAnalyze      `json:"ingressHosts,omitempty" yaml:"ingressHosts,omitempty"`
+	NodeCgroups              *NodeCgroupsAnalyze       `json:"nodeCgroups,omitempty" yaml:"nodeCgroups,omitempty"`
 }
@@ -20,7 +20,9 @@ type ClusterInfo struct {
 }
 
 type ClusterResources struct {
-	CollectorMeta `json:",inline" yaml:",inline"`
+	CollectorMeta     `json:",inline" yaml:",inline"`
+	NamespaceWorkers  int      `json:"namespaceWorkers,omitempty" yaml:"namespaceWorkers,omitempty"`
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty" yaml:"excludeNamespaces,omitempty"`
 }
 
 type Secret struct {
@@ -96,6 +98,7 @@ type HTTP struct {
 	Get           *Get   `json:"get,omitempty" yaml:"get,omitempty"`
 	Post          *Post  `json:"post,omitempty" yaml:"post,omitempty"`
 	Put           *Put   `json:"put,omitempty" yaml:"put,omitempty"`
+	Timeout       string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 }
 
 type Get struct {
@@ -133,12 +136,49 @@ type Collectd struct {
 	HostPath        string            `json:"hostPath" yaml:"hostPath"`
 }
 
+type Sysctl struct {
+	CollectorMeta   `json:",inline" yaml:",inline"`
+	Namespace       string            `json:"namespace" yaml:"namespace"`
+	Image           string            `json:"image,omitempty" yaml:"image,omitempty"`
+	ImagePullPolicy string            `json:"imagePullPolicy,omitempty" yaml:"imagePullPolicy,omitempty"`
+	ImagePullSecret *ImagePullSecrets `json:"imagePullSecret,omitempty" yaml:"imagePullSecret,omitempty"`
+	Timeout         string            `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+type NodeCgroups struct {
+	CollectorMeta   `json:",inline" yaml:",inline"`
+	Namespace       string            `json:"namespace" yaml:"namespace"`
+	Image           string            `json:"image,omitempty" yaml:"image,omitempty"`
+	ImagePullPolicy string            `json:"imagePullPolicy,omitempty" yaml:"imagePullPolicy,omitempty"`
+	ImagePullSecret *ImagePullSecrets `json:"imagePullSecret,omitempty" yaml:"imagePullSecret,omitempty"`
+	Timeout         string            `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
 type Ceph struct {
 	CollectorMeta `json:",inline" yaml:",inline"`
 	Namespace     string `json:"namespace" yaml:"namespace"`
 	Timeout       string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 }
 
+type Events struct {
+	CollectorMeta `json:",inline" yaml:",inline"`
+	Namespaces    []string `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+}
+
+type APIServerHealth struct {
+	CollectorMeta `json:",inline" yaml:",inline"`
+	Timeout       string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+type Certificates struct {
+	CollectorMeta `json:",inline" yaml:",inline"`
+	Namespaces    []string `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+}
+
+type KubeletConfig struct {
+	CollectorMeta `json:",inline" yaml:",inline"`
+}
+
 type Collect struct {
 	ClusterInfo      *ClusterInfo      `json:"clusterInfo,omitempty" yaml:"clusterInfo,omitempty"`
 	ClusterResources *ClusterResources `json:"clusterResources,omitempty" yaml:"clusterResources,omitempty"`
@@ -154,6 +194,12 @@ type Collect struct {
 	Redis            *Database         `json:"redis,omitempty" yaml:"redis,omitempty"`
 	Collectd         *Collectd         `json:"collectd,omitempty" yaml:"collectd,omitempty"`
 	Ceph             *Ceph             `json:"ceph,omitempty" yaml:"ceph,omitempty"`
+	Sysctl           *Sysctl           `json:"sysctl,omitempty" yaml:"sysctl,omitempty"`
+	Events           *Events           `json:"events,omitempty" yaml:"events,omitempty"`
+	KubeletConfig    *KubeletConfig    `json:"kubeletConfig,omitempty" yaml:"kubeletConfig,omitempty"`
+	Certificates     *Certificates     `json:"certificates,omitempty" yaml:"certificates,omitempty"`
+	APIServerHealth  *APIServerHealth  `json:"apiServerHealth,omitempty" yaml:"apiServerHealth,omitempty"`
+	NodeCgroups      *NodeCgroups      `json:"nodeCgroups,omitempty" yaml:"nodeCgroups,omitempty"`
 }
 
 func (c *Collect) AccessReviewSpecs(overrideNS string) []authorizationv1.SelfSubjectAccessReviewSpec {
@@ -313,6 +359,153 @@ func (c *Collect) AccessReviewSpecs(overrideNS string) []authorizationv1.SelfSub
 		})
 	} else if c.HTTP != nil {
 		// NOOP
+	} else if c.Sysctl != nil {
+		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   pickNamespaceOrDefault(c.Sysctl.Namespace, overrideNS),
+				Verb:        "create",
+				Group:       "apps",
+				Version:     "",
+				Resource:    "DaemonSet",
+				Subresource: "",
+				Name:        "",
+			},
+			NonResourceAttributes: nil,
+		})
+		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   pickNamespaceOrDefault(c.Sysctl.Namespace, overrideNS),
+				Verb:        "list",
+				Group:       "",
+				Version:     "",
+				Resource:    "Pod",
+				Subresource: "",
+				Name:        "",
+			},
+			NonResourceAttributes: nil,
+		})
+		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   pickNamespaceOrDefault(c.Sysctl.Namespace, overrideNS),
+				Verb:        "get",
+				Group:       "",
+				Version:     "",
+				Resource:    "Pod",
+				Subresource: "exec",
+				Name:        "",
+			},
+			NonResourceAttributes: nil,
+		})
+	} else if c.Events != nil {
+		namespaces := c.Events.Namespaces
+		if overrideNS != "" || len(namespaces) == 0 {
+			namespaces = []string{overrideNS}
+		}
+		for _, namespace := range namespaces {
+			result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace:   namespace,
+					Verb:        "list",
+					Group:       "",
+					Version:     "",
+					Resource:    "Event",
+					Subresource: "",
+					Name:        "",
+				},
+				NonResourceAttributes: nil,
+			})
+		}
+	} else if c.KubeletConfig != nil {
+		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   "",
+				Verb:        "list",
+				Group:       "",
+				Version:     "",
+				Resource:    "Node",
+				Subresource: "",
+				Name:        "",
+			},
+			NonResourceAttributes: nil,
+		})
+		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   "",
+				Verb:        "get",
+				Group:       "",
+				Version:     "",
+				Resource:    "Node",
+				Subresource: "proxy",
+				Name:        "",
+			},
+			NonResourceAttributes: nil,
+		})
+	} else if c.Certificates != nil {
+		namespaces := c.Certificates.Namespaces
+		if overrideNS != "" || len(namespaces) == 0 {
+			namespaces = []string{overrideNS}
+		}
+		for _, namespace := range namespaces {
+			result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace:   namespace,
+					Verb:        "list",
+					Group:       "",
+					Version:     "",
+					Resource:    "Secret",
+					Subresource: "",
+					Name:        "",
+				},
+				NonResourceAttributes: nil,
+			})
+		}
+	} else if c.APIServerHealth != nil {
+		for _, path := range []string{"/readyz", "/livez"} {
+			result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: nil,
+				NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+					Path: path,
+					Verb: "get",
+				},
+			})
+		}
+	} else if c.NodeCgroups != nil {
+		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   pickNamespaceOrDefault(c.NodeCgroups.Namespace, overrideNS),
+				Verb:        "create",
+				Group:       "apps",
+				Version:     "",
+				Resource:    "DaemonSet",
+				Subresource: "",
+				Name:        "",
+			},
+			NonResourceAttributes: nil,
+		})
+		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   pickNamespaceOrDefault(c.NodeCgroups.Namespace, overrideNS),
+				Verb:        "list",
+				Group:       "",
+				Version:     "",
+				Resource:    "Pod",
+				Subresource: "",
+				Name:        "",
+			},
+			NonResourceAttributes: nil,
+		})
+		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   pickNamespaceOrDefault(c.NodeCgroups.Namespace, overrideNS),
+				Verb:        "get",
+				Group:       "",
+				Version:     "",
+				Resource:    "Pod",
+				Subresource: "exec",
+				Name:        "",
+			},
+			NonResourceAttributes: nil,
+		})
 	}
 
 	return result
@@ -361,6 +554,30 @@ func (c *Collect) GetName() string {
 		collector = "ceph"
 		name = c.Ceph.CollectorName
 	}
+	if c.Sysctl != nil {
+		collector = "sysctl"
+		name = c.Sysctl.CollectorName
+	}
+	if c.Events != nil {
+		collector = "events"
+		name = c.Events.CollectorName
+	}
+	if c.KubeletConfig != nil {
+		collector = "kubelet-config"
+		name = c.KubeletConfig.CollectorName
+	}
+	if c.Certificates != nil {
+		collector = "certificates"
+		name = c.Certificates.CollectorName
+	}
+	if c.APIServerHealth != nil {
+		collector = "apiserver-health"
+		name = c.APIServerHealth.CollectorName
+	}
+	if c.NodeCgroups != nil {
+		collector = "node-cgroups"
+		name = c.NodeCgroups.CollectorName
+	}
 
 	if collector == "" {
 		return "<none>"
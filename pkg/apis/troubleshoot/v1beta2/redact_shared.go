@@ -3,6 +3,9 @@ package v1beta2
 type Regex struct {
 	Selector string `json:"selector,omitempty" yaml:"selector,omitempty"`
 	Redactor string `json:"redactor,omitempty" yaml:"redactor,omitempty"`
+	// Block matches the redactor against text spanning many lines, with . matching newlines. The selector, if set,
+	// matches the line a block starts on, otherwise the redactor is matched against the whole file.
+	Block bool `json:"block,omitempty" yaml:"block,omitempty"`
 }
 
 type FileSelector struct {
@@ -20,4 +23,5 @@ type Redact struct {
 	Name         string       `json:"name,omitempty" yaml:"name,omitempty"`
 	FileSelector FileSelector `json:"fileSelector,omitempty" yaml:"fileSelector,omitempty"`
 	Removals     Removals     `json:"removals,omitempty" yaml:"removals,omitempty"`
+	ScanBinary   bool         `json:"scanBinary,omitempty" yaml:"scanBinary,omitempty"`
 }
@@ -24,6 +24,49 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerHealth) DeepCopyInto(out *APIServerHealth) {
+	*out = *in
+	out.CollectorMeta = in.CollectorMeta
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerHealth.
+func (in *APIServerHealth) DeepCopy() *APIServerHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerHealthAnalyze) DeepCopyInto(out *APIServerHealthAnalyze) {
+	*out = *in
+	out.AnalyzeMeta = in.AnalyzeMeta
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerHealthAnalyze.
+func (in *APIServerHealthAnalyze) DeepCopy() *APIServerHealthAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerHealthAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AfterCollection) DeepCopyInto(out *AfterCollection) {
 	*out = *in
@@ -107,6 +150,11 @@ func (in *Analyze) DeepCopyInto(out *Analyze) {
 		*out = new(NodeResources)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodeOS != nil {
+		in, out := &in.NodeOS, &out.NodeOS
+		*out = new(NodeOS)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.TextAnalyze != nil {
 		in, out := &in.TextAnalyze, &out.TextAnalyze
 		*out = new(TextAnalyze)
@@ -132,6 +180,51 @@ func (in *Analyze) DeepCopyInto(out *Analyze) {
 		*out = new(CephStatusAnalyze)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Sysctl != nil {
+		in, out := &in.Sysctl, &out.Sysctl
+		*out = new(SysctlAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = new(EventsAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CompareField != nil {
+		in, out := &in.CompareField, &out.CompareField
+		*out = new(CompareField)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubeletMaxPods != nil {
+		in, out := &in.KubeletMaxPods, &out.KubeletMaxPods
+		*out = new(KubeletMaxPodsAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = new(CertificatesAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APIServerHealth != nil {
+		in, out := &in.APIServerHealth, &out.APIServerHealth
+		*out = new(APIServerHealthAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PersistentVolumes != nil {
+		in, out := &in.PersistentVolumes, &out.PersistentVolumes
+		*out = new(PersistentVolumesAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IngressHosts != nil {
+		in, out := &in.IngressHosts, &out.IngressHosts
+		*out = new(IngressHostsAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeCgroups != nil {
+		in, out := &in.NodeCgroups, &out.NodeCgroups
+		*out = new(NodeCgroupsAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Analyze.
@@ -330,6 +423,54 @@ func (in *CephStatusAnalyze) DeepCopy() *CephStatusAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Certificates) DeepCopyInto(out *Certificates) {
+	*out = *in
+	out.CollectorMeta = in.CollectorMeta
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Certificates.
+func (in *Certificates) DeepCopy() *Certificates {
+	if in == nil {
+		return nil
+	}
+	out := new(Certificates)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificatesAnalyze) DeepCopyInto(out *CertificatesAnalyze) {
+	*out = *in
+	out.AnalyzeMeta = in.AnalyzeMeta
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatesAnalyze.
+func (in *CertificatesAnalyze) DeepCopy() *CertificatesAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificatesAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterInfo) DeepCopyInto(out *ClusterInfo) {
 	*out = *in
@@ -350,6 +491,11 @@ func (in *ClusterInfo) DeepCopy() *ClusterInfo {
 func (in *ClusterResources) DeepCopyInto(out *ClusterResources) {
 	*out = *in
 	out.CollectorMeta = in.CollectorMeta
+	if in.ExcludeNamespaces != nil {
+		in, out := &in.ExcludeNamespaces, &out.ExcludeNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResources.
@@ -400,7 +546,7 @@ func (in *Collect) DeepCopyInto(out *Collect) {
 	if in.ClusterResources != nil {
 		in, out := &in.ClusterResources, &out.ClusterResources
 		*out = new(ClusterResources)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Secret != nil {
 		in, out := &in.Secret, &out.Secret
@@ -462,6 +608,36 @@ func (in *Collect) DeepCopyInto(out *Collect) {
 		*out = new(Ceph)
 		**out = **in
 	}
+	if in.Sysctl != nil {
+		in, out := &in.Sysctl, &out.Sysctl
+		*out = new(Sysctl)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = new(Events)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubeletConfig != nil {
+		in, out := &in.KubeletConfig, &out.KubeletConfig
+		*out = new(KubeletConfig)
+		**out = **in
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = new(Certificates)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APIServerHealth != nil {
+		in, out := &in.APIServerHealth, &out.APIServerHealth
+		*out = new(APIServerHealth)
+		**out = **in
+	}
+	if in.NodeCgroups != nil {
+		in, out := &in.NodeCgroups, &out.NodeCgroups
+		*out = new(NodeCgroups)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Collect.
@@ -649,6 +825,33 @@ func (in *CollectorStatus) DeepCopy() *CollectorStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompareField) DeepCopyInto(out *CompareField) {
+	*out = *in
+	out.AnalyzeMeta = in.AnalyzeMeta
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompareField.
+func (in *CompareField) DeepCopy() *CompareField {
+	if in == nil {
+		return nil
+	}
+	out := new(CompareField)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerRuntime) DeepCopyInto(out *ContainerRuntime) {
 	*out = *in
@@ -837,6 +1040,54 @@ func (in *Distribution) DeepCopy() *Distribution {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Events) DeepCopyInto(out *Events) {
+	*out = *in
+	out.CollectorMeta = in.CollectorMeta
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Events.
+func (in *Events) DeepCopy() *Events {
+	if in == nil {
+		return nil
+	}
+	out := new(Events)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventsAnalyze) DeepCopyInto(out *EventsAnalyze) {
+	*out = *in
+	out.AnalyzeMeta = in.AnalyzeMeta
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventsAnalyze.
+func (in *EventsAnalyze) DeepCopy() *EventsAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(EventsAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Exec) DeepCopyInto(out *Exec) {
 	*out = *in
@@ -1017,6 +1268,76 @@ func (in *Ingress) DeepCopy() *Ingress {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressHostsAnalyze) DeepCopyInto(out *IngressHostsAnalyze) {
+	*out = *in
+	out.AnalyzeMeta = in.AnalyzeMeta
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressHostsAnalyze.
+func (in *IngressHostsAnalyze) DeepCopy() *IngressHostsAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressHostsAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletConfig) DeepCopyInto(out *KubeletConfig) {
+	*out = *in
+	out.CollectorMeta = in.CollectorMeta
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletConfig.
+func (in *KubeletConfig) DeepCopy() *KubeletConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletMaxPodsAnalyze) DeepCopyInto(out *KubeletMaxPodsAnalyze) {
+	*out = *in
+	out.AnalyzeMeta = in.AnalyzeMeta
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletMaxPodsAnalyze.
+func (in *KubeletMaxPodsAnalyze) DeepCopy() *KubeletMaxPodsAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletMaxPodsAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogLimits) DeepCopyInto(out *LogLimits) {
 	*out = *in
@@ -1064,6 +1385,86 @@ func (in *Logs) DeepCopy() *Logs {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeCgroups) DeepCopyInto(out *NodeCgroups) {
+	*out = *in
+	out.CollectorMeta = in.CollectorMeta
+	if in.ImagePullSecret != nil {
+		in, out := &in.ImagePullSecret, &out.ImagePullSecret
+		*out = new(ImagePullSecrets)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCgroups.
+func (in *NodeCgroups) DeepCopy() *NodeCgroups {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCgroups)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeCgroupsAnalyze) DeepCopyInto(out *NodeCgroupsAnalyze) {
+	*out = *in
+	out.AnalyzeMeta = in.AnalyzeMeta
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = new(NodeResourceFilters)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCgroupsAnalyze.
+func (in *NodeCgroupsAnalyze) DeepCopy() *NodeCgroupsAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCgroupsAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeOS) DeepCopyInto(out *NodeOS) {
+	*out = *in
+	out.AnalyzeMeta = in.AnalyzeMeta
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeOS.
+func (in *NodeOS) DeepCopy() *NodeOS {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeOS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeResourceFilters) DeepCopyInto(out *NodeResourceFilters) {
 	*out = *in
@@ -1072,6 +1473,23 @@ func (in *NodeResourceFilters) DeepCopyInto(out *NodeResourceFilters) {
 		*out = new(NodeResourceSelectors)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ExcludeTaints != nil {
+		in, out := &in.ExcludeTaints, &out.ExcludeTaints
+		*out = make([]NodeResourceTaint, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeConditions != nil {
+		in, out := &in.ExcludeConditions, &out.ExcludeConditions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]NodeResourceGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeResourceFilters.
@@ -1084,6 +1502,46 @@ func (in *NodeResourceFilters) DeepCopy() *NodeResourceFilters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeResourceGroup) DeepCopyInto(out *NodeResourceGroup) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(NodeResourceSelectors)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeResourceGroup.
+func (in *NodeResourceGroup) DeepCopy() *NodeResourceGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeResourceGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeResourceSelectorRequirement) DeepCopyInto(out *NodeResourceSelectorRequirement) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeResourceSelectorRequirement.
+func (in *NodeResourceSelectorRequirement) DeepCopy() *NodeResourceSelectorRequirement {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeResourceSelectorRequirement)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeResourceSelectors) DeepCopyInto(out *NodeResourceSelectors) {
 	*out = *in
@@ -1094,6 +1552,20 @@ func (in *NodeResourceSelectors) DeepCopyInto(out *NodeResourceSelectors) {
 			(*out)[key] = val
 		}
 	}
+	if in.MatchAnnotation != nil {
+		in, out := &in.MatchAnnotation, &out.MatchAnnotation
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MatchExpressions != nil {
+		in, out := &in.MatchExpressions, &out.MatchExpressions
+		*out = make([]NodeResourceSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeResourceSelectors.
@@ -1106,6 +1578,21 @@ func (in *NodeResourceSelectors) DeepCopy() *NodeResourceSelectors {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeResourceTaint) DeepCopyInto(out *NodeResourceTaint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeResourceTaint.
+func (in *NodeResourceTaint) DeepCopy() *NodeResourceTaint {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeResourceTaint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeResources) DeepCopyInto(out *NodeResources) {
 	*out = *in
@@ -1126,6 +1613,43 @@ func (in *NodeResources) DeepCopyInto(out *NodeResources) {
 		*out = new(NodeResourceFilters)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Deployment != nil {
+		in, out := &in.Deployment, &out.Deployment
+		*out = new(NodeResourcesWorkload)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StatefulSet != nil {
+		in, out := &in.StatefulSet, &out.StatefulSet
+		*out = new(NodeResourcesWorkload)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DaemonSet != nil {
+		in, out := &in.DaemonSet, &out.DaemonSet
+		*out = new(NodeResourcesWorkload)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OnInstall != nil {
+		in, out := &in.OnInstall, &out.OnInstall
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.OnUpdate != nil {
+		in, out := &in.OnUpdate, &out.OnUpdate
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeResources.
@@ -1138,6 +1662,28 @@ func (in *NodeResources) DeepCopy() *NodeResources {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeResourcesWorkload) DeepCopyInto(out *NodeResourcesWorkload) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeResourcesWorkload.
+func (in *NodeResourcesWorkload) DeepCopy() *NodeResourcesWorkload {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeResourcesWorkload)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Outcome) DeepCopyInto(out *Outcome) {
 	*out = *in
@@ -1168,6 +1714,33 @@ func (in *Outcome) DeepCopy() *Outcome {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistentVolumesAnalyze) DeepCopyInto(out *PersistentVolumesAnalyze) {
+	*out = *in
+	out.AnalyzeMeta = in.AnalyzeMeta
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumesAnalyze.
+func (in *PersistentVolumesAnalyze) DeepCopy() *PersistentVolumesAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentVolumesAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Post) DeepCopyInto(out *Post) {
 	*out = *in
@@ -1769,6 +2342,54 @@ func (in *SupportBundleVersionSpec) DeepCopy() *SupportBundleVersionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Sysctl) DeepCopyInto(out *Sysctl) {
+	*out = *in
+	out.CollectorMeta = in.CollectorMeta
+	if in.ImagePullSecret != nil {
+		in, out := &in.ImagePullSecret, &out.ImagePullSecret
+		*out = new(ImagePullSecrets)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Sysctl.
+func (in *Sysctl) DeepCopy() *Sysctl {
+	if in == nil {
+		return nil
+	}
+	out := new(Sysctl)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SysctlAnalyze) DeepCopyInto(out *SysctlAnalyze) {
+	*out = *in
+	out.AnalyzeMeta = in.AnalyzeMeta
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SysctlAnalyze.
+func (in *SysctlAnalyze) DeepCopy() *SysctlAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(SysctlAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TextAnalyze) DeepCopyInto(out *TextAnalyze) {
 	*out = *in
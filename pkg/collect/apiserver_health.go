@@ -0,0 +1,122 @@
+package collect
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/redact"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// defaultAPIServerHealthTimeout is how long each health endpoint is given when the collector has no timeout
+const defaultAPIServerHealthTimeout = 10 * time.Second
+
+// apiServerHealthEndpoints are the health endpoints collected, by the name of the file their body is stored in
+var apiServerHealthEndpoints = []struct {
+	name string
+	path string
+}{
+	{name: "readyz.txt", path: "/readyz"},
+	{name: "livez.txt", path: "/livez"},
+}
+
+// APIServerHealthRequest describes how the health endpoints were requested and what they returned. Credentials are
+// never included, only whether they were used.
+type APIServerHealthRequest struct {
+	Host        string                 `json:"host"`
+	BearerToken string                 `json:"bearerToken,omitempty"`
+	Username    string                 `json:"username,omitempty"`
+	Password    string                 `json:"password,omitempty"`
+	Checks      []APIServerHealthCheck `json:"checks"`
+}
+
+// APIServerHealthCheck is the result of requesting one health endpoint
+type APIServerHealthCheck struct {
+	Path       string `json:"path"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// APIServerHealth collects the verbose /readyz and /livez output of the API server into apiserver-health/readyz.txt
+// and apiserver-health/livez.txt, so that text analyzers can also match them, along with how they were requested in
+// apiserver-health/request.json. The collector uses the same client config as the others, so it works both in
+// cluster and from a kubeconfig.
+func APIServerHealth(c *Collector, apiServerHealthCollector *troubleshootv1beta2.APIServerHealth) (map[string][]byte, error) {
+	timeout := defaultAPIServerHealthTimeout
+	if apiServerHealthCollector.Timeout != "" {
+		parsed, err := time.ParseDuration(apiServerHealthCollector.Timeout)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse timeout")
+		}
+		timeout = parsed
+	}
+
+	client, err := kubernetes.NewForConfig(c.ClientConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create client from config")
+	}
+
+	ctx := context.Background()
+	request := apiServerHealthRequest(c.ClientConfig)
+	apiServerHealthOutput := map[string][]byte{}
+	for _, endpoint := range apiServerHealthEndpoints {
+		check := APIServerHealthCheck{
+			Path: endpoint.path,
+		}
+
+		result := client.Discovery().RESTClient().Get().AbsPath(endpoint.path).Param("verbose", "").Timeout(timeout).Do(ctx)
+		result.StatusCode(&check.StatusCode)
+		body, err := result.Raw()
+		if err != nil {
+			check.Error = redactAPIServerHealthError(err.Error(), c.ClientConfig)
+		}
+		// unhealthy endpoints return their checks with an error status
+		if len(body) > 0 {
+			apiServerHealthOutput[filepath.Join("apiserver-health", endpoint.name)] = body
+		}
+
+		request.Checks = append(request.Checks, check)
+	}
+
+	b, err := json.MarshalIndent(request, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal request")
+	}
+	apiServerHealthOutput[filepath.Join("apiserver-health", "request.json")] = b
+
+	return apiServerHealthOutput, nil
+}
+
+// apiServerHealthRequest describes the client config with its credentials masked
+func apiServerHealthRequest(config *rest.Config) APIServerHealthRequest {
+	request := APIServerHealthRequest{
+		Host:   config.Host,
+		Checks: []APIServerHealthCheck{},
+	}
+	if config.BearerToken != "" || config.BearerTokenFile != "" {
+		request.BearerToken = redact.MASK_TEXT
+	}
+	if config.Username != "" {
+		request.Username = redact.MASK_TEXT
+	}
+	if config.Password != "" {
+		request.Password = redact.MASK_TEXT
+	}
+	return request
+}
+
+// redactAPIServerHealthError masks the credentials of the client config in case the error message repeats them
+func redactAPIServerHealthError(message string, config *rest.Config) string {
+	for _, secret := range []string{config.BearerToken, config.Password} {
+		if secret != "" {
+			message = strings.Replace(message, secret, redact.MASK_TEXT, -1)
+		}
+	}
+	return message
+}
@@ -0,0 +1,39 @@
+package collect
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/replicatedhq/troubleshoot/pkg/redact"
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+	"k8s.io/client-go/rest"
+)
+
+func Test_apiServerHealthRequest(t *testing.T) {
+	scopetest := scopeagent.StartTest(t)
+	defer scopetest.End()
+	req := require.New(t)
+
+	config := &rest.Config{
+		Host:        "https://10.96.0.1:443",
+		BearerToken: "eyJhbGciOiJSUzI1NiJ9.c2VjcmV0.c2lnbmF0dXJl",
+		Password:    "hunter2",
+	}
+
+	request := apiServerHealthRequest(config)
+	req.Equal(APIServerHealthRequest{
+		Host:        "https://10.96.0.1:443",
+		BearerToken: redact.MASK_TEXT,
+		Password:    redact.MASK_TEXT,
+		Checks:      []APIServerHealthCheck{},
+	}, request)
+
+	b, err := json.Marshal(request)
+	req.NoError(err)
+	req.NotContains(string(b), config.BearerToken)
+	req.NotContains(string(b), config.Password)
+
+	message := redactAPIServerHealthError("Get https://10.96.0.1:443/readyz: Authorization: Bearer "+config.BearerToken, config)
+	req.Equal("Get https://10.96.0.1:443/readyz: Authorization: Bearer "+redact.MASK_TEXT, message)
+}
@@ -0,0 +1,123 @@
+package collect
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CertificateInfo is the expiry metadata of a certificate found in a secret. It never includes the certificate or
+// its private key.
+type CertificateInfo struct {
+	Namespace  string    `json:"namespace"`
+	SecretName string    `json:"secretName"`
+	Subject    string    `json:"subject"`
+	Issuer     string    `json:"issuer"`
+	DNSNames   []string  `json:"dnsNames,omitempty"`
+	NotBefore  time.Time `json:"notBefore"`
+	NotAfter   time.Time `json:"notAfter"`
+}
+
+// Certificates collects the expiry of the certificates in the tls.crt key of the secrets in the listed namespaces,
+// or in every namespace when none are listed, into certificates/certificates.json. Only tls.crt is read, so private
+// keys are never collected. Certificates that cannot be parsed are listed in certificates/errors.json.
+func Certificates(c *Collector, certificatesCollector *troubleshootv1beta2.Certificates) (map[string][]byte, error) {
+	ctx := context.Background()
+
+	client, err := kubernetes.NewForConfig(c.ClientConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create client from config")
+	}
+
+	namespaces := certificatesCollector.Namespaces
+	if c.Namespace != "" {
+		namespaces = []string{c.Namespace}
+	}
+	if len(namespaces) == 0 {
+		// the empty namespace lists secrets across all namespaces
+		namespaces = []string{""}
+	}
+
+	certificates := []CertificateInfo{}
+	var certificateErrors []string
+	for _, namespace := range namespaces {
+		secrets, err := client.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			certificateErrors = append(certificateErrors, fmt.Sprintf("failed to list secrets in namespace %q: %s", namespace, err.Error()))
+			continue
+		}
+		for _, secret := range secrets.Items {
+			found, err := secretCertificates(secret)
+			if err != nil {
+				certificateErrors = append(certificateErrors, err.Error())
+			}
+			certificates = append(certificates, found...)
+		}
+	}
+
+	b, err := json.MarshalIndent(certificates, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal certificates")
+	}
+
+	certificatesOutput := map[string][]byte{
+		filepath.Join("certificates", "certificates.json"): b,
+	}
+	certificatesOutput[filepath.Join("certificates", "errors.json")], err = marshalNonNil(certificateErrors)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal errors")
+	}
+
+	return certificatesOutput, nil
+}
+
+// secretCertificates returns the expiry metadata of every certificate in the tls.crt key of the secret, which may
+// hold a chain. Secrets without a tls.crt key have none.
+func secretCertificates(secret corev1.Secret) ([]CertificateInfo, error) {
+	data, ok := secret.Data[corev1.TLSCertKey]
+	if !ok {
+		return nil, nil
+	}
+
+	certificates := []CertificateInfo{}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return certificates, errors.Wrapf(err, "failed to parse certificate in secret %s/%s", secret.Namespace, secret.Name)
+		}
+		certificates = append(certificates, CertificateInfo{
+			Namespace:  secret.Namespace,
+			SecretName: secret.Name,
+			Subject:    cert.Subject.String(),
+			Issuer:     cert.Issuer.String(),
+			DNSNames:   cert.DNSNames,
+			NotBefore:  cert.NotBefore,
+			NotAfter:   cert.NotAfter,
+		})
+	}
+
+	if len(certificates) == 0 {
+		return nil, errors.Errorf("no certificate found in %s of secret %s/%s", corev1.TLSCertKey, secret.Namespace, secret.Name)
+	}
+
+	return certificates, nil
+}
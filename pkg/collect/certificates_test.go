@@ -0,0 +1,70 @@
+package collect
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_secretCertificates(t *testing.T) {
+	scopetest := scopeagent.StartTest(t)
+	defer scopetest.End()
+	req := require.New(t)
+
+	notAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	req.NoError(err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    notAfter.AddDate(-1, 0, 0),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	req.NoError(err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	req.NoError(err)
+
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "example-tls"},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		},
+	}
+
+	certificates, err := secretCertificates(secret)
+	req.NoError(err)
+	req.Len(certificates, 1)
+	req.Equal("default", certificates[0].Namespace)
+	req.Equal("example-tls", certificates[0].SecretName)
+	req.Equal("CN=example.com", certificates[0].Subject)
+	req.Equal([]string{"example.com"}, certificates[0].DNSNames)
+	req.True(notAfter.Equal(certificates[0].NotAfter))
+
+	b, err := json.Marshal(certificates)
+	req.NoError(err)
+	req.NotContains(string(b), "PRIVATE KEY")
+	req.NotContains(string(b), string(secret.Data[corev1.TLSPrivateKeyKey]))
+
+	none, err := secretCertificates(corev1.Secret{Data: map[string][]byte{"password": []byte("hunter2")}})
+	req.NoError(err)
+	req.Empty(none)
+
+	_, err = secretCertificates(corev1.Secret{Data: map[string][]byte{corev1.TLSCertKey: []byte("not a certificate")}})
+	req.Error(err)
+}
@@ -6,12 +6,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"path" // this code uses 'path' and not 'path/filepath' because we don't want backslashes on windows
+	"sort"
 	"strings"
+	"sync"
 
+	"github.com/pkg/errors"
 	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiextensionsv1beta1clientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1beta1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -24,11 +28,27 @@ func ClusterResources(c *Collector) (map[string][]byte, error) {
 
 	ctx := context.Background()
 
+	workers := defaultNamespaceWorkers
+	if c.Collect.ClusterResources != nil && c.Collect.ClusterResources.NamespaceWorkers > 0 {
+		workers = c.Collect.ClusterResources.NamespaceWorkers
+	}
+
+	// excluded namespaces are never listed, so nothing from them is collected
+	var excludeNamespaces []string
+	if c.Collect.ClusterResources != nil {
+		excludeNamespaces = c.Collect.ClusterResources.ExcludeNamespaces
+	}
+	for _, pattern := range excludeNamespaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid excludeNamespaces pattern %q", pattern)
+		}
+	}
+
 	clusterResourcesOutput := map[string][]byte{}
 	// namespaces
 	var namespaceNames []string
 	if c.Namespace == "" {
-		namespaces, namespaceList, namespaceErrors := namespaces(ctx, client)
+		namespaces, namespaceList, namespaceErrors := namespaces(ctx, client, excludeNamespaces)
 		clusterResourcesOutput["cluster-resources/namespaces.json"] = namespaces
 		clusterResourcesOutput["cluster-resources/namespaces-errors.json"], err = marshalNonNil(namespaceErrors)
 		if err != nil {
@@ -39,7 +59,7 @@ func ClusterResources(c *Collector) (map[string][]byte, error) {
 				namespaceNames = append(namespaceNames, namespace.Name)
 			}
 		}
-	} else {
+	} else if !isNamespaceExcluded(c.Namespace, excludeNamespaces) {
 		namespaces, namespaceErrors := getNamespace(ctx, client, c.Namespace)
 		clusterResourcesOutput["cluster-resources/namespaces.json"] = namespaces
 		clusterResourcesOutput["cluster-resources/namespaces-errors.json"], err = marshalNonNil(namespaceErrors)
@@ -48,7 +68,7 @@ func ClusterResources(c *Collector) (map[string][]byte, error) {
 		}
 		namespaceNames = append(namespaceNames, c.Namespace)
 	}
-	pods, podErrors := pods(ctx, client, namespaceNames)
+	pods, podErrors := pods(ctx, client, namespaceNames, workers)
 	for k, v := range pods {
 		clusterResourcesOutput[path.Join("cluster-resources/pods", k)] = v
 	}
@@ -57,8 +77,16 @@ func ClusterResources(c *Collector) (map[string][]byte, error) {
 		return nil, err
 	}
 
+	// pod resources per node
+	nodePodResources, nodePodResourcesErrors := nodePodResources(pods)
+	clusterResourcesOutput["cluster-resources/node-pod-resources.json"] = nodePodResources
+	clusterResourcesOutput["cluster-resources/node-pod-resources-errors.json"], err = marshalNonNil(nodePodResourcesErrors)
+	if err != nil {
+		return nil, err
+	}
+
 	// services
-	services, servicesErrors := services(ctx, client, namespaceNames)
+	services, servicesErrors := services(ctx, client, namespaceNames, workers)
 	for k, v := range services {
 		clusterResourcesOutput[path.Join("cluster-resources/services", k)] = v
 	}
@@ -68,7 +96,7 @@ func ClusterResources(c *Collector) (map[string][]byte, error) {
 	}
 
 	// deployments
-	deployments, deploymentsErrors := deployments(ctx, client, namespaceNames)
+	deployments, deploymentsErrors := deployments(ctx, client, namespaceNames, workers)
 	for k, v := range deployments {
 		clusterResourcesOutput[path.Join("cluster-resources/deployments", k)] = v
 	}
@@ -78,7 +106,7 @@ func ClusterResources(c *Collector) (map[string][]byte, error) {
 	}
 
 	// statefulsets
-	statefulsets, statefulsetsErrors := statefulsets(ctx, client, namespaceNames)
+	statefulsets, statefulsetsErrors := statefulsets(ctx, client, namespaceNames, workers)
 	for k, v := range statefulsets {
 		clusterResourcesOutput[path.Join("cluster-resources/statefulsets", k)] = v
 	}
@@ -87,8 +115,18 @@ func ClusterResources(c *Collector) (map[string][]byte, error) {
 		return nil, err
 	}
 
+	// daemonsets
+	daemonsets, daemonsetsErrors := daemonsets(ctx, client, namespaceNames, workers)
+	for k, v := range daemonsets {
+		clusterResourcesOutput[path.Join("cluster-resources/daemonsets", k)] = v
+	}
+	clusterResourcesOutput["cluster-resources/daemonsets-errors.json"], err = marshalNonNil(daemonsetsErrors)
+	if err != nil {
+		return nil, err
+	}
+
 	// ingress
-	ingress, ingressErrors := ingress(ctx, client, namespaceNames)
+	ingress, ingressErrors := ingress(ctx, client, namespaceNames, workers)
 	for k, v := range ingress {
 		clusterResourcesOutput[path.Join("cluster-resources/ingress", k)] = v
 	}
@@ -96,6 +134,10 @@ func ClusterResources(c *Collector) (map[string][]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	clusterResourcesOutput["cluster-resources/ingress.json"], err = allIngresses(ingress)
+	if err != nil {
+		return nil, err
+	}
 
 	// storage classes
 	storageClasses, storageErrors := storageClasses(ctx, client)
@@ -105,6 +147,14 @@ func ClusterResources(c *Collector) (map[string][]byte, error) {
 		return nil, err
 	}
 
+	// persistent volumes
+	pvs, pvsErrors := pvs(ctx, client)
+	clusterResourcesOutput["cluster-resources/pvs.json"] = pvs
+	clusterResourcesOutput["cluster-resources/pvs-errors.json"], err = marshalNonNil(pvsErrors)
+	if err != nil {
+		return nil, err
+	}
+
 	// crds
 	crdClient, err := apiextensionsv1beta1clientset.NewForConfig(c.ClientConfig)
 	if err != nil {
@@ -135,6 +185,14 @@ func ClusterResources(c *Collector) (map[string][]byte, error) {
 		return nil, err
 	}
 
+	// node metrics
+	nodeMetrics, nodeMetricsErrors := nodeMetrics(ctx, client)
+	clusterResourcesOutput["cluster-resources/node-metrics.json"] = nodeMetrics
+	clusterResourcesOutput["cluster-resources/node-metrics-errors.json"], err = marshalNonNil(nodeMetricsErrors)
+	if err != nil {
+		return nil, err
+	}
+
 	groups, resources, groupsResourcesErrors := apiResources(ctx, client)
 	clusterResourcesOutput["cluster-resources/groups.json"] = groups
 	clusterResourcesOutput["cluster-resources/resources.json"] = resources
@@ -144,7 +202,7 @@ func ClusterResources(c *Collector) (map[string][]byte, error) {
 	}
 
 	// limit ranges
-	limitRanges, limitRangesErrors := limitRanges(ctx, client, namespaceNames)
+	limitRanges, limitRangesErrors := limitRanges(ctx, client, namespaceNames, workers)
 	for k, v := range limitRanges {
 		clusterResourcesOutput[path.Join("cluster-resources/limitranges", k)] = v
 	}
@@ -154,7 +212,7 @@ func ClusterResources(c *Collector) (map[string][]byte, error) {
 	}
 
 	// auth cani
-	authCanI, authCanIErrors := authCanI(ctx, client, namespaceNames)
+	authCanI, authCanIErrors := authCanI(ctx, client, namespaceNames, workers)
 	for k, v := range authCanI {
 		clusterResourcesOutput[path.Join("cluster-resources/auth-cani-list", k)] = v
 	}
@@ -164,7 +222,7 @@ func ClusterResources(c *Collector) (map[string][]byte, error) {
 	}
 
 	//Events
-	events, eventsErrors := events(ctx, client, namespaceNames)
+	events, eventsErrors := events(ctx, client, namespaceNames, workers)
 	for k, v := range events {
 		clusterResourcesOutput[path.Join("cluster-resources/events", k)] = v
 	}
@@ -176,12 +234,70 @@ func ClusterResources(c *Collector) (map[string][]byte, error) {
 	return clusterResourcesOutput, nil
 }
 
-func namespaces(ctx context.Context, client *kubernetes.Clientset) ([]byte, *corev1.NamespaceList, []string) {
+// defaultNamespaceWorkers is how many namespaces are listed at once. It matches the default client burst, since more
+// concurrent requests would only wait on the client rate limiter.
+const defaultNamespaceWorkers = 10
+
+// listByNamespace calls list for every namespace, with up to workers namespaces at once, and returns the marshalled
+// results by "<namespace>.json" along with the errors by namespace
+func listByNamespace(namespaces []string, workers int, list func(namespace string) (interface{}, error)) (map[string][]byte, map[string]string) {
+	resultsByNamespace := make(map[string][]byte)
+	errorsByNamespace := make(map[string]string)
+	var mut sync.Mutex
+
+	if workers <= 0 {
+		workers = 1
+	}
+
+	namespaceCh := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < workers && i < len(namespaces); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for namespace := range namespaceCh {
+				items, err := list(namespace)
+				var b []byte
+				if err == nil {
+					b, err = json.MarshalIndent(items, "", "  ")
+				}
+
+				mut.Lock()
+				if err != nil {
+					errorsByNamespace[namespace] = err.Error()
+				} else {
+					resultsByNamespace[namespace+".json"] = b
+				}
+				mut.Unlock()
+			}
+		}()
+	}
+
+	for _, namespace := range namespaces {
+		namespaceCh <- namespace
+	}
+	close(namespaceCh)
+	wg.Wait()
+
+	return resultsByNamespace, errorsByNamespace
+}
+
+func namespaces(ctx context.Context, client *kubernetes.Clientset, excludeNamespaces []string) ([]byte, *corev1.NamespaceList, []string) {
 	namespaces, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, nil, []string{err.Error()}
 	}
 
+	if len(excludeNamespaces) > 0 {
+		included := []corev1.Namespace{}
+		for _, namespace := range namespaces.Items {
+			if !isNamespaceExcluded(namespace.Name, excludeNamespaces) {
+				included = append(included, namespace)
+			}
+		}
+		namespaces.Items = included
+	}
+
 	b, err := json.MarshalIndent(namespaces.Items, "", "  ")
 	if err != nil {
 		return nil, nil, []string{err.Error()}
@@ -190,6 +306,17 @@ func namespaces(ctx context.Context, client *kubernetes.Clientset) ([]byte, *cor
 	return b, namespaces, nil
 }
 
+// isNamespaceExcluded matches the namespace against glob patterns such as "cert-manager" or "vault-*". The patterns
+// have already been checked, so a bad pattern never matches.
+func isNamespaceExcluded(namespace string, excludeNamespaces []string) bool {
+	for _, pattern := range excludeNamespaces {
+		if ok, _ := path.Match(pattern, namespace); ok {
+			return true
+		}
+	}
+	return false
+}
+
 func getNamespace(ctx context.Context, client *kubernetes.Clientset, namespace string) ([]byte, []string) {
 	namespaces, err := client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
 	if err != nil {
@@ -204,119 +331,185 @@ func getNamespace(ctx context.Context, client *kubernetes.Clientset, namespace s
 	return b, nil
 }
 
-func pods(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	podsByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
+func pods(ctx context.Context, client *kubernetes.Clientset, namespaces []string, workers int) (map[string][]byte, map[string]string) {
+	return listByNamespace(namespaces, workers, func(namespace string) (interface{}, error) {
 		pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
 		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		b, err := json.MarshalIndent(pods.Items, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
+			return nil, err
 		}
+		return pods.Items, nil
+	})
+}
 
-		podsByNamespace[namespace+".json"] = b
-	}
-
-	return podsByNamespace, errorsByNamespace
+// NodePodResources is the total of the resource requests and limits of the pods scheduled to a node
+type NodePodResources struct {
+	NodeName string              `json:"nodeName"`
+	PodCount int                 `json:"podCount"`
+	Requests corev1.ResourceList `json:"requests"`
+	Limits   corev1.ResourceList `json:"limits"`
 }
 
-func services(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	servicesByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
+// nodePodResources totals the requests and limits of the collected pods by node, the same way the scheduler does.
+// Pods that have finished are not counted, and containers without requests or limits count as zero.
+func nodePodResources(podsByNamespace map[string][]byte) ([]byte, []string) {
+	var errorArray []string
 
+	namespaces := []string{}
+	for namespace := range podsByNamespace {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	resourcesByNode := map[string]*NodePodResources{}
+	nodeNames := []string{}
 	for _, namespace := range namespaces {
-		services, err := client.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
+		var pods []corev1.Pod
+		if err := json.Unmarshal(podsByNamespace[namespace], &pods); err != nil {
+			errorArray = append(errorArray, err.Error())
 			continue
 		}
 
-		b, err := json.MarshalIndent(services.Items, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
+		for _, pod := range pods {
+			if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+
+			nodeResources, ok := resourcesByNode[pod.Spec.NodeName]
+			if !ok {
+				nodeResources = &NodePodResources{
+					NodeName: pod.Spec.NodeName,
+					Requests: corev1.ResourceList{},
+					Limits:   corev1.ResourceList{},
+				}
+				resourcesByNode[pod.Spec.NodeName] = nodeResources
+				nodeNames = append(nodeNames, pod.Spec.NodeName)
+			}
+
+			nodeResources.PodCount++
+			addResourceList(nodeResources.Requests, podResources(pod, func(r corev1.ResourceRequirements) corev1.ResourceList { return r.Requests }))
+			addResourceList(nodeResources.Limits, podResources(pod, func(r corev1.ResourceRequirements) corev1.ResourceList { return r.Limits }))
 		}
+	}
+
+	sort.Strings(nodeNames)
+	nodeResourcesList := []NodePodResources{}
+	for _, nodeName := range nodeNames {
+		nodeResourcesList = append(nodeResourcesList, *resourcesByNode[nodeName])
+	}
 
-		servicesByNamespace[namespace+".json"] = b
+	b, err := json.MarshalIndent(nodeResourcesList, "", "  ")
+	if err != nil {
+		errorArray = append(errorArray, err.Error())
 	}
 
-	return servicesByNamespace, errorsByNamespace
+	return b, errorArray
 }
 
-func deployments(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	deploymentsByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
+// podResources is the sum of the containers' resources, or the largest init container's if that is higher, plus the
+// pod overhead
+func podResources(pod corev1.Pod, resources func(corev1.ResourceRequirements) corev1.ResourceList) corev1.ResourceList {
+	total := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResourceList(total, resources(container.Resources))
+	}
 
-	for _, namespace := range namespaces {
-		deployments, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range resources(container.Resources) {
+			if current, ok := total[name]; !ok || quantity.Cmp(current) > 0 {
+				total[name] = quantity.DeepCopy()
+			}
 		}
+	}
 
-		b, err := json.MarshalIndent(deployments.Items, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
+	addResourceList(total, pod.Spec.Overhead)
+
+	return total
+}
 
-		deploymentsByNamespace[namespace+".json"] = b
+func addResourceList(total corev1.ResourceList, add corev1.ResourceList) {
+	for name, quantity := range add {
+		if current, ok := total[name]; ok {
+			current.Add(quantity)
+			total[name] = current
+		} else {
+			total[name] = quantity.DeepCopy()
+		}
 	}
+}
 
-	return deploymentsByNamespace, errorsByNamespace
+func services(ctx context.Context, client *kubernetes.Clientset, namespaces []string, workers int) (map[string][]byte, map[string]string) {
+	return listByNamespace(namespaces, workers, func(namespace string) (interface{}, error) {
+		services, err := client.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return services.Items, nil
+	})
 }
 
-func statefulsets(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	statefulsetsByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
+func deployments(ctx context.Context, client *kubernetes.Clientset, namespaces []string, workers int) (map[string][]byte, map[string]string) {
+	return listByNamespace(namespaces, workers, func(namespace string) (interface{}, error) {
+		deployments, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return deployments.Items, nil
+	})
+}
 
-	for _, namespace := range namespaces {
+func statefulsets(ctx context.Context, client *kubernetes.Clientset, namespaces []string, workers int) (map[string][]byte, map[string]string) {
+	return listByNamespace(namespaces, workers, func(namespace string) (interface{}, error) {
 		statefulsets, err := client.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
 		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
+			return nil, err
 		}
+		return statefulsets.Items, nil
+	})
+}
 
-		b, err := json.MarshalIndent(statefulsets.Items, "", "  ")
+func daemonsets(ctx context.Context, client *kubernetes.Clientset, namespaces []string, workers int) (map[string][]byte, map[string]string) {
+	return listByNamespace(namespaces, workers, func(namespace string) (interface{}, error) {
+		daemonsets, err := client.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
 		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
+			return nil, err
 		}
-
-		statefulsetsByNamespace[namespace+".json"] = b
-	}
-
-	return statefulsetsByNamespace, errorsByNamespace
+		return daemonsets.Items, nil
+	})
 }
 
-func ingress(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	ingressByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
+func ingress(ctx context.Context, client *kubernetes.Clientset, namespaces []string, workers int) (map[string][]byte, map[string]string) {
+	return listByNamespace(namespaces, workers, func(namespace string) (interface{}, error) {
 		ingress, err := client.ExtensionsV1beta1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
 		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
+			return nil, err
 		}
+		return ingress.Items, nil
+	})
+}
 
-		b, err := json.MarshalIndent(ingress.Items, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
+// allIngresses merges the ingresses listed in each namespace into a single list, so that analyzers can compare
+// ingresses across namespaces. Namespaces that could not be listed are already in ingress-errors.json.
+func allIngresses(ingressByNamespace map[string][]byte) ([]byte, error) {
+	filenames := []string{}
+	for filename := range ingressByNamespace {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
 
-		ingressByNamespace[namespace+".json"] = b
+	ingresses := []json.RawMessage{}
+	for _, filename := range filenames {
+		items := []json.RawMessage{}
+		if err := json.Unmarshal(ingressByNamespace[filename], &items); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal ingress in %s", filename)
+		}
+		ingresses = append(ingresses, items...)
 	}
 
-	return ingressByNamespace, errorsByNamespace
+	b, err := json.MarshalIndent(ingresses, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal ingress")
+	}
+	return b, nil
 }
 
 func storageClasses(ctx context.Context, client *kubernetes.Clientset) ([]byte, []string) {
@@ -333,6 +526,20 @@ func storageClasses(ctx context.Context, client *kubernetes.Clientset) ([]byte,
 	return b, nil
 }
 
+func pvs(ctx context.Context, client *kubernetes.Clientset) ([]byte, []string) {
+	pvs, err := client.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+
+	b, err := json.MarshalIndent(pvs.Items, "", "  ")
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+
+	return b, nil
+}
+
 func crds(ctx context.Context, client *apiextensionsv1beta1clientset.ApiextensionsV1beta1Client) ([]byte, []string) {
 	crds, err := client.CustomResourceDefinitions().List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -399,27 +606,14 @@ func imagePullSecrets(ctx context.Context, client *kubernetes.Clientset, namespa
 	return imagePullSecrets, errors
 }
 
-func limitRanges(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	limitRangesByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
+func limitRanges(ctx context.Context, client *kubernetes.Clientset, namespaces []string, workers int) (map[string][]byte, map[string]string) {
+	return listByNamespace(namespaces, workers, func(namespace string) (interface{}, error) {
 		limitRanges, err := client.CoreV1().LimitRanges(namespace).List(ctx, metav1.ListOptions{})
 		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		b, err := json.MarshalIndent(limitRanges.Items, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
+			return nil, err
 		}
-
-		limitRangesByNamespace[namespace+".json"] = b
-	}
-
-	return limitRangesByNamespace, errorsByNamespace
+		return limitRanges.Items, nil
+	})
 }
 
 func nodes(ctx context.Context, client *kubernetes.Clientset) ([]byte, []string) {
@@ -436,6 +630,31 @@ func nodes(ctx context.Context, client *kubernetes.Clientset) ([]byte, []string)
 	return b, nil
 }
 
+// nodeMetrics gets the current usage of each node from the metrics API, similar to 'kubectl top nodes'. Clusters
+// without metrics-server only report an error.
+func nodeMetrics(ctx context.Context, client *kubernetes.Clientset) ([]byte, []string) {
+	raw, err := client.Discovery().RESTClient().Get().AbsPath("/apis/metrics.k8s.io/v1beta1/nodes").Do(ctx).Raw()
+	if kuberneteserrors.IsNotFound(err) {
+		return nil, []string{fmt.Sprintf("metrics API is not available, metrics-server may not be installed: %s", err.Error())}
+	} else if err != nil {
+		return nil, []string{err.Error()}
+	}
+
+	metrics := struct {
+		Items []json.RawMessage `json:"items"`
+	}{}
+	if err := json.Unmarshal(raw, &metrics); err != nil {
+		return nil, []string{err.Error()}
+	}
+
+	b, err := json.MarshalIndent(metrics.Items, "", "  ")
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+
+	return b, nil
+}
+
 // get the list of API resources, similar to 'kubectl api-resources'
 func apiResources(ctx context.Context, client *kubernetes.Clientset) ([]byte, []byte, []string) {
 	var errorArray []string
@@ -457,13 +676,10 @@ func apiResources(ctx context.Context, client *kubernetes.Clientset) ([]byte, []
 	return groupBytes, resourcesBytes, errorArray
 }
 
-func authCanI(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
+func authCanI(ctx context.Context, client *kubernetes.Clientset, namespaces []string, workers int) (map[string][]byte, map[string]string) {
 	// https://github.com/kubernetes/kubernetes/blob/master/pkg/kubectl/cmd/auth/cani.go
 
-	authListByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
+	return listByNamespace(namespaces, workers, func(namespace string) (interface{}, error) {
 		sar := &authorizationv1.SelfSubjectRulesReview{
 			Spec: authorizationv1.SelfSubjectRulesReviewSpec{
 				Namespace: namespace,
@@ -471,44 +687,20 @@ func authCanI(ctx context.Context, client *kubernetes.Clientset, namespaces []st
 		}
 		response, err := client.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, sar, metav1.CreateOptions{})
 		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		rules := convertToPolicyRule(response.Status)
-		b, err := json.MarshalIndent(rules, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
+			return nil, err
 		}
-
-		authListByNamespace[namespace+".json"] = b
-	}
-
-	return authListByNamespace, errorsByNamespace
+		return convertToPolicyRule(response.Status), nil
+	})
 }
 
-func events(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	eventsByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
+func events(ctx context.Context, client *kubernetes.Clientset, namespaces []string, workers int) (map[string][]byte, map[string]string) {
+	return listByNamespace(namespaces, workers, func(namespace string) (interface{}, error) {
 		events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
 		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		b, err := json.MarshalIndent(events.Items, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
+			return nil, err
 		}
-
-		eventsByNamespace[namespace+".json"] = b
-	}
-
-	return eventsByNamespace, errorsByNamespace
+		return events.Items, nil
+	})
 }
 
 // not exprted from: https://github.com/kubernetes/kubernetes/blob/master/pkg/kubectl/cmd/auth/cani.go#L339
@@ -0,0 +1,218 @@
+package collect
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_nodePodResources(t *testing.T) {
+	scopetest := scopeagent.StartTest(t)
+	defer scopetest.End()
+	req := require.New(t)
+
+	container := func(cpuRequest string, memoryLimit string) corev1.Container {
+		c := corev1.Container{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{},
+				Limits:   corev1.ResourceList{},
+			},
+		}
+		if cpuRequest != "" {
+			c.Resources.Requests[corev1.ResourceCPU] = resource.MustParse(cpuRequest)
+		}
+		if memoryLimit != "" {
+			c.Resources.Limits[corev1.ResourceMemory] = resource.MustParse(memoryLimit)
+		}
+		return c
+	}
+
+	defaultPods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web"},
+			Spec: corev1.PodSpec{
+				NodeName:   "node1",
+				Containers: []corev1.Container{container("500m", "1Gi"), container("250m", "")},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "migrate"},
+			Spec: corev1.PodSpec{
+				NodeName:       "node2",
+				InitContainers: []corev1.Container{container("2", "")},
+				Containers:     []corev1.Container{container("100m", "512Mi")},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "no-requests"},
+			Spec: corev1.PodSpec{
+				NodeName:   "node2",
+				Containers: []corev1.Container{container("", "")},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "pending"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{container("1", "")},
+			},
+		},
+	}
+	systemPods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "proxy"},
+			Spec: corev1.PodSpec{
+				NodeName:   "node1",
+				Containers: []corev1.Container{container("100m", "")},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "completed"},
+			Spec: corev1.PodSpec{
+				NodeName:   "node1",
+				Containers: []corev1.Container{container("4", "")},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodSucceeded,
+			},
+		},
+	}
+
+	podsByNamespace := map[string][]byte{}
+	for namespace, pods := range map[string][]corev1.Pod{"default.json": defaultPods, "kube-system.json": systemPods} {
+		b, err := json.Marshal(pods)
+		req.NoError(err)
+		podsByNamespace[namespace] = b
+	}
+
+	b, errs := nodePodResources(podsByNamespace)
+	req.Empty(errs)
+
+	var actual []NodePodResources
+	req.NoError(json.Unmarshal(b, &actual))
+	req.Len(actual, 2)
+
+	req.Equal("node1", actual[0].NodeName)
+	req.Equal(2, actual[0].PodCount)
+	req.Equal("850m", actual[0].Requests.Cpu().String())
+	req.Equal("1Gi", actual[0].Limits.Memory().String())
+
+	req.Equal("node2", actual[1].NodeName)
+	req.Equal(2, actual[1].PodCount)
+	req.Equal("2", actual[1].Requests.Cpu().String())
+	req.Equal("512Mi", actual[1].Limits.Memory().String())
+}
+
+func Test_isNamespaceExcluded(t *testing.T) {
+	excludeNamespaces := []string{"vault", "cert-manager*", "team-?-secrets"}
+
+	tests := []struct {
+		namespace string
+		expected  bool
+	}{
+		{namespace: "vault", expected: true},
+		{namespace: "vault-agent", expected: false},
+		{namespace: "cert-manager", expected: true},
+		{namespace: "cert-manager-webhook", expected: true},
+		{namespace: "team-a-secrets", expected: true},
+		{namespace: "default", expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.namespace, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+
+			require.Equal(t, test.expected, isNamespaceExcluded(test.namespace, excludeNamespaces))
+		})
+	}
+}
+
+func Test_listByNamespace(t *testing.T) {
+	scopetest := scopeagent.StartTest(t)
+	defer scopetest.End()
+	req := require.New(t)
+
+	namespaces := []string{}
+	for i := 0; i < 25; i++ {
+		namespaces = append(namespaces, fmt.Sprintf("namespace-%d", i))
+	}
+
+	list := func(namespace string) (interface{}, error) {
+		if namespace == "namespace-7" {
+			return nil, errors.New("forbidden")
+		}
+		return []string{namespace}, nil
+	}
+
+	for _, workers := range []int{0, 1, 4, 100} {
+		results, errs := listByNamespace(namespaces, workers, list)
+
+		req.Equal(map[string]string{"namespace-7": "forbidden"}, errs)
+		req.Len(results, 24)
+		for _, namespace := range namespaces {
+			if namespace == "namespace-7" {
+				continue
+			}
+			req.Equal(fmt.Sprintf("[\n  %q\n]", namespace), string(results[namespace+".json"]))
+		}
+	}
+}
+
+func Benchmark_listByNamespace(b *testing.B) {
+	namespaces := []string{}
+	for i := 0; i < 200; i++ {
+		namespaces = append(namespaces, fmt.Sprintf("namespace-%d", i))
+	}
+
+	// simulate the latency of a list request
+	list := func(namespace string) (interface{}, error) {
+		time.Sleep(time.Millisecond)
+		return []corev1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: namespace}}}, nil
+	}
+
+	for _, workers := range []int{1, defaultNamespaceWorkers} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				listByNamespace(namespaces, workers, list)
+			}
+		})
+	}
+}
+
+func Test_allIngresses(t *testing.T) {
+	scopetest := scopeagent.StartTest(t)
+	defer scopetest.End()
+	req := require.New(t)
+
+	actual, err := allIngresses(map[string][]byte{
+		"staging.json": []byte(`[{"metadata": {"name": "web", "namespace": "staging"}}]`),
+		"default.json": []byte(`[{"metadata": {"name": "web", "namespace": "default"}}, {"metadata": {"name": "api", "namespace": "default"}}]`),
+		"empty.json":   []byte(`[]`),
+	})
+	req.NoError(err)
+
+	ingresses := []struct {
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+	}{}
+	req.NoError(json.Unmarshal(actual, &ingresses))
+	req.Len(ingresses, 3)
+	req.Equal("default", ingresses[0].Metadata.Namespace)
+	req.Equal("web", ingresses[0].Metadata.Name)
+	req.Equal("api", ingresses[1].Metadata.Name)
+	req.Equal("staging", ingresses[2].Metadata.Namespace)
+
+	actual, err = allIngresses(map[string][]byte{})
+	req.NoError(err)
+	req.Equal("[]", string(actual))
+}
@@ -152,6 +152,11 @@ func createDaemonSet(ctx context.Context, client *kubernetes.Clientset, rrdColle
 		return "", errors.Wrap(err, "failed to create daemonset")
 	}
 
+	return createdDS.Name, waitForDaemonSet(ctx, client, namespace, createdDS.Name)
+}
+
+// waitForDaemonSet waits for every scheduled pod of the daemonset to be ready
+func waitForDaemonSet(ctx context.Context, client *kubernetes.Clientset, namespace string, name string) error {
 	// This timeout is different from collector timeout.
 	// Time it takes to pull images should not count towards collector timeout.
 	childCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
@@ -160,15 +165,15 @@ func createDaemonSet(ctx context.Context, client *kubernetes.Clientset, rrdColle
 		select {
 		case <-time.After(1 * time.Second):
 		case <-childCtx.Done():
-			return createdDS.Name, errors.Wrap(ctx.Err(), "failed to wait for daemonset")
+			return errors.Wrap(ctx.Err(), "failed to wait for daemonset")
 		}
 
-		ds, err := client.AppsV1().DaemonSets(namespace).Get(ctx, createdDS.Name, metav1.GetOptions{})
+		ds, err := client.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
 		if err != nil {
 			if !kuberneteserrors.IsNotFound(err) {
 				continue
 			}
-			return createdDS.Name, errors.Wrap(err, "failed to get daemonset")
+			return errors.Wrap(err, "failed to get daemonset")
 		}
 
 		if ds.Status.DesiredNumberScheduled != ds.Status.NumberReady {
@@ -178,7 +183,7 @@ func createDaemonSet(ctx context.Context, client *kubernetes.Clientset, rrdColle
 		break
 	}
 
-	return createdDS.Name, nil
+	return nil
 }
 
 func collectRRDFiles(ctx context.Context, client *kubernetes.Clientset, c *Collector, rrdCollector *troubleshootv1beta2.Collectd, label string, namespace string) (map[string][]byte, error) {
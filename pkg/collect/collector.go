@@ -15,12 +15,13 @@ import (
 )
 
 type Collector struct {
-	Collect      *troubleshootv1beta2.Collect
-	Redact       bool
-	RBACErrors   []error
-	ClientConfig *rest.Config
-	Namespace    string
-	PathPrefix   string
+	Collect       *troubleshootv1beta2.Collect
+	Redact        bool
+	RedactOptions RedactOptions
+	RBACErrors    []error
+	ClientConfig  *rest.Config
+	Namespace     string
+	PathPrefix    string
 }
 
 type Collectors []*Collector
@@ -157,6 +158,54 @@ func (c *Collector) IsExcluded() bool {
 		if isExcludedResult {
 			return true
 		}
+	} else if c.Collect.Sysctl != nil {
+		isExcludedResult, err := isExcluded(c.Collect.Sysctl.Exclude)
+		if err != nil {
+			return true
+		}
+		if isExcludedResult {
+			return true
+		}
+	} else if c.Collect.Events != nil {
+		isExcludedResult, err := isExcluded(c.Collect.Events.Exclude)
+		if err != nil {
+			return true
+		}
+		if isExcludedResult {
+			return true
+		}
+	} else if c.Collect.KubeletConfig != nil {
+		isExcludedResult, err := isExcluded(c.Collect.KubeletConfig.Exclude)
+		if err != nil {
+			return true
+		}
+		if isExcludedResult {
+			return true
+		}
+	} else if c.Collect.Certificates != nil {
+		isExcludedResult, err := isExcluded(c.Collect.Certificates.Exclude)
+		if err != nil {
+			return true
+		}
+		if isExcludedResult {
+			return true
+		}
+	} else if c.Collect.APIServerHealth != nil {
+		isExcludedResult, err := isExcluded(c.Collect.APIServerHealth.Exclude)
+		if err != nil {
+			return true
+		}
+		if isExcludedResult {
+			return true
+		}
+	} else if c.Collect.NodeCgroups != nil {
+		isExcludedResult, err := isExcluded(c.Collect.NodeCgroups.Exclude)
+		if err != nil {
+			return true
+		}
+		if isExcludedResult {
+			return true
+		}
 	}
 	return false
 }
@@ -189,7 +238,7 @@ func (c *Collector) RunCollectorSync(globalRedactors []*troubleshootv1beta2.Reda
 	} else if c.Collect.Copy != nil {
 		result, err = Copy(c, c.Collect.Copy)
 	} else if c.Collect.HTTP != nil {
-		result, err = HTTP(c, c.Collect.HTTP)
+		result, err = HTTP(c, c.Collect.HTTP, globalRedactors)
 	} else if c.Collect.Postgres != nil {
 		result, err = Postgres(c, c.Collect.Postgres)
 	} else if c.Collect.Mysql != nil {
@@ -201,6 +250,18 @@ func (c *Collector) RunCollectorSync(globalRedactors []*troubleshootv1beta2.Reda
 		result, err = Collectd(c, c.Collect.Collectd)
 	} else if c.Collect.Ceph != nil {
 		result, err = Ceph(c, c.Collect.Ceph)
+	} else if c.Collect.Sysctl != nil {
+		result, err = Sysctl(c, c.Collect.Sysctl)
+	} else if c.Collect.Events != nil {
+		result, err = Events(c, c.Collect.Events)
+	} else if c.Collect.KubeletConfig != nil {
+		result, err = KubeletConfig(c, c.Collect.KubeletConfig)
+	} else if c.Collect.Certificates != nil {
+		result, err = Certificates(c, c.Collect.Certificates)
+	} else if c.Collect.APIServerHealth != nil {
+		result, err = APIServerHealth(c, c.Collect.APIServerHealth)
+	} else if c.Collect.NodeCgroups != nil {
+		result, err = NodeCgroups(c, c.Collect.NodeCgroups)
 	} else {
 		err = errors.New("no spec found to run")
 		return
@@ -219,7 +280,7 @@ func (c *Collector) RunCollectorSync(globalRedactors []*troubleshootv1beta2.Reda
 	}
 
 	if c.Redact {
-		result, err = redactMap(result, globalRedactors)
+		result, err = redactMapWithOptions(result, globalRedactors, c.RedactOptions)
 	}
 
 	return
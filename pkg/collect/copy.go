@@ -84,6 +84,11 @@ func copyFiles(ctx context.Context, client *kubernetes.Clientset, c *Collector,
 
 func getFilesFromPod(ctx context.Context, client *kubernetes.Clientset, c *Collector, podName string, containerName string, namespace string, containerPath string) ([]byte, []byte, error) {
 	command := []string{"tar", "-C", filepath.Dir(containerPath), "-cf", "-", filepath.Base(containerPath)}
+	return execPodCommand(client, c, podName, containerName, namespace, command)
+}
+
+// execPodCommand runs command in the container and returns its stdout and stderr
+func execPodCommand(client *kubernetes.Clientset, c *Collector, podName string, containerName string, namespace string, command []string) ([]byte, []byte, error) {
 	req := client.CoreV1().RESTClient().Post().Resource("pods").Name(podName).Namespace(namespace).SubResource("exec")
 	scheme := runtime.NewScheme()
 	if err := corev1.AddToScheme(scheme); err != nil {
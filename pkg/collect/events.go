@@ -0,0 +1,50 @@
+package collect
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Events collects the events of the listed namespaces, or of every namespace when none are listed, into a single
+// cluster-resources/events.json file for the events analyzer
+func Events(c *Collector, eventsCollector *troubleshootv1beta2.Events) (map[string][]byte, error) {
+	ctx := context.Background()
+
+	client, err := kubernetes.NewForConfig(c.ClientConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create client from config")
+	}
+
+	namespaces := eventsCollector.Namespaces
+	if c.Namespace != "" {
+		namespaces = []string{c.Namespace}
+	}
+	if len(namespaces) == 0 {
+		// the empty namespace lists events across all namespaces
+		namespaces = []string{""}
+	}
+
+	events := []corev1.Event{}
+	for _, namespace := range namespaces {
+		eventList, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list events in namespace %q", namespace)
+		}
+		events = append(events, eventList.Items...)
+	}
+
+	b, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal events")
+	}
+
+	return map[string][]byte{
+		"cluster-resources/events.json": b,
+	}, nil
+}
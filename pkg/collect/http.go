@@ -1,15 +1,18 @@
 package collect
 
 import (
+	"bytes"
 	"crypto/tls"
 	"encoding/json"
-	"errors"
 	"io/ioutil"
 	"net/http"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/redact"
 )
 
 type httpResponse struct {
@@ -22,41 +25,87 @@ type httpError struct {
 	Message string `json:"message"`
 }
 
-func HTTP(c *Collector, httpCollector *troubleshootv1beta2.HTTP) (map[string][]byte, error) {
+// sensitiveResponseHeaders are masked in the collected response when redaction is enabled
+var sensitiveResponseHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Set-Cookie",
+}
+
+func HTTP(c *Collector, httpCollector *troubleshootv1beta2.HTTP, globalRedactors []*troubleshootv1beta2.Redact) (map[string][]byte, error) {
+	var timeout time.Duration
+	if httpCollector.Timeout != "" {
+		parsed, err := time.ParseDuration(httpCollector.Timeout)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse timeout")
+		}
+		timeout = parsed
+	}
+
 	var response *http.Response
 	var err error
 
 	if httpCollector.Get != nil {
-		response, err = doGet(httpCollector.Get)
+		response, err = doGet(httpClient(httpCollector.Get.InsecureSkipVerify, timeout), httpCollector.Get)
 	} else if httpCollector.Post != nil {
-		response, err = doPost(httpCollector.Post)
+		response, err = doPost(httpClient(httpCollector.Post.InsecureSkipVerify, timeout), httpCollector.Post)
 	} else if httpCollector.Put != nil {
-		response, err = doPut(httpCollector.Put)
+		response, err = doPut(httpClient(httpCollector.Put.InsecureSkipVerify, timeout), httpCollector.Put)
 	} else {
 		return nil, errors.New("no supported http request type")
 	}
 
-	output, err := responseToOutput(response, err, c.Redact)
-	if err != nil {
-		return nil, err
-	}
-
 	fileName := "result.json"
 	if httpCollector.CollectorName != "" {
 		fileName = httpCollector.CollectorName + ".json"
 	}
+	filePath := filepath.Join(httpCollector.Name, fileName)
+
+	// the body is stored as an escaped JSON string, which most redactors would not match, so it is redacted on its
+	// own before it is added to the output
+	var redactBody func([]byte) ([]byte, error)
+	if c.Redact {
+		options := c.RedactOptions
+		options.RedactPaths = false
+		redactBody = func(body []byte) ([]byte, error) {
+			redacted, err := redactMapWithOptions(map[string][]byte{filePath: body}, globalRedactors, options)
+			if err != nil {
+				return nil, err
+			}
+			// redaction is line based and ends every line with a newline
+			if !bytes.HasSuffix(body, []byte("\n")) {
+				return bytes.TrimSuffix(redacted[filePath], []byte("\n")), nil
+			}
+			return redacted[filePath], nil
+		}
+	}
+
+	output, err := responseToOutput(response, err, redactBody)
+	if err != nil {
+		return nil, err
+	}
+
 	httpOutput := map[string][]byte{
-		filepath.Join(httpCollector.Name, fileName): output,
+		filePath: output,
 	}
 
 	return httpOutput, nil
 }
 
-func doGet(get *troubleshootv1beta2.Get) (*http.Response, error) {
-	http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{
-		InsecureSkipVerify: get.InsecureSkipVerify,
+// httpClient returns a client with its own transport, so that the TLS settings of one collector do not affect others
+func httpClient(insecureSkipVerify bool, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: insecureSkipVerify,
+			},
+		},
 	}
+}
 
+func doGet(client *http.Client, get *troubleshootv1beta2.Get) (*http.Response, error) {
 	req, err := http.NewRequest("GET", get.URL, nil)
 	if err != nil {
 		return nil, err
@@ -66,14 +115,10 @@ func doGet(get *troubleshootv1beta2.Get) (*http.Response, error) {
 		req.Header.Set(k, v)
 	}
 
-	return http.DefaultClient.Do(req)
+	return client.Do(req)
 }
 
-func doPost(post *troubleshootv1beta2.Post) (*http.Response, error) {
-	http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{
-		InsecureSkipVerify: post.InsecureSkipVerify,
-	}
-
+func doPost(client *http.Client, post *troubleshootv1beta2.Post) (*http.Response, error) {
 	req, err := http.NewRequest("POST", post.URL, strings.NewReader(post.Body))
 	if err != nil {
 		return nil, err
@@ -83,14 +128,10 @@ func doPost(post *troubleshootv1beta2.Post) (*http.Response, error) {
 		req.Header.Set(k, v)
 	}
 
-	return http.DefaultClient.Do(req)
+	return client.Do(req)
 }
 
-func doPut(put *troubleshootv1beta2.Put) (*http.Response, error) {
-	http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{
-		InsecureSkipVerify: put.InsecureSkipVerify,
-	}
-
+func doPut(client *http.Client, put *troubleshootv1beta2.Put) (*http.Response, error) {
 	req, err := http.NewRequest("PUT", put.URL, strings.NewReader(put.Body))
 	if err != nil {
 		return nil, err
@@ -100,16 +141,20 @@ func doPut(put *troubleshootv1beta2.Put) (*http.Response, error) {
 		req.Header.Set(k, v)
 	}
 
-	return http.DefaultClient.Do(req)
+	return client.Do(req)
 }
 
-func responseToOutput(response *http.Response, err error, doRedact bool) ([]byte, error) {
+// responseToOutput marshals the response, or the request error. When redactBody is set, the body and the sensitive
+// headers are redacted.
+func responseToOutput(response *http.Response, err error, redactBody func([]byte) ([]byte, error)) ([]byte, error) {
 	output := make(map[string]interface{})
 	if err != nil {
 		output["error"] = httpError{
 			Message: err.Error(),
 		}
 	} else {
+		defer response.Body.Close()
+
 		body, err := ioutil.ReadAll(response.Body)
 		if err != nil {
 			return nil, err
@@ -120,6 +165,18 @@ func responseToOutput(response *http.Response, err error, doRedact bool) ([]byte
 			headers[k] = strings.Join(v, ",")
 		}
 
+		if redactBody != nil {
+			body, err = redactBody(body)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to redact response body")
+			}
+			for _, name := range sensitiveResponseHeaders {
+				if _, ok := headers[name]; ok {
+					headers[name] = redact.MASK_TEXT
+				}
+			}
+		}
+
 		output["response"] = httpResponse{
 			Status:  response.StatusCode,
 			Body:    string(body),
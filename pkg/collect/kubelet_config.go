@@ -0,0 +1,47 @@
+package collect
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// KubeletConfig collects the running configuration of the kubelet on every node from its configz endpoint, through
+// the API server's node proxy, into kubelet-config/<node>.json. Nodes whose configz cannot be reached are listed in
+// kubelet-config/errors.json rather than failing the collector.
+func KubeletConfig(c *Collector, kubeletConfigCollector *troubleshootv1beta2.KubeletConfig) (map[string][]byte, error) {
+	ctx := context.Background()
+
+	client, err := kubernetes.NewForConfig(c.ClientConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create client from config")
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+
+	kubeletConfigOutput := map[string][]byte{}
+	var kubeletConfigErrors []string
+	for _, node := range nodes.Items {
+		raw, err := client.CoreV1().RESTClient().Get().Resource("nodes").Name(node.Name).SubResource("proxy").Suffix("configz").Do(ctx).Raw()
+		if err != nil {
+			kubeletConfigErrors = append(kubeletConfigErrors, fmt.Sprintf("failed to get configz of node %s: %s", node.Name, err.Error()))
+			continue
+		}
+		kubeletConfigOutput[filepath.Join("kubelet-config", fmt.Sprintf("%s.json", node.Name))] = raw
+	}
+
+	kubeletConfigOutput[filepath.Join("kubelet-config", "errors.json")], err = marshalNonNil(kubeletConfigErrors)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal errors")
+	}
+
+	return kubeletConfigOutput, nil
+}
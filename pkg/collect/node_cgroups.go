@@ -0,0 +1,233 @@
+package collect
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/logger"
+	"github.com/segmentio/ksuid"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+const defaultNodeCgroupsImage = "busybox:1"
+
+// nodeCgroupsMountPath is where the host's /sys/fs/cgroup is mounted in the collector pods
+const nodeCgroupsMountPath = "/host/sys/fs/cgroup"
+
+// NodeCgroupsInfo is the cgroup hierarchy detected on a node
+type NodeCgroupsInfo struct {
+	// Filesystem is the type of the filesystem mounted at /sys/fs/cgroup, e.g. cgroup2fs or tmpfs
+	Filesystem string `json:"filesystem"`
+	// Version is v1 or v2, or empty when the filesystem type is not recognized
+	Version string `json:"version"`
+}
+
+// NodeCgroups collects the cgroup version of every node into node-cgroups/<node>.json. The version is detected from
+// the type of the filesystem mounted at /sys/fs/cgroup, which is cgroup2fs on nodes with the unified v2 hierarchy.
+func NodeCgroups(c *Collector, nodeCgroupsCollector *troubleshootv1beta2.NodeCgroups) (map[string][]byte, error) {
+	ctx := context.Background()
+	label := ksuid.New().String()
+	namespace := nodeCgroupsCollector.Namespace
+
+	client, err := kubernetes.NewForConfig(c.ClientConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create client from config")
+	}
+
+	dsName, err := createNodeCgroupsDaemonSet(ctx, client, nodeCgroupsCollector, namespace, label)
+	if dsName != "" {
+		defer func() {
+			if err := client.AppsV1().DaemonSets(namespace).Delete(ctx, dsName, metav1.DeleteOptions{}); err != nil {
+				logger.Printf("Failed to delete daemonset %s: %v\n", dsName, err)
+			}
+		}()
+
+		if nodeCgroupsCollector.ImagePullSecret != nil && nodeCgroupsCollector.ImagePullSecret.Data != nil {
+			defer func() {
+				err := client.CoreV1().Secrets(namespace).Delete(ctx, nodeCgroupsCollector.ImagePullSecret.Name, metav1.DeleteOptions{})
+				if err != nil && !kuberneteserrors.IsNotFound(err) {
+					logger.Printf("Failed to delete secret %s: %v\n", nodeCgroupsCollector.ImagePullSecret.Name, err)
+				}
+			}()
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create daemonset")
+	}
+
+	if nodeCgroupsCollector.Timeout == "" {
+		return collectNodeCgroups(ctx, client, c, label, namespace)
+	}
+
+	timeout, err := time.ParseDuration(nodeCgroupsCollector.Timeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse timeout")
+	}
+
+	childCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	resultCh := make(chan map[string][]byte, 1)
+	go func() {
+		b, err := collectNodeCgroups(childCtx, client, c, label, namespace)
+		if err != nil {
+			errCh <- err
+		} else {
+			resultCh <- b
+		}
+	}()
+
+	select {
+	case <-time.After(timeout):
+		return nil, errors.New("timeout")
+	case result := <-resultCh:
+		return result, nil
+	case err := <-errCh:
+		return nil, err
+	}
+}
+
+func createNodeCgroupsDaemonSet(ctx context.Context, client *kubernetes.Clientset, nodeCgroupsCollector *troubleshootv1beta2.NodeCgroups, namespace string, label string) (string, error) {
+	image := defaultNodeCgroupsImage
+	if nodeCgroupsCollector.Image != "" {
+		image = nodeCgroupsCollector.Image
+	}
+	pullPolicy := corev1.PullIfNotPresent
+	if nodeCgroupsCollector.ImagePullPolicy != "" {
+		pullPolicy = corev1.PullPolicy(nodeCgroupsCollector.ImagePullPolicy)
+	}
+	dsLabels := map[string]string{
+		"node-cgroups-collector": label,
+	}
+
+	ds := appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "troubleshoot",
+			Namespace:    namespace,
+			Labels:       dsLabels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: dsLabels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: dsLabels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyAlways,
+					Tolerations: []corev1.Toleration{
+						{
+							Operator: corev1.TolerationOpExists,
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Image:           image,
+							ImagePullPolicy: pullPolicy,
+							Name:            "collector",
+							Command:         []string{"sleep"},
+							Args:            []string{"1000000"},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "cgroup",
+									MountPath: nodeCgroupsMountPath,
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "cgroup",
+							VolumeSource: corev1.VolumeSource{
+								HostPath: &corev1.HostPathVolumeSource{
+									Path: "/sys/fs/cgroup",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if nodeCgroupsCollector.ImagePullSecret != nil && nodeCgroupsCollector.ImagePullSecret.Name != "" {
+		err := createSecret(ctx, client, namespace, nodeCgroupsCollector.ImagePullSecret)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to create secret")
+		}
+		ds.Spec.Template.Spec.ImagePullSecrets = append(ds.Spec.Template.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: nodeCgroupsCollector.ImagePullSecret.Name})
+	}
+
+	createdDS, err := client.AppsV1().DaemonSets(namespace).Create(ctx, &ds, metav1.CreateOptions{})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create daemonset")
+	}
+
+	return createdDS.Name, waitForDaemonSet(ctx, client, namespace, createdDS.Name)
+}
+
+func collectNodeCgroups(ctx context.Context, client *kubernetes.Clientset, c *Collector, label string, namespace string) (map[string][]byte, error) {
+	labelSelector := map[string]string{
+		"node-cgroups-collector": label,
+	}
+	opts := metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labelSelector).String(),
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "list node cgroups collector pods")
+	}
+
+	runOutput := map[string][]byte{}
+	for _, pod := range pods.Items {
+		nodePath := path.Join("node-cgroups", pod.Spec.NodeName)
+
+		stdout, stderr, err := execPodCommand(client, c, pod.Name, "", namespace, []string{"stat", "-f", "-c", "%T", nodeCgroupsMountPath})
+		if err != nil {
+			runOutput[nodePath+".error"] = []byte(err.Error())
+			if len(stderr) > 0 {
+				runOutput[nodePath+".stderr"] = stderr
+			}
+			continue
+		}
+
+		b, err := json.MarshalIndent(parseNodeCgroupsFilesystem(stdout), "", "  ")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal node cgroups")
+		}
+		runOutput[nodePath+".json"] = b
+	}
+
+	return runOutput, nil
+}
+
+// parseNodeCgroupsFilesystem detects the cgroup version from the filesystem type printed by stat. The v1
+// hierarchy, including the hybrid layout with a unified subtree, is a tmpfs with a mount per controller.
+func parseNodeCgroupsFilesystem(output []byte) NodeCgroupsInfo {
+	info := NodeCgroupsInfo{
+		Filesystem: strings.TrimSpace(string(output)),
+	}
+
+	switch info.Filesystem {
+	case "cgroup2fs", "cgroup2":
+		info.Version = "v2"
+	case "tmpfs", "cgroupfs", "cgroup":
+		info.Version = "v1"
+	}
+
+	return info
+}
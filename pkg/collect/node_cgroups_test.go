@@ -0,0 +1,41 @@
+package collect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+)
+
+func Test_parseNodeCgroupsFilesystem(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected NodeCgroupsInfo
+	}{
+		{
+			name:     "unified",
+			output:   "cgroup2fs\n",
+			expected: NodeCgroupsInfo{Filesystem: "cgroup2fs", Version: "v2"},
+		},
+		{
+			name:     "legacy",
+			output:   "tmpfs\n",
+			expected: NodeCgroupsInfo{Filesystem: "tmpfs", Version: "v1"},
+		},
+		{
+			name:     "unknown",
+			output:   "UNKNOWN (0x1234)\n",
+			expected: NodeCgroupsInfo{Filesystem: "UNKNOWN (0x1234)"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopetest := scopeagent.StartTest(t)
+			defer scopetest.End()
+			req := require.New(t)
+
+			req.Equal(test.expected, parseNodeCgroupsFilesystem([]byte(test.output)))
+		})
+	}
+}
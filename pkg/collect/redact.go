@@ -2,128 +2,586 @@ package collect
 
 import (
 	"archive/tar"
+	"archive/zip"
+	"bufio"
 	"bytes"
 	"compress/gzip"
-	"encoding/binary"
+	"context"
+	"fmt"
 	"io"
-	"path/filepath"
+	"io/ioutil"
+	"os"
+	"path"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	"github.com/replicatedhq/troubleshoot/pkg/redact"
 )
 
+// defaultMaxArchiveDepth is how many levels of archives inside archives redactMap will unpack
+const defaultMaxArchiveDepth = 5
+
+// builtinRedactors are merged into the additional redactors by redactMap. A redactor with the same name replaces the
+// built-in one, so a redactor with that name and no removals disables it.
+var builtinRedactors = []*troubleshootv1beta2.Redact{
+	{
+		Name: "bearer-tokens",
+		Removals: troubleshootv1beta2.Removals{
+			Regex: []troubleshootv1beta2.Regex{
+				{Redactor: `(?i)(\bBearer\s+)(?P<mask>[A-Za-z0-9\-._~+/]{20,}=*)`},
+				{Redactor: `(?P<mask>\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+)`},
+			},
+		},
+	},
+}
+
+// RedactOptions configure how collected files are redacted. The embedded redact.Options can disable the default
+// redactors or select some of them by name; this also applies to the built-in redactors that redactMap adds, and has
+// the same security implications. Workers is how many files are redacted at once and defaults to the number of CPUs.
+// MaxArchiveDepth is how many levels of archives inside archives are unpacked. RedactPaths also runs the redactors
+// over file paths, including the names of archive entries, and renames the files in the output. TarPaths maps file
+// names to tars on disk that redactMap streams through redaction and replaces with the redacted tar, rather than
+// reading them from the input; the name decides the compression, is the path that file selectors match, and is in
+// the output with a nil value. Progress, if set, is called after each entry of a tar redacted from disk.
+type RedactOptions struct {
+	redact.Options
+	Workers         int
+	MaxArchiveDepth int
+	RedactPaths     bool
+	TarPaths        map[string]string
+	Progress        func(RedactProgress)
+}
+
+// RedactProgress reports how far the redaction of a tar on disk has got. BytesRead counts the bytes of the archive
+// as stored, so it reaches TotalBytes even when the archive is compressed.
+type RedactProgress struct {
+	Filename   string
+	Entry      string
+	Entries    int
+	BytesRead  int64
+	TotalBytes int64
+}
+
 func redactMap(input map[string][]byte, additionalRedactors []*troubleshootv1beta2.Redact) (map[string][]byte, error) {
-	result := make(map[string][]byte)
-	for k, v := range input {
-		if v == nil {
+	return redactMapWithOptions(input, additionalRedactors, RedactOptions{})
+}
+
+// mergeBuiltinRedactors appends the built-in redactors that are not overridden by name, so that the generated names
+// of unnamed additional redactors do not change.
+func mergeBuiltinRedactors(additionalRedactors []*troubleshootv1beta2.Redact, options redact.Options) []*troubleshootv1beta2.Redact {
+	merged := append([]*troubleshootv1beta2.Redact{}, additionalRedactors...)
+	if options.DisableDefaultRedactors {
+		return merged
+	}
+
+	overridden := map[string]bool{}
+	for _, r := range additionalRedactors {
+		if r != nil && r.Name != "" {
+			overridden[r.Name] = true
+		}
+	}
+	selected := map[string]bool{}
+	for _, name := range options.DefaultRedactorNames {
+		selected[name] = true
+	}
+
+	for _, r := range builtinRedactors {
+		if overridden[r.Name] {
 			continue
 		}
-		//If the file is .tar, .tgz or .tar.gz, it must not be redacted. Instead it is decompressed and each file inside the
-		//tar is decompressed, redacted and compressed back into the tar.
-		if filepath.Ext(k) == ".tar" || filepath.Ext(k) == ".tgz" || strings.HasSuffix(k, ".tar.gz") {
-			tarFile := bytes.NewBuffer(v)
-			unRedacted, tarHeaders, err := decompressFile(tarFile, k)
-			if err != nil {
-				return nil, err
-			}
-			redacted, err := redactMap(unRedacted, additionalRedactors)
-			if err != nil {
-				return nil, err
-			}
-			result[k], err = compressFiles(redacted, tarHeaders, k)
-			if err != nil {
-				return nil, err
-			}
-			//Content of the tar file was redacted. Continue to next file.
+		if len(selected) > 0 && !selected[r.Name] {
 			continue
 		}
-		redacted, err := redact.Redact(v, k, additionalRedactors)
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// redactMapWithOptions redacts the files in input using up to options.Workers goroutines. Archives nested deeper than
+// options.MaxArchiveDepth are rejected rather than passed through unredacted. The entries of an archive are streamed
+// one at a time by the worker that picked up the archive, so nested archives never start goroutines of their own.
+// The first error stops any files that have not been started yet.
+func redactMapWithOptions(input map[string][]byte, additionalRedactors []*troubleshootv1beta2.Redact, options RedactOptions) (map[string][]byte, error) {
+	if options.Workers <= 0 {
+		options.Workers = runtime.NumCPU()
+	}
+	if options.MaxArchiveDepth <= 0 {
+		options.MaxArchiveDepth = defaultMaxArchiveDepth
+	}
+	additionalRedactors = mergeBuiltinRedactors(additionalRedactors, options.Options)
+
+	// tars on disk are passed separately, so that no collected contents can make redactMap read or replace a file
+	files := map[string][]byte{}
+	for k, v := range input {
+		if v != nil {
+			files[k] = v
+		}
+	}
+	for k := range options.TarPaths {
+		files[k] = nil
+	}
+
+	outputPaths := map[string]string{}
+	for k := range files {
+		outputPaths[k] = k
+	}
+	if options.RedactPaths {
+		var err error
+		outputPaths, err = redactPaths(files, additionalRedactors, options)
 		if err != nil {
 			return nil, err
 		}
-		result[k] = redacted
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	result := make(map[string][]byte)
+	var resultMut sync.Mutex
+	var firstErr error
+
+	filenames := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < options.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range filenames {
+				var redacted []byte
+				var err error
+				if tarPath, ok := options.TarPaths[k]; ok {
+					err = redactTarPathInPlace(tarPath, k, additionalRedactors, options)
+				} else {
+					buf := new(bytes.Buffer)
+					err = redactFile(bytes.NewReader(input[k]), buf, k, additionalRedactors, 0, options)
+					redacted = buf.Bytes()
+				}
+
+				resultMut.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+				} else {
+					result[outputPaths[k]] = redacted
+				}
+				resultMut.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for k := range files {
+		select {
+		case filenames <- k:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(filenames)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
 	}
 	return result, nil
 }
 
-func compressFiles(tarContent map[string][]byte, tarHeaders map[string]*tar.Header, filename string) ([]byte, error) {
-	buff := new(bytes.Buffer)
-	var tw *tar.Writer
-	var zw *gzip.Writer
-	if filepath.Ext(filename) != ".tar" {
-		zw = gzip.NewWriter(buff)
-		tw = tar.NewWriter(zw)
-		defer zw.Close()
-	} else {
-		tw = tar.NewWriter(buff)
+// RedactTarFile redacts the .tar, .tgz, .tar.gz or .tar.zst archive at srcPath into a new archive at dstPath. Entries
+// are streamed from disk and written as they are redacted, so only one entry is held in memory however large the
+// archive is. dstPath is removed if redaction fails.
+func RedactTarFile(srcPath string, dstPath string, additionalRedactors []*troubleshootv1beta2.Redact, options RedactOptions) error {
+	if options.MaxArchiveDepth <= 0 {
+		options.MaxArchiveDepth = defaultMaxArchiveDepth
 	}
-	defer tw.Close()
-	for p, f := range tarContent {
-		if tarHeaders[p].FileInfo().IsDir() {
-			err := tw.WriteHeader(tarHeaders[p])
-			if err != nil {
-				return nil, err
-			}
-			continue
+	additionalRedactors = mergeBuiltinRedactors(additionalRedactors, options.Options)
+
+	return redactTarPath(srcPath, dstPath, srcPath, additionalRedactors, options)
+}
+
+// RedactTarReaderAt redacts the archive of the given size read from input into output, as RedactTarFile does.
+// filename decides the compression and is the path that file selectors match.
+func RedactTarReaderAt(input io.ReaderAt, size int64, output io.Writer, filename string, additionalRedactors []*troubleshootv1beta2.Redact, options RedactOptions) error {
+	if options.MaxArchiveDepth <= 0 {
+		options.MaxArchiveDepth = defaultMaxArchiveDepth
+	}
+	additionalRedactors = mergeBuiltinRedactors(additionalRedactors, options.Options)
+
+	return redactTarReaderAt(input, size, output, filename, additionalRedactors, options)
+}
+
+// redactTarPathInPlace redacts the tar at tarPath into a file next to it and then replaces the tar with it
+func redactTarPathInPlace(tarPath string, filename string, additionalRedactors []*troubleshootv1beta2.Redact, options RedactOptions) error {
+	redactedPath := tarPath + ".redacted"
+	if err := redactTarPath(tarPath, redactedPath, filename, additionalRedactors, options); err != nil {
+		return err
+	}
+	if err := os.Rename(redactedPath, tarPath); err != nil {
+		os.Remove(redactedPath)
+		return errors.Wrapf(err, "replace %s with redacted archive", tarPath)
+	}
+	return nil
+}
+
+func redactTarPath(srcPath string, dstPath string, filename string, additionalRedactors []*troubleshootv1beta2.Redact, options RedactOptions) error {
+	if !isTarFile(filename) {
+		return errors.Errorf("%s is not a tar archive", filename)
+	}
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return errors.Wrap(err, "open archive")
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return errors.Wrap(err, "stat archive")
+	}
+
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return errors.Wrap(err, "create redacted archive")
+	}
+
+	bufferedDst := bufio.NewWriter(dst)
+	err = redactTarReaderAt(src, info.Size(), bufferedDst, filename, additionalRedactors, options)
+	if err == nil {
+		err = bufferedDst.Flush()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dstPath)
+		return err
+	}
+	return nil
+}
+
+func redactTarReaderAt(input io.ReaderAt, size int64, output io.Writer, filename string, additionalRedactors []*troubleshootv1beta2.Redact, options RedactOptions) error {
+	counted := &countingReader{reader: bufio.NewReader(io.NewSectionReader(input, 0, size))}
+
+	var onEntry func(entry string)
+	if options.Progress != nil {
+		entries := 0
+		onEntry = func(entry string) {
+			entries++
+			options.Progress(RedactProgress{
+				Filename:   filename,
+				Entry:      entry,
+				Entries:    entries,
+				BytesRead:  counted.count,
+				TotalBytes: size,
+			})
 		}
-		//File size must be recalculated in case the redactor added some bytes while redacting.
-		tarHeaders[p].Size = int64(binary.Size(f))
-		err := tw.WriteHeader(tarHeaders[p])
+	}
+
+	err := redactTar(counted, output, filename, additionalRedactors, 0, options, onEntry)
+	if err != nil {
+		return errors.Wrapf(err, "redact archive %s", filename)
+	}
+	return nil
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
+
+// redactPaths maps each path in input to its redacted path. Paths that the redactors leave alone keep their names, and
+// redacted paths that collide with another path get a numbered suffix.
+func redactPaths(input map[string][]byte, additionalRedactors []*troubleshootv1beta2.Redact, options RedactOptions) (map[string]string, error) {
+	paths := []string{}
+	for k := range input {
+		paths = append(paths, k)
+	}
+	sort.Strings(paths)
+
+	redactedPaths := map[string]string{}
+	used := map[string]bool{}
+	for _, p := range paths {
+		redactedPath, err := redactPath(p, additionalRedactors, options)
 		if err != nil {
 			return nil, err
 		}
-		_, err = tw.Write(f)
-		if err != nil {
-			return nil, err
+		redactedPaths[p] = redactedPath
+		if redactedPath == p {
+			used[p] = true
+		}
+	}
+
+	for _, p := range paths {
+		if redactedPaths[p] != p {
+			redactedPaths[p] = uniquePath(redactedPaths[p], used)
 		}
 	}
-	err := tw.Close()
+	return redactedPaths, nil
+}
+
+func redactPath(p string, additionalRedactors []*troubleshootv1beta2.Redact, options RedactOptions) (string, error) {
+	redacted := new(bytes.Buffer)
+	err := redact.RedactStreamWithOptions(strings.NewReader(p), redacted, p, additionalRedactors, options.Options)
 	if err != nil {
-		return nil, err
+		return "", errors.Wrap(err, "redact path")
 	}
-	if filepath.Ext(filename) != ".tar" {
-		err = zw.Close()
-		if err != nil {
-			return nil, err
-		}
+	return strings.TrimSuffix(redacted.String(), "\n"), nil
+}
+
+// uniquePath returns p, or p with a number inserted before its extensions if p is already used, and marks the result
+// as used
+func uniquePath(p string, used map[string]bool) string {
+	unique := p
+	dir, base := path.Split(p)
+	name, ext := base, ""
+	if i := strings.Index(base, "."); i > 0 {
+		name, ext = base[:i], base[i:]
+	}
+	for i := 1; used[unique]; i++ {
+		unique = fmt.Sprintf("%s%s-%d%s", dir, name, i, ext)
+	}
+	used[unique] = true
+	return unique
+}
+
+// redactFile redacts a single file from input into output. If the file is .tar, .tgz, .tar.gz, .tar.zst or .zip, it
+// must not be redacted as a whole. Instead each file inside the archive is redacted and written back into a new
+// archive. depth is the number of archives that contain the file.
+func redactFile(input io.Reader, output io.Writer, filename string, additionalRedactors []*troubleshootv1beta2.Redact, depth int, options RedactOptions) error {
+	if !isTarFile(filename) && !isZipFile(filename) {
+		return redact.RedactStreamWithOptions(input, output, filename, additionalRedactors, options.Options)
 	}
-	return buff.Bytes(), nil
 
+	if depth >= options.MaxArchiveDepth {
+		return errors.Errorf("archive %s is nested more than %d levels deep", filename, options.MaxArchiveDepth)
+	}
+
+	var err error
+	if isTarFile(filename) {
+		err = redactTar(input, output, filename, additionalRedactors, depth, options, nil)
+	} else {
+		err = redactZip(input, output, filename, additionalRedactors, depth, options)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "redact archive %s", filename)
+	}
+	return nil
+}
+
+// archiveEntryPath is the path of a file inside an archive, used to match file selectors and to report redactions
+func archiveEntryPath(archive string, entry string) string {
+	return archive + "/" + entry
+}
+
+func isTarFile(filename string) bool {
+	return strings.HasSuffix(strings.ToLower(filename), ".tar") || isGzippedTarFile(filename) || isZstdTarFile(filename)
+}
+
+func isZstdTarFile(filename string) bool {
+	lower := strings.ToLower(filename)
+	return strings.HasSuffix(lower, ".tzst") || strings.HasSuffix(lower, ".tar.zst")
 }
 
-func decompressFile(tarFile *bytes.Buffer, filename string) (map[string][]byte, map[string]*tar.Header, error) {
+func isZipFile(filename string) bool {
+	return strings.HasSuffix(strings.ToLower(filename), ".zip")
+}
+
+func isGzippedTarFile(filename string) bool {
+	lower := strings.ToLower(filename)
+	return strings.HasSuffix(lower, ".tgz") || strings.HasSuffix(lower, ".tar.gz")
+}
+
+// redactTar streams the tar named filename from input to output one entry at a time, so only a single redacted entry
+// is held in memory. Entries are written in their original order with their original headers, so that modes,
+// ownership, timestamps and type flags are kept. onEntry, if set, is called with the name of each entry once it has
+// been written.
+func redactTar(input io.Reader, output io.Writer, filename string, additionalRedactors []*troubleshootv1beta2.Redact, depth int, options RedactOptions, onEntry func(entry string)) error {
 	var tarReader *tar.Reader
-	var zr *gzip.Reader
-	var err error
-	if filepath.Ext(filename) != ".tar" {
-		zr, err = gzip.NewReader(tarFile)
+	var compressedWriter io.WriteCloser
+	switch {
+	case isGzippedTarFile(filename):
+		zr, err := gzip.NewReader(input)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		tarReader = tar.NewReader(zr)
+
+		compressedWriter, err = gzip.NewWriterLevel(output, gzip.DefaultCompression)
+		if err != nil {
+			return err
+		}
+	case isZstdTarFile(filename):
+		zr, err := zstd.NewReader(input, zstd.WithDecoderConcurrency(1))
 		if err != nil {
-			return nil, nil, err
+			return err
 		}
 		defer zr.Close()
 		tarReader = tar.NewReader(zr)
+
+		compressedWriter, err = zstd.NewWriter(output, zstd.WithEncoderLevel(zstd.SpeedDefault), zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return err
+		}
+	default:
+		tarReader = tar.NewReader(input)
+	}
+
+	var tarWriter *tar.Writer
+	if compressedWriter != nil {
+		// closed explicitly once the tar is written, a second Close could write another zstd frame
+		defer func() {
+			if compressedWriter != nil {
+				compressedWriter.Close()
+			}
+		}()
+		tarWriter = tar.NewWriter(compressedWriter)
 	} else {
-		tarReader = tar.NewReader(tarFile)
+		tarWriter = tar.NewWriter(output)
 	}
-	tarHeaders := make(map[string]*tar.Header)
-	tarContent := make(map[string][]byte)
+	defer tarWriter.Close()
+
+	usedNames := map[string]bool{}
 	for {
 		header, err := tarReader.Next()
 		if err != nil {
 			if err != io.EOF {
-				return nil, nil, err
+				return err
 			}
 			break
 		}
-		file := new(bytes.Buffer)
-		_, err = io.Copy(file, tarReader)
+		entryPath := archiveEntryPath(filename, header.Name)
+		if options.RedactPaths {
+			header.Name, err = redactEntryName(header.Name, header.FileInfo().IsDir(), usedNames, additionalRedactors, options)
+			if err != nil {
+				return err
+			}
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			err = tarWriter.WriteHeader(header)
+			if err != nil {
+				return err
+			}
+			if onEntry != nil {
+				onEntry(header.Name)
+			}
+			continue
+		}
+
+		redacted := new(bytes.Buffer)
+		err = redactFile(tarReader, redacted, entryPath, additionalRedactors, depth+1, options)
+		if err != nil {
+			return err
+		}
+
+		//File size must be recalculated in case the redactor added some bytes while redacting.
+		header.Size = int64(redacted.Len())
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tarWriter, redacted)
+		if err != nil {
+			return err
+		}
+		if onEntry != nil {
+			onEntry(header.Name)
+		}
+	}
+
+	err := tarWriter.Close()
+	if err != nil {
+		return err
+	}
+	if compressedWriter != nil {
+		err = compressedWriter.Close()
+		compressedWriter = nil
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// redactEntryName redacts the name of an archive entry. Entries are renamed as they are streamed, so a renamed file
+// only gets a numbered suffix if an earlier entry already has its name. Directories that redact to the same name are
+// merged.
+func redactEntryName(name string, isDir bool, used map[string]bool, additionalRedactors []*troubleshootv1beta2.Redact, options RedactOptions) (string, error) {
+	redactedName, err := redactPath(name, additionalRedactors, options)
+	if err != nil {
+		return "", err
+	}
+	if isDir {
+		return redactedName, nil
+	}
+	return uniquePath(redactedName, used), nil
+}
+
+// redactZip rewrites the zip archive read from input into output, keeping each entry's order, compression method and,
+// unless paths are redacted, name. zip archives can only be read with random access, so the archive itself is buffered in memory.
+func redactZip(input io.Reader, output io.Writer, filename string, additionalRedactors []*troubleshootv1beta2.Redact, depth int, options RedactOptions) error {
+	archive, err := ioutil.ReadAll(input)
+	if err != nil {
+		return err
+	}
+	zipReader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return err
+	}
+
+	zipWriter := zip.NewWriter(output)
+	defer zipWriter.Close()
+
+	usedNames := map[string]bool{}
+	for _, file := range zipReader.File {
+		header := file.FileHeader
+		if options.RedactPaths {
+			header.Name, err = redactEntryName(file.Name, file.FileInfo().IsDir(), usedNames, additionalRedactors, options)
+			if err != nil {
+				return err
+			}
+		}
+		if file.FileInfo().IsDir() {
+			_, err = zipWriter.CreateHeader(&header)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		contents, err := file.Open()
+		if err != nil {
+			return err
+		}
+		redacted := new(bytes.Buffer)
+		err = redactFile(contents, redacted, archiveEntryPath(filename, file.Name), additionalRedactors, depth+1, options)
+		contents.Close()
 		if err != nil {
-			return nil, nil, err
+			return err
 		}
-		tarContent[header.Name] = file.Bytes()
-		tarHeaders[header.Name] = header
 
+		entry, err := zipWriter.CreateHeader(&header)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(entry, redacted)
+		if err != nil {
+			return err
+		}
 	}
-	return tarContent, tarHeaders, nil
+
+	return zipWriter.Close()
 }
@@ -0,0 +1,217 @@
+package collect
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/logger"
+	"github.com/segmentio/ksuid"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+const defaultSysctlImage = "busybox:1"
+
+// Sysctl collects the kernel parameters of every node into sysctl/<node>.json, a map of parameter name to value.
+// The pods use the host network and IPC namespaces so that namespaced parameters such as net.core.somaxconn
+// report the host's values.
+func Sysctl(c *Collector, sysctlCollector *troubleshootv1beta2.Sysctl) (map[string][]byte, error) {
+	ctx := context.Background()
+	label := ksuid.New().String()
+	namespace := sysctlCollector.Namespace
+
+	client, err := kubernetes.NewForConfig(c.ClientConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create client from config")
+	}
+
+	dsName, err := createSysctlDaemonSet(ctx, client, sysctlCollector, namespace, label)
+	if dsName != "" {
+		defer func() {
+			if err := client.AppsV1().DaemonSets(namespace).Delete(ctx, dsName, metav1.DeleteOptions{}); err != nil {
+				logger.Printf("Failed to delete daemonset %s: %v\n", dsName, err)
+			}
+		}()
+
+		if sysctlCollector.ImagePullSecret != nil && sysctlCollector.ImagePullSecret.Data != nil {
+			defer func() {
+				err := client.CoreV1().Secrets(namespace).Delete(ctx, sysctlCollector.ImagePullSecret.Name, metav1.DeleteOptions{})
+				if err != nil && !kuberneteserrors.IsNotFound(err) {
+					logger.Printf("Failed to delete secret %s: %v\n", sysctlCollector.ImagePullSecret.Name, err)
+				}
+			}()
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create daemonset")
+	}
+
+	if sysctlCollector.Timeout == "" {
+		return collectSysctls(ctx, client, c, label, namespace)
+	}
+
+	timeout, err := time.ParseDuration(sysctlCollector.Timeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse timeout")
+	}
+
+	childCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	resultCh := make(chan map[string][]byte, 1)
+	go func() {
+		b, err := collectSysctls(childCtx, client, c, label, namespace)
+		if err != nil {
+			errCh <- err
+		} else {
+			resultCh <- b
+		}
+	}()
+
+	select {
+	case <-time.After(timeout):
+		return nil, errors.New("timeout")
+	case result := <-resultCh:
+		return result, nil
+	case err := <-errCh:
+		return nil, err
+	}
+}
+
+func createSysctlDaemonSet(ctx context.Context, client *kubernetes.Clientset, sysctlCollector *troubleshootv1beta2.Sysctl, namespace string, label string) (string, error) {
+	image := defaultSysctlImage
+	if sysctlCollector.Image != "" {
+		image = sysctlCollector.Image
+	}
+	pullPolicy := corev1.PullIfNotPresent
+	if sysctlCollector.ImagePullPolicy != "" {
+		pullPolicy = corev1.PullPolicy(sysctlCollector.ImagePullPolicy)
+	}
+	dsLabels := map[string]string{
+		"sysctl-collector": label,
+	}
+
+	ds := appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "troubleshoot",
+			Namespace:    namespace,
+			Labels:       dsLabels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: dsLabels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: dsLabels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyAlways,
+					HostNetwork:   true,
+					HostIPC:       true,
+					Tolerations: []corev1.Toleration{
+						{
+							Operator: corev1.TolerationOpExists,
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Image:           image,
+							ImagePullPolicy: pullPolicy,
+							Name:            "collector",
+							Command:         []string{"sleep"},
+							Args:            []string{"1000000"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if sysctlCollector.ImagePullSecret != nil && sysctlCollector.ImagePullSecret.Name != "" {
+		err := createSecret(ctx, client, namespace, sysctlCollector.ImagePullSecret)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to create secret")
+		}
+		ds.Spec.Template.Spec.ImagePullSecrets = append(ds.Spec.Template.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: sysctlCollector.ImagePullSecret.Name})
+	}
+
+	createdDS, err := client.AppsV1().DaemonSets(namespace).Create(ctx, &ds, metav1.CreateOptions{})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create daemonset")
+	}
+
+	return createdDS.Name, waitForDaemonSet(ctx, client, namespace, createdDS.Name)
+}
+
+func collectSysctls(ctx context.Context, client *kubernetes.Clientset, c *Collector, label string, namespace string) (map[string][]byte, error) {
+	labelSelector := map[string]string{
+		"sysctl-collector": label,
+	}
+	opts := metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labelSelector).String(),
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "list sysctl collector pods")
+	}
+
+	runOutput := map[string][]byte{}
+	for _, pod := range pods.Items {
+		nodePath := path.Join("sysctl", pod.Spec.NodeName)
+
+		// some parameters are not readable even from the host namespaces, so a failed exec still has useful output
+		stdout, stderr, err := execPodCommand(client, c, pod.Name, "", namespace, []string{"sysctl", "-a"})
+		if err != nil {
+			runOutput[nodePath+".error"] = []byte(err.Error())
+			if len(stderr) > 0 {
+				runOutput[nodePath+".stderr"] = stderr
+			}
+		}
+		if len(stdout) == 0 {
+			continue
+		}
+
+		b, err := json.MarshalIndent(parseSysctlOutput(stdout), "", "  ")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal sysctl values")
+		}
+		runOutput[nodePath+".json"] = b
+	}
+
+	return runOutput, nil
+}
+
+// parseSysctlOutput parses "name = value" lines. Values with several fields, such as net.ipv4.ip_local_port_range,
+// are joined with a single space.
+func parseSysctlOutput(output []byte) map[string]string {
+	values := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.TrimSpace(parts[0])
+		if name == "" {
+			continue
+		}
+		values[name] = strings.Join(strings.Fields(parts[1]), " ")
+	}
+
+	return values
+}
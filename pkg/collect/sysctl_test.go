@@ -0,0 +1,23 @@
+package collect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.undefinedlabs.com/scopeagent"
+)
+
+func Test_parseSysctlOutput(t *testing.T) {
+	scopetest := scopeagent.StartTest(t)
+	defer scopetest.End()
+	req := require.New(t)
+
+	output := []byte("vm.max_map_count = 65530\nnet.core.somaxconn = 4096\nnet.ipv4.ip_local_port_range = 32768\t60999\nkernel.domainname = \nsysctl: error reading key 'net.ipv6.conf.all.stable_secret': I/O error\n")
+
+	req.Equal(map[string]string{
+		"vm.max_map_count":             "65530",
+		"net.core.somaxconn":           "4096",
+		"net.ipv4.ip_local_port_range": "32768 60999",
+		"kernel.domainname":            "",
+	}, parseSysctlOutput(output))
+}
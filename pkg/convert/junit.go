@@ -0,0 +1,100 @@
+package convert
+
+import (
+	"encoding/xml"
+
+	"github.com/pkg/errors"
+	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+)
+
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Errors     int              `xml:"errors,attr"`
+	Skipped    int              `xml:"skipped,attr"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr,omitempty"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// ToJUnit converts analyzer results to a JUnit XML report with a single test suite, where each result is a test
+// case named by its title. Failures are reported as failures, errors as errors and warnings as skipped test cases,
+// with the message as their text. The messages of passing results are written to system-out.
+func ToJUnit(results []*analyze.AnalyzeResult) ([]byte, error) {
+	suite := junitTestSuite{
+		Name:      "troubleshoot",
+		TestCases: []junitTestCase{},
+	}
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+
+		testCase := junitTestCase{
+			Name:      result.Title,
+			ClassName: result.AnalyzerKind,
+		}
+		message := &junitMessage{
+			Message: result.Message,
+			Text:    result.Message,
+		}
+		if result.URI != "" {
+			message.Text = result.Message + "\n" + result.URI
+		}
+
+		switch {
+		case result.IsFail:
+			testCase.Failure = message
+			suite.Failures++
+		case result.IsError:
+			testCase.Error = message
+			suite.Errors++
+		case result.IsSkipped, result.IsWarn:
+			testCase.Skipped = message
+			suite.Skipped++
+		default:
+			testCase.SystemOut = message.Text
+		}
+
+		suite.Tests++
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	report := junitTestSuites{
+		Tests:      suite.Tests,
+		Failures:   suite.Failures,
+		Errors:     suite.Errors,
+		Skipped:    suite.Skipped,
+		TestSuites: []junitTestSuite{suite},
+	}
+
+	b, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal junit")
+	}
+
+	return append([]byte(xml.Header), b...), nil
+}
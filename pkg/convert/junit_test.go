@@ -0,0 +1,62 @@
+package convert
+
+import (
+	"testing"
+
+	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToJUnit(t *testing.T) {
+	req := require.New(t)
+
+	results := []*analyze.AnalyzeResult{
+		{
+			IsPass:       true,
+			Title:        "Node Memory",
+			Message:      "All nodes have at least 8 GB of memory",
+			AnalyzerKind: "nodeResources",
+		},
+		{
+			IsWarn:       true,
+			Title:        "Node Count",
+			Message:      "At least 3 nodes are recommended",
+			AnalyzerKind: "nodeResources",
+		},
+		{
+			IsFail:       true,
+			Title:        "Required Kubernetes Version",
+			Message:      "This application requires at least Kubernetes 1.24.0 & later",
+			URI:          "https://kubernetes.io",
+			AnalyzerKind: "clusterVersion",
+		},
+		{
+			IsError: true,
+			Title:   "Analyzer Failed",
+			Message: "Analyzer postgres failed",
+		},
+	}
+
+	b, err := ToJUnit(results)
+	req.NoError(err)
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="4" failures="1" errors="1" skipped="1">
+  <testsuite name="troubleshoot" tests="4" failures="1" errors="1" skipped="1">
+    <testcase name="Node Memory" classname="nodeResources">
+      <system-out>All nodes have at least 8 GB of memory</system-out>
+    </testcase>
+    <testcase name="Node Count" classname="nodeResources">
+      <skipped message="At least 3 nodes are recommended">At least 3 nodes are recommended</skipped>
+    </testcase>
+    <testcase name="Required Kubernetes Version" classname="clusterVersion">
+      <failure message="This application requires at least Kubernetes 1.24.0 &amp; later">This application requires at least Kubernetes 1.24.0 &amp; later&#xA;https://kubernetes.io</failure>
+    </testcase>
+    <testcase name="Analyzer Failed">
+      <error message="Analyzer postgres failed">Analyzer postgres failed</error>
+    </testcase>
+  </testsuite>
+</testsuites>`
+	assert.Equal(t, expected, string(b))
+}
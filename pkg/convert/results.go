@@ -0,0 +1,125 @@
+package convert
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+)
+
+// ResultsSchemaVersion is the version of the document written by MarshalResults. It changes only when fields are
+// removed or change meaning, so new fields may be added without a new version. The JSON schema for each version is
+// in schemas/analyze-results-<version>.json.
+const ResultsSchemaVersion = "v1"
+
+const (
+	ResultStatusPass    = "pass"
+	ResultStatusWarn    = "warn"
+	ResultStatusFail    = "fail"
+	ResultStatusError   = "error"
+	ResultStatusSkipped = "skipped"
+	ResultStatusNone    = "none"
+)
+
+// ResultsDocument is the document written by MarshalResults
+type ResultsDocument struct {
+	SchemaVersion string          `json:"schemaVersion"`
+	Summary       ResultsSummary  `json:"summary"`
+	Results       []ResultsResult `json:"results"`
+}
+
+// ResultsSummary counts the results by status. Results that matched no outcome are only counted in the total.
+type ResultsSummary struct {
+	Total   int `json:"total"`
+	Pass    int `json:"pass"`
+	Warn    int `json:"warn"`
+	Fail    int `json:"fail"`
+	Error   int `json:"error"`
+	Skipped int `json:"skipped"`
+}
+
+// ResultsResult is a single analyzer result. Status is one of pass, warn, fail, error, skipped or none, and agrees
+// with the boolean fields. Skipped results also have IsWarn set.
+type ResultsResult struct {
+	Status       string `json:"status"`
+	IsPass       bool   `json:"isPass"`
+	IsWarn       bool   `json:"isWarn"`
+	IsFail       bool   `json:"isFail"`
+	IsError      bool   `json:"isError"`
+	IsSkipped    bool   `json:"isSkipped"`
+	Title        string `json:"title"`
+	Message      string `json:"message"`
+	URI          string `json:"uri"`
+	IconKey      string `json:"iconKey"`
+	IconURI      string `json:"iconUri"`
+	AnalyzerKind string `json:"analyzerKind"`
+}
+
+// MarshalResults writes analyzer results as a ResultsDocument, in the order they are given
+func MarshalResults(results []*analyze.AnalyzeResult) ([]byte, error) {
+	document := ResultsDocument{
+		SchemaVersion: ResultsSchemaVersion,
+		Results:       []ResultsResult{},
+	}
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+
+		status := resultStatus(result)
+		switch status {
+		case ResultStatusPass:
+			document.Summary.Pass++
+		case ResultStatusWarn:
+			document.Summary.Warn++
+		case ResultStatusFail:
+			document.Summary.Fail++
+		case ResultStatusError:
+			document.Summary.Error++
+		case ResultStatusSkipped:
+			document.Summary.Skipped++
+		}
+		document.Summary.Total++
+
+		document.Results = append(document.Results, ResultsResult{
+			Status:       status,
+			IsPass:       result.IsPass,
+			IsWarn:       result.IsWarn,
+			IsFail:       result.IsFail,
+			IsError:      result.IsError,
+			IsSkipped:    result.IsSkipped,
+			Title:        result.Title,
+			Message:      result.Message,
+			URI:          result.URI,
+			IconKey:      result.IconKey,
+			IconURI:      result.IconURI,
+			AnalyzerKind: result.AnalyzerKind,
+		})
+	}
+
+	b, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal results")
+	}
+
+	return b, nil
+}
+
+// resultStatus checks the booleans in the same order as the preflight output, so a result with more than one set
+// is reported the same way everywhere
+func resultStatus(result *analyze.AnalyzeResult) string {
+	switch {
+	case result.IsPass:
+		return ResultStatusPass
+	case result.IsSkipped:
+		return ResultStatusSkipped
+	case result.IsWarn:
+		return ResultStatusWarn
+	case result.IsFail:
+		return ResultStatusFail
+	case result.IsError:
+		return ResultStatusError
+	}
+	return ResultStatusNone
+}
@@ -0,0 +1,72 @@
+package convert
+
+import (
+	"encoding/json"
+	"testing"
+
+	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalResults(t *testing.T) {
+	req := require.New(t)
+
+	results := []*analyze.AnalyzeResult{
+		{
+			IsFail:       true,
+			Title:        "Required Kubernetes Version",
+			Message:      "This application requires at least Kubernetes 1.24.0",
+			URI:          "https://kubernetes.io",
+			IconKey:      "kubernetes_cluster_version",
+			IconURI:      "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+			AnalyzerKind: "clusterVersion",
+		},
+		{
+			IsPass: true,
+			Title:  "Node Memory",
+		},
+		{
+			IsError: true,
+			Title:   "Analyzer Failed",
+		},
+		{
+			Title: "Container Runtime",
+		},
+		{
+			IsWarn:    true,
+			IsSkipped: true,
+			Title:     "Node Resources",
+		},
+	}
+
+	b, err := MarshalResults(results)
+	req.NoError(err)
+
+	var document ResultsDocument
+	req.NoError(json.Unmarshal(b, &document))
+
+	assert.Equal(t, "v1", document.SchemaVersion)
+	assert.Equal(t, ResultsSummary{Total: 5, Pass: 1, Fail: 1, Error: 1, Skipped: 1}, document.Summary)
+	req.Len(document.Results, 5)
+	assert.Equal(t, ResultsResult{
+		Status:       "fail",
+		IsFail:       true,
+		Title:        "Required Kubernetes Version",
+		Message:      "This application requires at least Kubernetes 1.24.0",
+		URI:          "https://kubernetes.io",
+		IconKey:      "kubernetes_cluster_version",
+		IconURI:      "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+		AnalyzerKind: "clusterVersion",
+	}, document.Results[0])
+	assert.Equal(t, "none", document.Results[3].Status)
+	assert.Equal(t, "skipped", document.Results[4].Status)
+	assert.True(t, document.Results[4].IsWarn)
+
+	// every field is written even when it is empty
+	var raw struct {
+		Results []map[string]interface{} `json:"results"`
+	}
+	req.NoError(json.Unmarshal(b, &raw))
+	assert.Len(t, raw.Results[1], 12)
+}
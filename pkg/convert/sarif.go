@@ -0,0 +1,148 @@
+package convert
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	"github.com/replicatedhq/troubleshoot/pkg/version"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	RuleIndex  int               `json:"ruleIndex"`
+	Kind       string            `json:"kind"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+var sarifRuleIDInvalidChars = regexp.MustCompile("[^a-zA-Z0-9]+")
+
+// ToSARIF converts analyzer results to a SARIF 2.1.0 log with a single run. Failures and errors are reported at
+// the error level, warnings at the warning level and passes as notes. Results are grouped into rules by analyzer
+// kind, or by title for results that do not have a kind.
+func ToSARIF(results []*analyze.AnalyzeResult) ([]byte, error) {
+	driver := sarifDriver{
+		Name:           "troubleshoot",
+		Version:        version.Version(),
+		InformationURI: "https://troubleshoot.sh",
+		Rules:          []sarifRule{},
+	}
+
+	ruleIndexes := map[string]int{}
+	sarifResults := []sarifResult{}
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+
+		ruleID := sarifRuleID(result)
+		ruleIndex, ok := ruleIndexes[ruleID]
+		if !ok {
+			ruleIndex = len(driver.Rules)
+			ruleIndexes[ruleID] = ruleIndex
+			driver.Rules = append(driver.Rules, sarifRule{
+				ID:               ruleID,
+				ShortDescription: sarifMessage{Text: result.Title},
+			})
+		}
+
+		kind, level := sarifKindAndLevel(result)
+		sarifResult := sarifResult{
+			RuleID:    ruleID,
+			RuleIndex: ruleIndex,
+			Kind:      kind,
+			Level:     level,
+			Message:   sarifMessage{Text: result.Message},
+			Properties: map[string]string{
+				"title": result.Title,
+			},
+		}
+		if sarifResult.Message.Text == "" {
+			// SARIF requires message text
+			sarifResult.Message.Text = result.Title
+		}
+		if result.URI != "" {
+			sarifResult.Properties["uri"] = result.URI
+		}
+		sarifResults = append(sarifResults, sarifResult)
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool:    sarifTool{Driver: driver},
+				Results: sarifResults,
+			},
+		},
+	}
+
+	b, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal sarif")
+	}
+
+	return b, nil
+}
+
+func sarifRuleID(result *analyze.AnalyzeResult) string {
+	if result.AnalyzerKind != "" {
+		return result.AnalyzerKind
+	}
+	return strings.Trim(sarifRuleIDInvalidChars.ReplaceAllString(strings.ToLower(result.Title), "."), ".")
+}
+
+func sarifKindAndLevel(result *analyze.AnalyzeResult) (string, string) {
+	switch {
+	case result.IsFail, result.IsError:
+		return "fail", "error"
+	case result.IsSkipped:
+		return "notApplicable", "none"
+	case result.IsWarn:
+		return "fail", "warning"
+	case result.IsPass:
+		return "pass", "note"
+	}
+	return "notApplicable", "none"
+}
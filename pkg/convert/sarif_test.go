@@ -0,0 +1,75 @@
+package convert
+
+import (
+	"encoding/json"
+	"testing"
+
+	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToSARIF(t *testing.T) {
+	req := require.New(t)
+
+	results := []*analyze.AnalyzeResult{
+		{
+			IsFail:       true,
+			Title:        "Required Kubernetes Version",
+			Message:      "This application requires at least Kubernetes 1.24.0",
+			URI:          "https://kubernetes.io",
+			AnalyzerKind: "clusterVersion",
+		},
+		{
+			IsWarn:       true,
+			Title:        "Node Count",
+			Message:      "At least 3 nodes are recommended",
+			AnalyzerKind: "nodeResources",
+		},
+		{
+			IsPass:       true,
+			Title:        "Node Memory",
+			Message:      "All nodes have at least 8 GB of memory",
+			AnalyzerKind: "nodeResources",
+		},
+		{
+			IsError: true,
+			Title:   "Analyzer Failed",
+			Message: "Analyzer postgres failed",
+		},
+	}
+
+	b, err := ToSARIF(results)
+	req.NoError(err)
+
+	var log sarifLog
+	req.NoError(json.Unmarshal(b, &log))
+
+	req.Equal("2.1.0", log.Version)
+	req.Len(log.Runs, 1)
+	run := log.Runs[0]
+
+	ruleIDs := []string{}
+	for _, rule := range run.Tool.Driver.Rules {
+		ruleIDs = append(ruleIDs, rule.ID)
+	}
+	assert.Equal(t, []string{"clusterVersion", "nodeResources", "analyzer.failed"}, ruleIDs)
+
+	req.Len(run.Results, 4)
+	assert.Equal(t, sarifResult{
+		RuleID:    "clusterVersion",
+		RuleIndex: 0,
+		Kind:      "fail",
+		Level:     "error",
+		Message:   sarifMessage{Text: "This application requires at least Kubernetes 1.24.0"},
+		Properties: map[string]string{
+			"title": "Required Kubernetes Version",
+			"uri":   "https://kubernetes.io",
+		},
+	}, run.Results[0])
+	assert.Equal(t, "warning", run.Results[1].Level)
+	assert.Equal(t, 1, run.Results[2].RuleIndex)
+	assert.Equal(t, "note", run.Results[2].Level)
+	assert.Equal(t, "pass", run.Results[2].Kind)
+	assert.Equal(t, "error", run.Results[3].Level)
+}
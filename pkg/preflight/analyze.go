@@ -1,57 +1,332 @@
 package preflight
 
 import (
+	"context"
 	"fmt"
+	"path"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/pkg/errors"
 	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 )
 
-// Analyze runs the analyze phase of preflight checks
+const defaultAnalyzerTimeout = 30 * time.Second
+
+var errAnalyzerTimeout = errors.New("analyzer timed out")
+
+// Analyze runs the analyze phase of preflight checks. Up to c.AnalyzerConcurrency analyzers, one per CPU by default,
+// run at once. Each analyzer that runs longer than c.AnalyzerTimeout, 30 seconds by default, is reported as failed.
+// Results are in the order of the analyzers in the spec. Analyzers that return several results, such as textAnalyze,
+// may not order them the same way between runs, so c.SortResults sorts all results by title and then message instead.
 func (c CollectResult) Analyze() []*analyze.AnalyzeResult {
-	getCollectedFileContents := func(fileName string) ([]byte, error) {
-		contents, ok := c.AllCollectedData[fileName]
-		if !ok {
-			return nil, fmt.Errorf("file %s was not collected", fileName)
-		}
+	return c.AnalyzeContext(context.Background())
+}
 
-		return contents, nil
+// AnalyzeContext is like Analyze, but stops starting analyzers once ctx is done. The results of the analyzers that
+// finished are returned, followed by a warning that the analysis was cancelled.
+func (c CollectResult) AnalyzeContext(ctx context.Context) []*analyze.AnalyzeResult {
+	return c.analyze(ctx, c.getCollectedFileContents, c.getChildCollectedFileContents)
+}
+
+// AnalyzeDir is like AnalyzeContext, but reads the collected files from dir, such as an extracted support bundle,
+// instead of from c.AllCollectedData. Files are only read when an analyzer asks for them.
+func (c CollectResult) AnalyzeDir(ctx context.Context, dir string) []*analyze.AnalyzeResult {
+	collected := collectedDir(dir)
+	return c.analyze(ctx, collected.getCollectedFileContents, collected.getChildCollectedFileContents)
+}
+
+func (c CollectResult) analyze(ctx context.Context, getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) []*analyze.AnalyzeResult {
+	timeout := c.AnalyzerTimeout
+	if timeout == 0 {
+		timeout = defaultAnalyzerTimeout
+	}
+	concurrency := c.AnalyzerConcurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
 	}
-	getChildCollectedFileContents := func(prefix string) (map[string][]byte, error) {
-		matching := make(map[string][]byte)
-		for k, v := range c.AllCollectedData {
-			if strings.HasPrefix(k, prefix) {
-				matching[k] = v
+
+	// parsed collected files, such as the node list, are shared by all analyzers
+	cache := analyze.NewCollectedObjectCache(getFile)
+
+	// each worker writes only to the slots of the analyzers it runs, so results keep the order of the spec
+	analyzers := c.Spec.Spec.Analyzers
+	resultsByAnalyzer := make([][]*analyze.AnalyzeResult, len(analyzers))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				if ctx.Err() != nil {
+					continue
+				}
+				resultsByAnalyzer[idx] = analyzeOne(ctx, analyzers[idx], c.Values, timeout, getFile, findFiles, cache)
 			}
+		}()
+	}
+	for idx := range analyzers {
+		if ctx.Err() != nil {
+			break
 		}
+		indexes <- idx
+	}
+	close(indexes)
+	wg.Wait()
 
-		for k, v := range c.AllCollectedData {
-			if ok, _ := filepath.Match(prefix, k); ok {
-				matching[k] = v
-			}
+	analyzeResults := []*analyze.AnalyzeResult{}
+	for _, analyzeResult := range resultsByAnalyzer {
+		if analyzeResult != nil {
+			analyzeResults = append(analyzeResults, analyzeResult...)
 		}
+	}
 
-		return matching, nil
+	if c.SortResults {
+		sortAnalyzeResults(analyzeResults)
 	}
 
-	analyzeResults := []*analyze.AnalyzeResult{}
-	for _, analyzer := range c.Spec.Spec.Analyzers {
-		analyzeResult, err := analyze.Analyze(analyzer, getCollectedFileContents, getChildCollectedFileContents)
+	if ctx.Err() != nil {
+		analyzeResults = append(analyzeResults, &analyze.AnalyzeResult{
+			IsWarn:  true,
+			Title:   "Analysis Cancelled",
+			Message: "Analysis was cancelled before all analyzers finished, so these results are incomplete",
+		})
+	}
+
+	return analyzeResults
+}
+
+// SpecProblem is a problem found in an analyzer of the spec by ValidateSpec
+type SpecProblem struct {
+	Index    int
+	Analyzer string
+	Err      error
+	// IsWarning is set for problems found by LintSpec, which do not stop the analyzer from running
+	IsWarning bool
+}
+
+func (p SpecProblem) Error() string {
+	if p.IsWarning {
+		return fmt.Sprintf("warning: analyzer %d (%s): %s", p.Index, p.Analyzer, p.Err.Error())
+	}
+	return fmt.Sprintf("analyzer %d (%s): %s", p.Index, p.Analyzer, p.Err.Error())
+}
+
+// ValidateSpec checks the analyzers in the spec without any collected data, so that mistakes such as unparseable
+// conditionals are found when the spec is written instead of when it runs
+func (c CollectResult) ValidateSpec() []SpecProblem {
+	problems := []SpecProblem{}
+	for i, analyzer := range c.Spec.Spec.Analyzers {
+		interpolated, err := analyze.InterpolateAnalyzer(analyzer, c.Values)
+		if err != nil {
+			problems = append(problems, SpecProblem{
+				Index:    i,
+				Analyzer: analyzerName(analyzer),
+				Err:      err,
+			})
+			continue
+		}
+		for _, err := range analyze.ValidateAnalyzer(interpolated) {
+			problems = append(problems, SpecProblem{
+				Index:    i,
+				Analyzer: analyzerName(analyzer),
+				Err:      err,
+			})
+		}
+	}
+	return problems
+}
+
+// LintSpec checks the analyzers in the spec for conditionals that are valid but are likely mistakes, such as a
+// nodeResources outcome that always or never matches. The problems are warnings, unlike those of ValidateSpec.
+func (c CollectResult) LintSpec() []SpecProblem {
+	problems := []SpecProblem{}
+	for i, analyzer := range c.Spec.Spec.Analyzers {
+		// references that cannot be interpolated are reported by ValidateSpec
+		interpolated, err := analyze.InterpolateAnalyzer(analyzer, c.Values)
 		if err != nil {
-			analyzeResult = []*analyze.AnalyzeResult{
-				{
-					IsFail:  true,
-					Title:   "Analyzer Failed",
-					Message: err.Error(),
-				},
+			continue
+		}
+		for _, err := range analyze.LintAnalyzer(interpolated) {
+			problems = append(problems, SpecProblem{
+				Index:     i,
+				Analyzer:  analyzerName(analyzer),
+				Err:       err,
+				IsWarning: true,
+			})
+		}
+	}
+	return problems
+}
+
+// ParseValues parses NAME=VALUE pairs, such as those given with --var, into the values of a CollectResult. The value
+// is everything after the first =, and may be empty.
+func ParseValues(pairs []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, pair := range pairs {
+		idx := strings.Index(pair, "=")
+		if idx <= 0 {
+			return nil, errors.Errorf("invalid value %q, must be NAME=VALUE", pair)
+		}
+		values[pair[:idx]] = pair[idx+1:]
+	}
+	return values, nil
+}
+
+// sortAnalyzeResults sorts results by title and then message, keeping the order of results that are otherwise equal
+func sortAnalyzeResults(results []*analyze.AnalyzeResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Title != results[j].Title {
+			return results[i].Title < results[j].Title
+		}
+		return results[i].Message < results[j].Message
+	})
+}
+
+// getCollectedFileContents returns the collected file, decompressing fileName.gz if only a gzipped copy was stored
+func (c CollectResult) getCollectedFileContents(fileName string) ([]byte, error) {
+	contents, ok := c.AllCollectedData[fileName]
+	if !ok {
+		gzipped, ok := c.AllCollectedData[fileName+".gz"]
+		if !ok {
+			return nil, fmt.Errorf("file %s was not collected", fileName)
+		}
+		return analyze.GunzipCollectedFile(fileName+".gz", gzipped)
+	}
+
+	return contents, nil
+}
+
+// getChildCollectedFileContents returns the collected files under prefix, or matching prefix as a glob. In globs a
+// `**` path segment matches any number of directories.
+func (c CollectResult) getChildCollectedFileContents(prefix string) (map[string][]byte, error) {
+	matching := make(map[string][]byte)
+	for k, v := range c.AllCollectedData {
+		if childPathMatches(prefix, k) {
+			matching[k] = v
+		}
+	}
+
+	return matching, nil
+}
+
+func childPathMatches(prefix string, name string) bool {
+	if strings.HasPrefix(name, prefix) {
+		return true
+	}
+	if ok, _ := filepath.Match(prefix, name); ok {
+		return true
+	}
+	return strings.Contains(prefix, "**") && matchDoubleStar(prefix, name)
+}
+
+func matchDoubleStar(pattern string, name string) bool {
+	return matchPathSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchPathSegments(pattern []string, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchPathSegments(pattern[1:], name[i:]) {
+					return true
+				}
 			}
+			return false
 		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
 
-		if analyzeResult != nil {
-			analyzeResults = append(analyzeResults, analyzeResult...)
+// analyzeOne runs a single analyzer, turning errors and timeouts into error results. Nothing is returned for an
+// analyzer that is interrupted by ctx.
+func analyzeOne(ctx context.Context, analyzer *troubleshootv1beta2.Analyze, values map[string]string, timeout time.Duration, getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error), cache *analyze.CollectedObjectCache) []*analyze.AnalyzeResult {
+	kind, _ := analyze.AnalyzerKindAndMeta(analyzer)
+
+	interpolated, err := analyze.InterpolateAnalyzer(analyzer, values)
+	if err != nil {
+		return []*analyze.AnalyzeResult{
+			{
+				IsError:      true,
+				Title:        "Analyzer Failed",
+				Message:      fmt.Sprintf("Analyzer %s failed: %s", analyzerName(analyzer), err.Error()),
+				AnalyzerKind: kind,
+			},
 		}
 	}
 
-	return analyzeResults
+	analyzeResult, err := analyzeWithTimeout(ctx, timeout, interpolated, getFile, findFiles, cache)
+	if ctx.Err() != nil && err == ctx.Err() {
+		return nil
+	} else if err == errAnalyzerTimeout {
+		return []*analyze.AnalyzeResult{
+			{
+				IsError:      true,
+				Title:        "Analyzer Timed Out",
+				Message:      fmt.Sprintf("Analyzer %s did not finish within %s", analyzerName(analyzer), timeout),
+				AnalyzerKind: kind,
+			},
+		}
+	} else if err != nil {
+		return []*analyze.AnalyzeResult{
+			{
+				IsError:      true,
+				Title:        "Analyzer Failed",
+				Message:      fmt.Sprintf("Analyzer %s failed: %s", analyzerName(analyzer), err.Error()),
+				AnalyzerKind: kind,
+			},
+		}
+	}
+
+	return analyzeResult
+}
+
+// analyzeWithTimeout stops waiting for the analyzer once timeout has passed and returns errAnalyzerTimeout, or once ctx
+// is cancelled and returns its error. The analyzer is passed a context that is done at the same time, which the
+// analyzers that loop over many nodes or files check, but others are left running in the background if they hang.
+func analyzeWithTimeout(ctx context.Context, timeout time.Duration, analyzer *troubleshootv1beta2.Analyze, getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error), cache *analyze.CollectedObjectCache) ([]*analyze.AnalyzeResult, error) {
+	analyzeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type analyzeOutput struct {
+		results []*analyze.AnalyzeResult
+		err     error
+	}
+	done := make(chan analyzeOutput, 1)
+	go func() {
+		results, err := analyze.AnalyzeWithCache(analyzeCtx, analyzer, getFile, findFiles, cache)
+		done <- analyzeOutput{results: results, err: err}
+	}()
+
+	select {
+	case output := <-done:
+		return output.results, output.err
+	case <-analyzeCtx.Done():
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errAnalyzerTimeout
+	}
+}
+
+// analyzerName describes an analyzer by its kind and, if set, its check name
+func analyzerName(analyzer *troubleshootv1beta2.Analyze) string {
+	kind, meta := analyze.AnalyzerKindAndMeta(analyzer)
+	if meta.CheckName == "" {
+		return kind
+	}
+	return fmt.Sprintf("%s %q", kind, meta.CheckName)
 }
@@ -0,0 +1,421 @@
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func nodeResourcesCollectResult(t testing.TB, nodeCount int, analyzerCount int) CollectResult {
+	nodes := []corev1.Node{}
+	for i := 0; i < nodeCount; i++ {
+		nodes = append(nodes, corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("node-%d", i),
+			},
+			Status: corev1.NodeStatus{
+				Capacity: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("4"),
+					corev1.ResourceMemory: resource.MustParse("16Gi"),
+				},
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("3800m"),
+					corev1.ResourceMemory: resource.MustParse("15Gi"),
+				},
+			},
+		})
+	}
+	nodesJSON, err := json.Marshal(nodes)
+	require.NoError(t, err)
+
+	analyzers := []*troubleshootv1beta2.Analyze{}
+	for i := 0; i < analyzerCount; i++ {
+		analyzers = append(analyzers, &troubleshootv1beta2.Analyze{
+			NodeResources: &troubleshootv1beta2.NodeResources{
+				AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{
+					CheckName: fmt.Sprintf("check %d", i),
+				},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    fmt.Sprintf("count() < %d", i),
+							Message: "not enough nodes",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "enough nodes",
+						},
+					},
+				},
+			},
+		})
+	}
+
+	return CollectResult{
+		AllCollectedData: map[string][]byte{
+			"cluster-resources/nodes.json": nodesJSON,
+		},
+		Spec: &troubleshootv1beta2.Preflight{
+			Spec: troubleshootv1beta2.PreflightSpec{
+				Analyzers: analyzers,
+			},
+		},
+	}
+}
+
+func TestCollectResult_AnalyzeConcurrentOrder(t *testing.T) {
+	req := require.New(t)
+
+	c := nodeResourcesCollectResult(t, 10, 50)
+	c.AnalyzerConcurrency = 8
+
+	results := c.Analyze()
+	req.Len(results, 50)
+	for i, result := range results {
+		req.Equal(fmt.Sprintf("check %d", i), result.Title)
+	}
+}
+
+func TestCollectResult_AnalyzeSortResults(t *testing.T) {
+	req := require.New(t)
+
+	c := CollectResult{
+		AllCollectedData: map[string][]byte{},
+		Spec: &troubleshootv1beta2.Preflight{
+			Spec: troubleshootv1beta2.PreflightSpec{
+				Analyzers: []*troubleshootv1beta2.Analyze{
+					{
+						TextAnalyze: &troubleshootv1beta2.TextAnalyze{
+							AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{
+								CheckName: "log levels",
+							},
+							CollectorName: "logs",
+							FileName:      "level-*.log",
+							RegexGroups:   `level=(?P<Level>\w+)`,
+							Outcomes: []*troubleshootv1beta2.Outcome{
+								{
+									Fail: &troubleshootv1beta2.SingleOutcome{
+										When:    "Level == error",
+										Message: "errors logged",
+									},
+								},
+								{
+									Pass: &troubleshootv1beta2.SingleOutcome{
+										Message: "no errors logged",
+									},
+								},
+							},
+						},
+					},
+					{
+						TextAnalyze: &troubleshootv1beta2.TextAnalyze{
+							AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{
+								CheckName: "application logs",
+							},
+							CollectorName: "logs",
+							FileName:      "app.log",
+							RegexPattern:  "started",
+							Outcomes: []*troubleshootv1beta2.Outcome{
+								{
+									Pass: &troubleshootv1beta2.SingleOutcome{
+										Message: "application started",
+									},
+								},
+								{
+									Fail: &troubleshootv1beta2.SingleOutcome{
+										Message: "application did not start",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		SortResults: true,
+	}
+	for i := 0; i < 10; i++ {
+		level := "info"
+		if i%2 == 0 {
+			level = "error"
+		}
+		c.AllCollectedData[fmt.Sprintf("logs/level-%d.log", i)] = []byte(fmt.Sprintf("level=%s\n", level))
+	}
+	c.AllCollectedData["logs/app.log"] = []byte("started\n")
+
+	first := c.Analyze()
+	second := c.Analyze()
+	req.Equal(first, second)
+
+	req.Len(first, 11)
+	req.Equal("application logs", first[0].Title)
+	for i := 1; i < len(first); i++ {
+		req.Equal("log levels", first[i].Title)
+	}
+	req.Equal("errors logged", first[1].Message)
+	req.Equal("no errors logged", first[len(first)-1].Message)
+}
+
+func TestCollectResult_AnalyzeContextCancelled(t *testing.T) {
+	req := require.New(t)
+
+	c := nodeResourcesCollectResult(t, 10, 50)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := c.AnalyzeContext(ctx)
+	req.Len(results, 1)
+	req.True(results[0].IsWarn)
+	req.Equal("Analysis Cancelled", results[0].Title)
+}
+
+func TestCollectResult_AnalyzeFailedAnalyzer(t *testing.T) {
+	req := require.New(t)
+
+	c := nodeResourcesCollectResult(t, 1, 1)
+	delete(c.AllCollectedData, "cluster-resources/nodes.json")
+
+	results := c.Analyze()
+	req.Len(results, 1)
+	req.True(results[0].IsError)
+	req.False(results[0].IsFail)
+	req.Equal("Analyzer Failed", results[0].Title)
+	req.Contains(results[0].Message, `Analyzer nodeResources "check 0" failed: `)
+	req.Contains(results[0].Message, "cluster-resources/nodes.json was not collected")
+}
+
+func TestCollectResult_ValidateSpec(t *testing.T) {
+	req := require.New(t)
+
+	c := nodeResourcesCollectResult(t, 0, 3)
+	c.Spec.Spec.Analyzers[1].NodeResources.Outcomes[0].Fail.When = "coutn() >= 3"
+
+	problems := c.ValidateSpec()
+	req.Len(problems, 1)
+	req.Equal(1, problems[0].Index)
+	req.Equal(`nodeResources "check 1"`, problems[0].Analyzer)
+	req.Contains(problems[0].Error(), `unsupported function "coutn"`)
+}
+
+func TestCollectResult_AnalyzeValues(t *testing.T) {
+	req := require.New(t)
+
+	c := nodeResourcesCollectResult(t, 3, 1)
+	c.Spec.Spec.Analyzers[0].NodeResources.Outcomes[0].Fail.When = "count() < ${MIN_NODES}"
+	c.Spec.Spec.Analyzers[0].NodeResources.Outcomes[0].Fail.Message = "at least ${MIN_NODES} nodes are required"
+
+	c.Values = map[string]string{"MIN_NODES": "5"}
+	results := c.Analyze()
+	req.Len(results, 1)
+	req.True(results[0].IsFail)
+	req.Equal("at least 5 nodes are required", results[0].Message)
+	req.Empty(c.ValidateSpec())
+
+	c.Values = nil
+	results = c.Analyze()
+	req.Len(results, 1)
+	req.True(results[0].IsError)
+	req.Contains(results[0].Message, "outcomes[0].fail.when: variable MIN_NODES is not set")
+
+	problems := c.ValidateSpec()
+	req.Len(problems, 1)
+	req.Contains(problems[0].Error(), "variable MIN_NODES is not set")
+}
+
+func TestParseValues(t *testing.T) {
+	req := require.New(t)
+
+	values, err := ParseValues([]string{"MIN_NODES=3", "SELECTOR=pool=app", "EMPTY="})
+	req.NoError(err)
+	req.Equal(map[string]string{"MIN_NODES": "3", "SELECTOR": "pool=app", "EMPTY": ""}, values)
+
+	_, err = ParseValues([]string{"MIN_NODES"})
+	req.EqualError(err, `invalid value "MIN_NODES", must be NAME=VALUE`)
+}
+
+func TestCollectResult_LintSpec(t *testing.T) {
+	req := require.New(t)
+
+	// check 0 fails when count() < 0, which never matches
+	c := nodeResourcesCollectResult(t, 0, 3)
+
+	problems := c.LintSpec()
+	req.Len(problems, 1)
+	req.True(problems[0].IsWarning)
+	req.Equal(0, problems[0].Index)
+	req.Equal(`warning: analyzer 0 (nodeResources "check 0"): outcomes[0].fail.when "count() < 0" never matches`, problems[0].Error())
+}
+
+func TestCollectResult_getCollectedFileContents(t *testing.T) {
+	req := require.New(t)
+
+	c := CollectResult{
+		AllCollectedData: map[string][]byte{
+			"cluster-resources/nodes.json.gz": gzipContents(t, []byte("[]")),
+		},
+	}
+
+	contents, err := c.getCollectedFileContents("cluster-resources/nodes.json")
+	req.NoError(err)
+	req.Equal([]byte("[]"), contents)
+
+	_, err = c.getCollectedFileContents("cluster-resources/pods.json")
+	req.EqualError(err, "file cluster-resources/pods.json was not collected")
+}
+
+func TestCollectResult_getChildCollectedFileContents(t *testing.T) {
+	c := CollectResult{
+		AllCollectedData: map[string][]byte{
+			"secrets/default/registry.json":  []byte("registry"),
+			"secrets/kube-system/other.json": []byte("other"),
+			"logs/app.log":                   []byte("log"),
+			"logs/web/app.log":               []byte("web log"),
+			"logs/web/pod-1/app.log":         []byte("pod log"),
+			"logs/web/pod-1/other.log":       []byte("other log"),
+		},
+	}
+
+	tests := []struct {
+		name   string
+		prefix string
+		want   map[string][]byte
+	}{
+		{
+			name:   "prefix",
+			prefix: "secrets/",
+			want: map[string][]byte{
+				"secrets/default/registry.json":  []byte("registry"),
+				"secrets/kube-system/other.json": []byte("other"),
+			},
+		},
+		{
+			name:   "glob",
+			prefix: "secrets/*/registry.json",
+			want: map[string][]byte{
+				"secrets/default/registry.json": []byte("registry"),
+			},
+		},
+		{
+			name:   "double star spans directories",
+			prefix: "logs/**/app.log",
+			want: map[string][]byte{
+				"logs/app.log":           []byte("log"),
+				"logs/web/app.log":       []byte("web log"),
+				"logs/web/pod-1/app.log": []byte("pod log"),
+			},
+		},
+		{
+			name:   "double star with single star",
+			prefix: "logs/**/pod-*/*.log",
+			want: map[string][]byte{
+				"logs/web/pod-1/app.log":   []byte("pod log"),
+				"logs/web/pod-1/other.log": []byte("other log"),
+			},
+		},
+		{
+			name:   "trailing double star",
+			prefix: "secrets/**",
+			want: map[string][]byte{
+				"secrets/default/registry.json":  []byte("registry"),
+				"secrets/kube-system/other.json": []byte("other"),
+			},
+		},
+		{
+			name:   "no matches",
+			prefix: "pods/",
+			want:   map[string][]byte{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			got, err := c.getChildCollectedFileContents(tt.prefix)
+			req.NoError(err)
+			req.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestCollectResult_AnalyzeTimeout(t *testing.T) {
+	req := require.New(t)
+
+	c := nodeResourcesCollectResult(t, 3, 2)
+	c.AnalyzerTimeout = 50 * time.Millisecond
+	c.Spec.Spec.Analyzers = []*troubleshootv1beta2.Analyze{
+		c.Spec.Spec.Analyzers[0],
+		{
+			TextAnalyze: &troubleshootv1beta2.TextAnalyze{
+				AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{
+					CheckName: "slow logs",
+				},
+				CollectorName: "slow",
+				FileName:      "*.log",
+				RegexPattern:  "error",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							Message: "errors logged",
+						},
+					},
+				},
+			},
+		},
+		c.Spec.Spec.Analyzers[1],
+	}
+
+	// reading the files of the slow collector blocks until the test is done
+	unblock := make(chan struct{})
+	defer close(unblock)
+	findFiles := func(prefix string) (map[string][]byte, error) {
+		if strings.HasPrefix(prefix, "slow/") {
+			<-unblock
+		}
+		return c.getChildCollectedFileContents(prefix)
+	}
+
+	results := c.analyze(context.Background(), c.getCollectedFileContents, findFiles)
+	req.Len(results, 3)
+
+	req.Equal("check 0", results[0].Title)
+	req.True(results[0].IsPass)
+	req.Equal("enough nodes", results[0].Message)
+
+	req.Equal("Analyzer Timed Out", results[1].Title)
+	req.True(results[1].IsError)
+	req.Equal(`Analyzer textAnalyze "slow logs" did not finish within 50ms`, results[1].Message)
+	req.Equal("textAnalyze", results[1].AnalyzerKind)
+
+	req.Equal("check 1", results[2].Title)
+	req.True(results[2].IsPass)
+	req.Equal("enough nodes", results[2].Message)
+}
+
+func BenchmarkCollectResult_Analyze(b *testing.B) {
+	for _, concurrency := range []int{1, 0} {
+		name := fmt.Sprintf("concurrency=%d", concurrency)
+		if concurrency == 0 {
+			name = "concurrency=default"
+		}
+		b.Run(name, func(b *testing.B) {
+			c := nodeResourcesCollectResult(b, 2000, 50)
+			c.AnalyzerConcurrency = concurrency
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				c.Analyze()
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package preflight
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
@@ -18,10 +19,16 @@ type CollectOpts struct {
 }
 
 type CollectResult struct {
-	AllCollectedData map[string][]byte
-	Collectors       collect.Collectors
-	IsRBACAllowed    bool
-	Spec             *troubleshootv1beta2.Preflight
+	AllCollectedData    map[string][]byte
+	Collectors          collect.Collectors
+	IsRBACAllowed       bool
+	Spec                *troubleshootv1beta2.Preflight
+	AnalyzerTimeout     time.Duration
+	AnalyzerConcurrency int
+	SortResults         bool
+	// Values are substituted for ${NAME} references in nodeResources outcomes before they are analyzed or validated.
+	// Nothing is read from the environment, so references to names that are not in Values fail.
+	Values map[string]string
 }
 
 // Collect runs the collection phase of preflight checks
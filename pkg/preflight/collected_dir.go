@@ -0,0 +1,88 @@
+package preflight
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+)
+
+// collectedDir is a directory of collected files, named by their path relative to the directory
+type collectedDir string
+
+// getCollectedFileContents reads the collected file, decompressing fileName.gz if only a gzipped copy was stored
+func (d collectedDir) getCollectedFileContents(fileName string) ([]byte, error) {
+	contents, err := ioutil.ReadFile(filepath.Join(string(d), filepath.FromSlash(fileName)))
+	if os.IsNotExist(err) {
+		gzipped, gzErr := ioutil.ReadFile(filepath.Join(string(d), filepath.FromSlash(fileName+".gz")))
+		if os.IsNotExist(gzErr) {
+			return nil, fmt.Errorf("file %s was not collected", fileName)
+		} else if gzErr != nil {
+			return nil, errors.Wrapf(gzErr, "failed to read %s.gz", fileName)
+		}
+		return analyze.GunzipCollectedFile(fileName+".gz", gzipped)
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", fileName)
+	}
+
+	return contents, nil
+}
+
+// getChildCollectedFileContents matches files the same way as CollectResult.getChildCollectedFileContents. Only the
+// directories that can contain a match are walked.
+func (d collectedDir) getChildCollectedFileContents(prefix string) (map[string][]byte, error) {
+	root := filepath.Join(string(d), filepath.FromSlash(childSearchDir(prefix)))
+
+	matching := make(map[string][]byte)
+	err := filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && filePath == root {
+				return nil
+			}
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(string(d), filePath)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if !childPathMatches(prefix, name) {
+			return nil
+		}
+
+		contents, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", name)
+		}
+		matching[name] = contents
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find files matching %s", prefix)
+	}
+
+	return matching, nil
+}
+
+// childSearchDir returns the deepest directory that contains every file matching prefix. The last path segment is
+// never included, since a plain prefix also matches names that only start with it.
+func childSearchDir(prefix string) string {
+	segments := strings.Split(prefix, "/")
+	dir := []string{}
+	for _, segment := range segments[:len(segments)-1] {
+		if strings.ContainsAny(segment, `*?[\`) {
+			break
+		}
+		dir = append(dir, segment)
+	}
+	return path.Join(dir...)
+}
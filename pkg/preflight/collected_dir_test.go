@@ -0,0 +1,108 @@
+package preflight
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeCollectedDir(t *testing.T, files map[string][]byte) string {
+	dir, err := ioutil.TempDir("", "preflight-collected")
+	require.NoError(t, err)
+
+	for name, contents := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+		require.NoError(t, ioutil.WriteFile(filePath, contents, 0644))
+	}
+
+	return dir
+}
+
+func gzipContents(t *testing.T, contents []byte) []byte {
+	var buf bytes.Buffer
+	gzWriter := gzip.NewWriter(&buf)
+	_, err := gzWriter.Write(contents)
+	require.NoError(t, err)
+	require.NoError(t, gzWriter.Close())
+	return buf.Bytes()
+}
+
+func TestCollectResult_AnalyzeDir(t *testing.T) {
+	req := require.New(t)
+
+	c := nodeResourcesCollectResult(t, 3, 5)
+	dir := writeCollectedDir(t, c.AllCollectedData)
+	defer os.RemoveAll(dir)
+
+	expected := c.Analyze()
+
+	c.AllCollectedData = nil
+	req.Equal(expected, c.AnalyzeDir(context.Background(), dir))
+}
+
+func TestCollectedDir_getCollectedFileContents(t *testing.T) {
+	req := require.New(t)
+
+	dir := writeCollectedDir(t, map[string][]byte{
+		"cluster-resources/nodes.json":     []byte("[]"),
+		"cluster-resources/pvs.json.gz":    gzipContents(t, []byte(`[{"metadata": {"name": "pv-1"}}]`)),
+		"cluster-resources/events.json.gz": []byte("not gzipped"),
+	})
+	defer os.RemoveAll(dir)
+
+	contents, err := collectedDir(dir).getCollectedFileContents("cluster-resources/nodes.json")
+	req.NoError(err)
+	req.Equal([]byte("[]"), contents)
+
+	contents, err = collectedDir(dir).getCollectedFileContents("cluster-resources/pvs.json")
+	req.NoError(err)
+	req.Equal([]byte(`[{"metadata": {"name": "pv-1"}}]`), contents)
+
+	_, err = collectedDir(dir).getCollectedFileContents("cluster-resources/events.json")
+	req.Error(err)
+
+	_, err = collectedDir(dir).getCollectedFileContents("cluster-resources/pods.json")
+	req.EqualError(err, "file cluster-resources/pods.json was not collected")
+}
+
+func TestCollectedDir_getChildCollectedFileContents(t *testing.T) {
+	c := CollectResult{
+		AllCollectedData: map[string][]byte{
+			"secrets/default/registry.json":  []byte("registry"),
+			"secrets/kube-system/other.json": []byte("other"),
+			"logs/app.log":                   []byte("log"),
+			"logs/web/app.log":               []byte("web log"),
+			"logs/web/pod-1/app.log":         []byte("pod log"),
+		},
+	}
+	dir := writeCollectedDir(t, c.AllCollectedData)
+	defer os.RemoveAll(dir)
+
+	prefixes := []string{
+		"secrets/",
+		"secrets/def",
+		"secrets/*/registry.json",
+		"logs/**/app.log",
+		"logs/web/pod-*/*.log",
+		"pods/",
+	}
+	for _, prefix := range prefixes {
+		t.Run(prefix, func(t *testing.T) {
+			req := require.New(t)
+
+			expected, err := c.getChildCollectedFileContents(prefix)
+			req.NoError(err)
+
+			actual, err := collectedDir(dir).getChildCollectedFileContents(prefix)
+			req.NoError(err)
+			req.Equal(expected, actual)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package preflight
+
+import (
+	"github.com/pkg/errors"
+	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+)
+
+// Status is the overall outcome of a preflight run
+type Status string
+
+const (
+	StatusPass  Status = "pass"
+	StatusWarn  Status = "warn"
+	StatusFail  Status = "fail"
+	StatusError Status = "error"
+)
+
+// Exit codes for each status, used by the preflight CLI when it is not interactive
+const (
+	ExitCodePass  = 0
+	ExitCodeFail  = 1
+	ExitCodeError = 2
+	ExitCodeWarn  = 3
+)
+
+// ExitCode returns the process exit code for the status
+func (s Status) ExitCode() int {
+	switch s {
+	case StatusWarn:
+		return ExitCodeWarn
+	case StatusFail:
+		return ExitCodeFail
+	case StatusError:
+		return ExitCodeError
+	}
+	return ExitCodePass
+}
+
+// ReduceResults returns the most severe status of the results, with error before fail, warn and then pass, and the
+// exit code for that status. An error means an analyzer could not run, so its checks are unknown rather than
+// failed. Results that matched no outcome, skipped results, and an empty list of results, count as passing.
+func ReduceResults(results []*analyze.AnalyzeResult) (Status, int) {
+	status := StatusPass
+	for _, result := range results {
+		if result == nil || result.IsSkipped {
+			continue
+		}
+		if result.IsError {
+			status = StatusError
+			break
+		}
+		if result.IsFail {
+			status = StatusFail
+		} else if result.IsWarn && status != StatusFail {
+			status = StatusWarn
+		}
+	}
+
+	return status, status.ExitCode()
+}
+
+// ParseSeverityThreshold parses a severity threshold of pass, warn or fail. An empty threshold is fail, which leaves
+// results as they are.
+func ParseSeverityThreshold(threshold string) (Status, error) {
+	switch Status(threshold) {
+	case "":
+		return StatusFail, nil
+	case StatusPass, StatusWarn, StatusFail:
+		return Status(threshold), nil
+	}
+	return "", errors.Errorf("invalid severity threshold %q, must be one of pass, warn or fail", threshold)
+}
+
+// ApplySeverityThreshold returns copies of the results where those at or above the threshold are failures, e.g. a
+// warn threshold makes warnings fail so that CI can be strict. The results themselves are not changed, so they can
+// still be shown with their own severity. Errors, skipped results, and results that matched no outcome, are kept as
+// they are.
+func ApplySeverityThreshold(results []*analyze.AnalyzeResult, threshold Status) []*analyze.AnalyzeResult {
+	elevated := make([]*analyze.AnalyzeResult, 0, len(results))
+	for _, result := range results {
+		if result == nil {
+			elevated = append(elevated, nil)
+			continue
+		}
+		copied := *result
+		if copied.IsSkipped {
+			elevated = append(elevated, &copied)
+			continue
+		}
+		if (copied.IsWarn && threshold != StatusFail) || (copied.IsPass && threshold == StatusPass) {
+			copied.IsFail = true
+			copied.IsWarn = false
+			copied.IsPass = false
+		}
+		elevated = append(elevated, &copied)
+	}
+	return elevated
+}
+
+// ReduceResultsWithThreshold is ReduceResults after applying the severity threshold. Warnings that are below the
+// threshold are still the status, but do not fail the exit code, so only a warn or pass threshold makes them fail.
+func ReduceResultsWithThreshold(results []*analyze.AnalyzeResult, threshold Status) (Status, int) {
+	status, exitCode := ReduceResults(ApplySeverityThreshold(results, threshold))
+	if status == StatusWarn {
+		return status, ExitCodePass
+	}
+	return status, exitCode
+}
@@ -0,0 +1,151 @@
+package preflight
+
+import (
+	"testing"
+
+	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReduceResults(t *testing.T) {
+	tests := []struct {
+		name             string
+		results          []*analyze.AnalyzeResult
+		expectedStatus   Status
+		expectedExitCode int
+	}{
+		{
+			name:             "no results",
+			expectedStatus:   StatusPass,
+			expectedExitCode: 0,
+		},
+		{
+			name: "pass",
+			results: []*analyze.AnalyzeResult{
+				{IsPass: true},
+				{},
+			},
+			expectedStatus:   StatusPass,
+			expectedExitCode: 0,
+		},
+		{
+			name: "warn",
+			results: []*analyze.AnalyzeResult{
+				{IsPass: true},
+				{IsWarn: true},
+			},
+			expectedStatus:   StatusWarn,
+			expectedExitCode: 3,
+		},
+		{
+			name: "skipped is not a warning",
+			results: []*analyze.AnalyzeResult{
+				{IsPass: true},
+				{IsWarn: true, IsSkipped: true},
+			},
+			expectedStatus:   StatusPass,
+			expectedExitCode: 0,
+		},
+		{
+			name: "fail before warn",
+			results: []*analyze.AnalyzeResult{
+				{IsFail: true},
+				{IsWarn: true},
+			},
+			expectedStatus:   StatusFail,
+			expectedExitCode: 1,
+		},
+		{
+			name: "error before fail",
+			results: []*analyze.AnalyzeResult{
+				{IsFail: true},
+				{IsError: true},
+				{IsWarn: true},
+			},
+			expectedStatus:   StatusError,
+			expectedExitCode: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, exitCode := ReduceResults(test.results)
+			assert.Equal(t, test.expectedStatus, status)
+			assert.Equal(t, test.expectedExitCode, exitCode)
+		})
+	}
+}
+
+func TestReduceResultsWithThreshold(t *testing.T) {
+	results := []*analyze.AnalyzeResult{
+		{IsPass: true},
+		{IsWarn: true},
+		{},
+	}
+
+	tests := []struct {
+		threshold        Status
+		expectedStatus   Status
+		expectedExitCode int
+	}{
+		{
+			threshold:        StatusFail,
+			expectedStatus:   StatusWarn,
+			expectedExitCode: 0,
+		},
+		{
+			threshold:        StatusWarn,
+			expectedStatus:   StatusFail,
+			expectedExitCode: 1,
+		},
+		{
+			threshold:        StatusPass,
+			expectedStatus:   StatusFail,
+			expectedExitCode: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.threshold), func(t *testing.T) {
+			status, exitCode := ReduceResultsWithThreshold(results, test.threshold)
+			assert.Equal(t, test.expectedStatus, status)
+			assert.Equal(t, test.expectedExitCode, exitCode)
+
+			// the original severities are kept for display
+			assert.True(t, results[0].IsPass)
+			assert.True(t, results[1].IsWarn)
+			assert.False(t, results[1].IsFail)
+		})
+	}
+}
+
+func TestApplySeverityThreshold(t *testing.T) {
+	results := []*analyze.AnalyzeResult{
+		{Title: "pass", IsPass: true},
+		{Title: "warn", IsWarn: true},
+		{Title: "error", IsError: true},
+		{Title: "skipped", IsWarn: true, IsSkipped: true},
+		{Title: "no outcome"},
+	}
+
+	elevated := ApplySeverityThreshold(results, StatusWarn)
+	assert.Equal(t, []*analyze.AnalyzeResult{
+		{Title: "pass", IsPass: true},
+		{Title: "warn", IsFail: true},
+		{Title: "error", IsError: true},
+		{Title: "skipped", IsWarn: true, IsSkipped: true},
+		{Title: "no outcome"},
+	}, elevated)
+	assert.True(t, results[1].IsWarn)
+}
+
+func TestParseSeverityThreshold(t *testing.T) {
+	for threshold, expected := range map[string]Status{"": StatusFail, "pass": StatusPass, "warn": StatusWarn, "fail": StatusFail} {
+		actual, err := ParseSeverityThreshold(threshold)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+
+	_, err := ParseSeverityThreshold("error")
+	assert.Error(t, err)
+}
@@ -1,9 +1,10 @@
 package preflight
 
 type UploadPreflightResult struct {
-	IsFail bool `json:"isFail,omitempty"`
-	IsWarn bool `json:"isWarn,omitempty"`
-	IsPass bool `json:"isPass,omitempty"`
+	IsFail  bool `json:"isFail,omitempty"`
+	IsWarn  bool `json:"isWarn,omitempty"`
+	IsPass  bool `json:"isPass,omitempty"`
+	IsError bool `json:"isError,omitempty"`
 
 	Title   string `json:"title"`
 	Message string `json:"message"`
@@ -0,0 +1,125 @@
+package redact
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// A block that has not matched after this many lines or bytes, such as a start without an end in a truncated log, is
+// written out as it is, so that it is not held in memory or matched again for the rest of the input. Later redactors
+// still see the lines. The limits are well above the size of a PEM encoded 16384 bit RSA key.
+const (
+	maxBlockLines = 200
+	maxBlockBytes = 32 * 1024
+)
+
+// BlockRedactor matches its regex against text spanning many lines, such as a PEM block, with . matching newlines.
+// Without a start regex the whole input is read before it is redacted. With one, lines are passed through as they
+// are read until a line matches start, and only the lines from there until the regex matches, up to maxBlockLines
+// and maxBlockBytes, are held in memory.
+type BlockRedactor struct {
+	start      *regexp.Regexp
+	re         *regexp.Regexp
+	maskText   string
+	filePath   string
+	redactName string
+	isDefault  bool
+	recorder   *RedactionRecorder
+}
+
+func NewBlockRedactor(start, re, maskText, path, name string, isDefault bool) (*BlockRedactor, error) {
+	var compiledStart *regexp.Regexp
+	if start != "" {
+		var err error
+		compiledStart, err = regexp.Compile(start)
+		if err != nil {
+			return nil, err
+		}
+	}
+	compiled, err := regexp.Compile("(?s)" + re)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockRedactor{start: compiledStart, re: compiled, maskText: maskText, filePath: path, redactName: name, isDefault: isDefault}, nil
+}
+
+func (r *BlockRedactor) Redact(input io.Reader) io.Reader {
+	out, writer := io.Pipe()
+
+	go func() {
+		var err error
+		defer func() {
+			if err == io.EOF {
+				writer.Close()
+			} else {
+				writer.CloseWithError(err)
+			}
+		}()
+
+		reader := bufio.NewReader(input)
+		lineNum := 0
+		blockLine := 0
+		blockBytes := 0
+		block := []string{}
+		for {
+			lineNum++
+			var line string
+			line, err = readLine(reader)
+			if err != nil {
+				if err == io.EOF && len(block) > 0 {
+					r.writeBlock(writer, block, blockLine)
+				}
+				return
+			}
+
+			if len(block) == 0 && r.start != nil && !r.start.MatchString(line) {
+				fmt.Fprintf(writer, "%s\n", line)
+				continue
+			}
+
+			if len(block) == 0 {
+				blockLine = lineNum
+				blockBytes = 0
+			}
+			block = append(block, line)
+			blockBytes += len(line) + 1
+			if r.start == nil {
+				continue
+			}
+			if r.re.MatchString(strings.Join(block, "\n")) {
+				r.writeBlock(writer, block, blockLine)
+				block = []string{}
+			} else if len(block) >= maxBlockLines || blockBytes >= maxBlockBytes {
+				for _, buffered := range block {
+					fmt.Fprintf(writer, "%s\n", buffered)
+				}
+				block = []string{}
+			}
+		}
+	}()
+	return out
+}
+
+// writeBlock redacts the lines of a block together and writes them out. blockLine is the line the block starts on,
+// used to report the redaction.
+func (r *BlockRedactor) writeBlock(writer io.Writer, block []string, blockLine int) {
+	text := strings.Join(block, "\n")
+	clean := redactLine(r.re, text, r.maskText)
+
+	// io.WriteString would be nicer, but the reader strips new lines
+	fmt.Fprintf(writer, "%s\n", clean)
+
+	// if clean is not equal to text, a redaction was performed
+	if clean != text {
+		addRedaction(r.recorder, Redaction{
+			RedactorName:      r.redactName,
+			CharactersRemoved: len(text) - len(clean),
+			Line:              blockLine,
+			File:              r.filePath,
+			IsDefaultRedactor: r.isDefault,
+		})
+	}
+}
@@ -4,21 +4,22 @@ import (
 	"bufio"
 	"fmt"
 	"io"
-	"strings"
+	"regexp"
 )
 
 type literalRedactor struct {
-	matchString string
-	filePath    string
-	redactName  string
-	isDefault   bool
+	re         *regexp.Regexp
+	filePath   string
+	redactName string
+	isDefault  bool
+	recorder   *RedactionRecorder
 }
 
 func literalString(matchString, path, name string) Redactor {
 	return literalRedactor{
-		matchString: matchString,
-		filePath:    path,
-		redactName:  name,
+		re:         regexp.MustCompile("(?P<mask>" + regexp.QuoteMeta(matchString) + ")"),
+		filePath:   path,
+		redactName: name,
 	}
 }
 
@@ -45,7 +46,7 @@ func (r literalRedactor) Redact(input io.Reader) io.Reader {
 				return
 			}
 
-			clean := strings.ReplaceAll(line, r.matchString, MASK_TEXT)
+			clean := redactLine(r.re, line, MASK_TEXT)
 
 			// io.WriteString would be nicer, but scanner strips new lines
 			fmt.Fprintf(writer, "%s\n", clean)
@@ -54,7 +55,7 @@ func (r literalRedactor) Redact(input io.Reader) io.Reader {
 			}
 
 			if clean != line {
-				addRedaction(Redaction{
+				addRedaction(r.recorder, Redaction{
 					RedactorName:      r.redactName,
 					CharactersRemoved: len(line) - len(clean),
 					Line:              lineNum,
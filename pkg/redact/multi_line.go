@@ -14,6 +14,7 @@ type MultiLineRedactor struct {
 	filePath   string
 	redactName string
 	isDefault  bool
+	recorder   *RedactionRecorder
 }
 
 func NewMultiLineRedactor(re1, re2, maskText, path, name string, isDefault bool) (*MultiLineRedactor, error) {
@@ -36,14 +37,15 @@ func (r *MultiLineRedactor) Redact(input io.Reader) io.Reader {
 			writer.CloseWithError(err)
 		}()
 
-		substStr := getReplacementPattern(r.re2, r.maskText)
-
 		reader := bufio.NewReader(input)
-		line1, line2, err := getNextTwoLines(reader, nil)
+		line1, err := readLine(reader)
+		if err != nil {
+			return
+		}
+		line2, err := readLine(reader)
 		if err != nil {
-			// this will print 2 blank lines for empty input...
+			// a single line has no following line to redact
 			fmt.Fprintf(writer, "%s\n", line1)
-			fmt.Fprintf(writer, "%s\n", line2)
 			return
 		}
 
@@ -61,7 +63,7 @@ func (r *MultiLineRedactor) Redact(input io.Reader) io.Reader {
 			}
 			flushLastLine = false
 
-			clean := r.re2.ReplaceAllString(line2, substStr)
+			clean := redactLine(r.re2, line2, r.maskText)
 
 			// io.WriteString would be nicer, but reader strips new lines
 			fmt.Fprintf(writer, "%s\n%s\n", line1, clean)
@@ -71,7 +73,7 @@ func (r *MultiLineRedactor) Redact(input io.Reader) io.Reader {
 
 			// if clean is not equal to line2, a redaction was performed
 			if clean != line2 {
-				addRedaction(Redaction{
+				addRedaction(r.recorder, Redaction{
 					RedactorName:      r.redactName,
 					CharactersRemoved: len(line2) - len(clean),
 					Line:              lineNum,
@@ -5,9 +5,12 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"io/ioutil"
+	"net/http"
 	"regexp"
+	"sort"
+	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/gobwas/glob"
 	"github.com/pkg/errors"
@@ -29,6 +32,8 @@ func init() {
 	}
 }
 
+// Redactor redacts its input as it is read. Redacting output that has already been redacted must not change it any
+// further, so that redacted files can safely be passed through the redactors again.
 type Redactor interface {
 	Redact(input io.Reader) io.Reader
 }
@@ -47,29 +52,162 @@ type Redaction struct {
 	IsDefaultRedactor bool   `json:"isDefaultRedactor" yaml:"isDefaultRedactor"`
 }
 
+// RedactionSummary counts the redactions made in each file. It never includes the redacted values.
+type RedactionSummary struct {
+	Files map[string]FileRedactionSummary `json:"files" yaml:"files"`
+}
+
+type FileRedactionSummary struct {
+	Count     int      `json:"count" yaml:"count"`
+	Redactors []string `json:"redactors" yaml:"redactors"`
+}
+
 func Redact(input []byte, path string, additionalRedactors []*troubleshootv1beta2.Redact) ([]byte, error) {
-	redactors, err := getRedactors(path)
+	redacted := new(bytes.Buffer)
+	err := RedactStream(bytes.NewReader(input), redacted, path, additionalRedactors)
 	if err != nil {
 		return nil, err
 	}
 
+	return redacted.Bytes(), nil
+}
+
+// Options change which of the default redactors run. With DisableDefaultRedactors set, or with DefaultRedactorNames
+// naming only some of them, anything the remaining redactors do not match is written out as is. That includes
+// passwords, tokens, cloud credentials, IP addresses and connection strings, so only disable defaults for inputs
+// that are known not to contain them. DisableCloudCredentialRedactors only turns off the AWS, GCP and Azure
+// credential redactors, for users who already redact these with their own redactors. Recorder, if set, also records
+// the redactions made, so that they can be summarized for this call alone.
+type Options struct {
+	DisableDefaultRedactors         bool
+	DefaultRedactorNames            []string
+	DisableCloudCredentialRedactors bool
+	Recorder                        *RedactionRecorder
+}
+
+// RedactionRecorder collects the redactions made by the calls whose Options set it, unlike the process-wide list
+// returned by GetRedactionList. Calls that run at the same time can share a recorder.
+type RedactionRecorder struct {
+	mut        sync.Mutex
+	redactions RedactionList
+}
+
+func NewRedactionRecorder() *RedactionRecorder {
+	return &RedactionRecorder{
+		redactions: RedactionList{
+			ByRedactor: map[string][]Redaction{},
+			ByFile:     map[string][]Redaction{},
+		},
+	}
+}
+
+func (r *RedactionRecorder) add(redaction Redaction) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	r.redactions.ByRedactor[redaction.RedactorName] = append(r.redactions.ByRedactor[redaction.RedactorName], redaction)
+	r.redactions.ByFile[redaction.File] = append(r.redactions.ByFile[redaction.File], redaction)
+}
+
+// Summary summarizes the redactions recorded so far
+func (r *RedactionRecorder) Summary() RedactionSummary {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	return r.redactions.Summary()
+}
+
+// RedactStream redacts input line by line into output, so the file never has to be held in memory as a whole.
+// Binary files are copied through untouched unless a redactor matching the path sets ScanBinary.
+func RedactStream(input io.Reader, output io.Writer, path string, additionalRedactors []*troubleshootv1beta2.Redact) error {
+	return RedactStreamWithOptions(input, output, path, additionalRedactors, Options{})
+}
+
+func RedactStreamWithOptions(input io.Reader, output io.Writer, path string, additionalRedactors []*troubleshootv1beta2.Redact, options Options) error {
+	bufferedInput := bufio.NewReader(input)
+	isBinary, err := isBinaryContent(bufferedInput)
+	if err != nil {
+		return errors.Wrap(err, "detect content type")
+	}
+	if isBinary {
+		scanBinary, err := scansBinary(path, additionalRedactors)
+		if err != nil {
+			return err
+		}
+		if !scanBinary {
+			_, err = io.Copy(output, bufferedInput)
+			return err
+		}
+	}
+
+	redactors := []Redactor{}
+	if !options.DisableDefaultRedactors {
+		redactors, err = getDefaultRedactors(path, options)
+		if err != nil {
+			return err
+		}
+	}
+
 	builtRedactors, err := buildAdditionalRedactors(path, additionalRedactors)
 	if err != nil {
-		return nil, errors.Wrap(err, "build custom redactors")
+		return errors.Wrap(err, "build custom redactors")
 	}
 	redactors = append(redactors, builtRedactors...)
+	if options.Recorder != nil {
+		setRecorder(redactors, options.Recorder)
+	}
 
-	nextReader := io.Reader(bytes.NewReader(input))
+	nextReader := io.Reader(bufferedInput)
 	for _, r := range redactors {
 		nextReader = r.Redact(nextReader)
 	}
 
-	redacted, err := ioutil.ReadAll(nextReader)
+	_, err = io.Copy(output, nextReader)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return redacted, nil
+	return nil
+}
+
+// isBinaryContent sniffs the first bytes of input without consuming them. Text can start with the signature of a
+// binary format, such as "BM" or "%PDF-", so content that http.DetectContentType does not report as text is only
+// treated as binary when it also has NUL bytes or is not valid UTF-8. Everything else is redacted.
+func isBinaryContent(input *bufio.Reader) (bool, error) {
+	peeked, err := input.Peek(512)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	if strings.HasPrefix(http.DetectContentType(peeked), "text/") {
+		return false, nil
+	}
+	return bytes.IndexByte(peeked, 0) != -1 || !isUTF8Prefix(peeked), nil
+}
+
+// isUTF8Prefix reports whether b is valid UTF-8, allowing the last character to be cut off where the sniffed bytes end
+func isUTF8Prefix(b []byte) bool {
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		if r == utf8.RuneError && size == 1 {
+			return !utf8.FullRune(b)
+		}
+		b = b[size:]
+	}
+	return true
+}
+
+func scansBinary(path string, redacts []*troubleshootv1beta2.Redact) (bool, error) {
+	for _, redact := range redacts {
+		if redact == nil || !redact.ScanBinary {
+			continue
+		}
+		matches, err := redactMatchesPath(path, redact)
+		if err != nil {
+			return false, err
+		}
+		if matches {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 func GetRedactionList() RedactionList {
@@ -79,6 +217,49 @@ func GetRedactionList() RedactionList {
 	return allRedactions
 }
 
+func (l RedactionList) Summary() RedactionSummary {
+	summary := RedactionSummary{
+		Files: map[string]FileRedactionSummary{},
+	}
+	for file, redactions := range l.ByFile {
+		fileSummary := FileRedactionSummary{
+			Count: len(redactions),
+		}
+		seen := map[string]bool{}
+		for _, redaction := range redactions {
+			if seen[redaction.RedactorName] {
+				continue
+			}
+			seen[redaction.RedactorName] = true
+			fileSummary.Redactors = append(fileSummary.Redactors, redaction.RedactorName)
+		}
+		sort.Strings(fileSummary.Redactors)
+		summary.Files[file] = fileSummary
+	}
+	return summary
+}
+
+// Count is the total number of redactions across all files
+func (s RedactionSummary) Count() int {
+	count := 0
+	for _, file := range s.Files {
+		count += file.Count
+	}
+	return count
+}
+
+func (s RedactionSummary) String() string {
+	matches := "matches"
+	if s.Count() == 1 {
+		matches = "match"
+	}
+	files := "files"
+	if len(s.Files) == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("redacted %d %s across %d %s", s.Count(), matches, len(s.Files), files)
+}
+
 func ResetRedactionList() {
 	redactionListMut.Lock()
 	defer redactionListMut.Unlock()
@@ -110,7 +291,12 @@ func buildAdditionalRedactors(path string, redacts []*troubleshootv1beta2.Redact
 
 		for j, re := range redact.Removals.Regex {
 			var newRedactor Redactor
-			if re.Selector != "" {
+			if re.Block {
+				newRedactor, err = NewBlockRedactor(re.Selector, re.Redactor, MASK_TEXT, path, redactorName(i, j, redact.Name, "block"), false)
+				if err != nil {
+					return nil, errors.Wrapf(err, "block redactor %+v", re)
+				}
+			} else if re.Selector != "" {
 				newRedactor, err = NewMultiLineRedactor(re.Selector, re.Redactor, MASK_TEXT, path, redactorName(i, j, redact.Name, "multiLine"), false)
 				if err != nil {
 					return nil, errors.Wrapf(err, "multiline redactor %+v", re)
@@ -118,7 +304,7 @@ func buildAdditionalRedactors(path string, redacts []*troubleshootv1beta2.Redact
 			} else {
 				newRedactor, err = NewSingleLineRedactor(re.Redactor, MASK_TEXT, path, redactorName(i, j, redact.Name, "regex"), false)
 				if err != nil {
-					return nil, errors.Wrapf(err, "redactor %q", re)
+					return nil, errors.Wrapf(err, "redactor %+v", re)
 				}
 			}
 			additionalRedactors = append(additionalRedactors, newRedactor)
@@ -140,19 +326,19 @@ func redactMatchesPath(path string, redact *troubleshootv1beta2.Redact) (bool, e
 	globs := []glob.Glob{}
 
 	if redact.FileSelector.File != "" {
-		newGlob, err := glob.Compile(redact.FileSelector.File, '/')
+		newGlobs, err := compileFileGlob(redact.FileSelector.File)
 		if err != nil {
 			return false, errors.Wrapf(err, "invalid file glob string %q", redact.FileSelector.File)
 		}
-		globs = append(globs, newGlob)
+		globs = append(globs, newGlobs...)
 	}
 
 	for i, fileGlobString := range redact.FileSelector.Files {
-		newGlob, err := glob.Compile(fileGlobString, '/')
+		newGlobs, err := compileFileGlob(fileGlobString)
 		if err != nil {
 			return false, errors.Wrapf(err, "invalid file glob string %d %q", i, fileGlobString)
 		}
-		globs = append(globs, newGlob)
+		globs = append(globs, newGlobs...)
 	}
 
 	for _, thisGlob := range globs {
@@ -164,8 +350,35 @@ func redactMatchesPath(path string, redact *troubleshootv1beta2.Redact) (bool, e
 	return false, nil
 }
 
+// compileFileGlob compiles a glob where `*` stays within a directory and `**` spans directories. A leading `**/` also
+// matches files with no parent directory, so `**/secrets/*.yaml` matches `secrets/db.yaml`.
+func compileFileGlob(pattern string) ([]glob.Glob, error) {
+	patterns := []string{pattern}
+	if strings.HasPrefix(pattern, "**/") {
+		patterns = append(patterns, strings.TrimPrefix(pattern, "**/"))
+	}
+
+	globs := []glob.Glob{}
+	for _, p := range patterns {
+		newGlob, err := glob.Compile(p, '/')
+		if err != nil {
+			return nil, err
+		}
+		globs = append(globs, newGlob)
+	}
+	return globs, nil
+}
+
 func getRedactors(path string) ([]Redactor, error) {
-	// TODO: Make this configurable
+	return getDefaultRedactors(path, Options{})
+}
+
+// getDefaultRedactors builds the default redactors, keeping only those named in DefaultRedactorNames if any are given
+func getDefaultRedactors(path string, options Options) ([]Redactor, error) {
+	selected := map[string]bool{}
+	for _, name := range options.DefaultRedactorNames {
+		selected[name] = true
+	}
 
 	// (?i) makes it case insensitive
 	// groups named with `?P<mask>` will be masked
@@ -257,8 +470,15 @@ func getRedactors(path string) ([]Redactor, error) {
 		},
 	}
 
+	if !options.DisableCloudCredentialRedactors {
+		singleLines = append(singleLines, cloudCredentialRedactors...)
+	}
+
 	redactors := make([]Redactor, 0)
 	for _, re := range singleLines {
+		if len(selected) > 0 && !selected[re.name] {
+			continue
+		}
 		r, err := NewSingleLineRedactor(re.regex, MASK_TEXT, path, re.name, true)
 		if err != nil {
 			return nil, err // maybe skip broken ones?
@@ -309,6 +529,9 @@ func getRedactors(path string) ([]Redactor, error) {
 	}
 
 	for _, l := range doubleLines {
+		if len(selected) > 0 && !selected[l.name] {
+			continue
+		}
 		r, err := NewMultiLineRedactor(l.line1, l.line2, MASK_TEXT, path, l.name, true)
 		if err != nil {
 			return nil, err // maybe skip broken ones?
@@ -316,26 +539,138 @@ func getRedactors(path string) ([]Redactor, error) {
 		redactors = append(redactors, r)
 	}
 
+	// blocks only hold the lines from one matching start to the end of the match in memory
+	blocks := []struct {
+		start string
+		regex string
+		name  string
+	}{
+		{
+			start: `-----BEGIN [A-Z ]*PRIVATE KEY-----`,
+			regex: `(-----BEGIN [A-Z ]*PRIVATE KEY-----\n?)(?P<mask>.+?)(\n?-----END [A-Z ]*PRIVATE KEY-----)`,
+			name:  "Redact private keys in PEM blocks",
+		},
+	}
+
+	for _, b := range blocks {
+		if len(selected) > 0 && !selected[b.name] {
+			continue
+		}
+		r, err := NewBlockRedactor(b.start, b.regex, MASK_TEXT, path, b.name, true)
+		if err != nil {
+			return nil, err
+		}
+		redactors = append(redactors, r)
+	}
+
 	return redactors, nil
 }
 
-func getReplacementPattern(re *regexp.Regexp, maskText string) string {
-	substStr := ""
-	for i, name := range re.SubexpNames() {
-		if i == 0 { // index 0 is the entire string
-			continue
+var cloudCredentialRedactors = []struct {
+	regex string
+	name  string
+}{
+	{
+		regex: `(?P<mask>\b(?:AKIA|ASIA)[0-9A-Z]{16}\b)`,
+		name:  "Redact AWS access key IDs",
+	},
+	{
+		regex: `(?i)(aws_?secret_?access_?key["']?\s*[:=]\s*["']?)(?P<mask>[A-Za-z0-9/+=]{40})`,
+		name:  "Redact AWS secret access keys",
+	},
+	// GCP service account JSON keeps the whole PEM block on one line with escaped newlines
+	{
+		regex: `(-----BEGIN [A-Z ]*PRIVATE KEY-----)(?P<mask>.+?)(-----END [A-Z ]*PRIVATE KEY-----)`,
+		name:  "Redact private keys such as those in GCP service account JSON",
+	},
+	{
+		regex: `(?i)("private_key_id" *: *")(?P<mask>[0-9a-f]+)(")`,
+		name:  "Redact GCP service account private key IDs",
+	},
+	{
+		regex: `(?i)(AccountKey *= *)(?P<mask>[^;"\s]+)`,
+		name:  "Redact 'AccountKey' values in Azure storage connection strings",
+	},
+	{
+		regex: `(?i)(SharedAccessKey *= *)(?P<mask>[^;"\s]+)`,
+		name:  "Redact 'SharedAccessKey' values in Azure connection strings",
+	},
+}
+
+// redactLine replaces every match of re in line. Groups named "mask" are replaced with maskText, groups named "drop"
+// are removed and other groups are kept, as is the text between matches. A mask group that only covers text that
+// was already masked is kept as is, so that redacting a line a second time does not change it.
+func redactLine(re *regexp.Regexp, line, maskText string) string {
+	matches := re.FindAllStringSubmatchIndex(line, -1)
+	if len(matches) == 0 {
+		return line
+	}
+
+	masked := maskedRanges(line, maskText)
+	names := re.SubexpNames()
+
+	var clean strings.Builder
+	last := 0
+	for _, match := range matches {
+		clean.WriteString(line[last:match[0]])
+		for i, name := range names {
+			if i == 0 { // index 0 is the entire string
+				continue
+			}
+			start, end := match[2*i], match[2*i+1]
+			switch name {
+			case "mask":
+				if start < end && isMasked(masked, start, end) {
+					clean.WriteString(line[start:end])
+				} else {
+					clean.WriteString(maskText)
+				}
+			case "drop":
+				// no-op, string is just dropped from result
+			default:
+				if start >= 0 {
+					clean.WriteString(line[start:end])
+				}
+			}
+		}
+		last = match[1]
+	}
+	clean.WriteString(line[last:])
+
+	return clean.String()
+}
+
+// maskedRanges returns the start and end of each occurrence of maskText in line
+func maskedRanges(line, maskText string) [][2]int {
+	ranges := [][2]int{}
+	if maskText == "" {
+		return ranges
+	}
+	for offset := 0; ; {
+		i := strings.Index(line[offset:], maskText)
+		if i < 0 {
+			return ranges
+		}
+		start := offset + i
+		ranges = append(ranges, [2]int{start, start + len(maskText)})
+		offset = start + 1
+	}
+}
+
+// isMasked reports whether every character from start to end is part of an occurrence of the mask text
+func isMasked(masked [][2]int, start, end int) bool {
+	for _, r := range masked {
+		if r[0] > start {
+			return false
+		}
+		if r[1] > start {
+			start = r[1]
 		}
-		if name == "" {
-			substStr = fmt.Sprintf("%s$%d", substStr, i)
-		} else if name == "mask" {
-			substStr = fmt.Sprintf("%s%s", substStr, maskText)
-		} else if name == "drop" {
-			// no-op, string is just dropped from result
-		} else {
-			substStr = fmt.Sprintf("%s${%s}", substStr, name)
+		if start >= end {
+			return true
 		}
 	}
-	return substStr
+	return false
 }
 
 func readLine(r *bufio.Reader) (string, error) {
@@ -355,7 +690,30 @@ func readLine(r *bufio.Reader) (string, error) {
 	return string(completeLine), nil
 }
 
-func addRedaction(redaction Redaction) {
+// setRecorder makes the redactors also record their redactions in recorder
+func setRecorder(redactors []Redactor, recorder *RedactionRecorder) {
+	for i, redactor := range redactors {
+		switch r := redactor.(type) {
+		case *SingleLineRedactor:
+			r.recorder = recorder
+		case *MultiLineRedactor:
+			r.recorder = recorder
+		case *BlockRedactor:
+			r.recorder = recorder
+		case *YamlRedactor:
+			r.recorder = recorder
+		case literalRedactor:
+			r.recorder = recorder
+			redactors[i] = r
+		}
+	}
+}
+
+func addRedaction(recorder *RedactionRecorder, redaction Redaction) {
+	if recorder != nil {
+		recorder.add(redaction)
+	}
+
 	pendingRedactions.Add(1)
 	go func(redaction Redaction) {
 		redactionListMut.Lock()
@@ -13,6 +13,7 @@ type SingleLineRedactor struct {
 	filePath   string
 	redactName string
 	isDefault  bool
+	recorder   *RedactionRecorder
 }
 
 func NewSingleLineRedactor(re, maskText, path, name string, isDefault bool) (*SingleLineRedactor, error) {
@@ -36,8 +37,6 @@ func (r *SingleLineRedactor) Redact(input io.Reader) io.Reader {
 			}
 		}()
 
-		substStr := getReplacementPattern(r.re, r.maskText)
-
 		reader := bufio.NewReader(input)
 		lineNum := 0
 		for {
@@ -53,7 +52,7 @@ func (r *SingleLineRedactor) Redact(input io.Reader) io.Reader {
 				continue
 			}
 
-			clean := r.re.ReplaceAllString(line, substStr)
+			clean := redactLine(r.re, line, r.maskText)
 
 			// io.WriteString would be nicer, but scanner strips new lines
 			fmt.Fprintf(writer, "%s\n", clean)
@@ -63,7 +62,7 @@ func (r *SingleLineRedactor) Redact(input io.Reader) io.Reader {
 
 			// if clean is not equal to line, a redaction was performed
 			if clean != line {
-				addRedaction(Redaction{
+				addRedaction(r.recorder, Redaction{
 					RedactorName:      r.redactName,
 					CharactersRemoved: len(line) - len(clean),
 					Line:              lineNum,
@@ -17,6 +17,7 @@ type YamlRedactor struct {
 	filePath   string
 	redactName string
 	isDefault  bool
+	recorder   *RedactionRecorder
 }
 
 func NewYamlRedactor(yamlPath, filePath, name string) *YamlRedactor {
@@ -65,7 +66,7 @@ func (r *YamlRedactor) Redact(input io.Reader) io.Reader {
 		buf := bytes.NewBuffer(newBytes)
 		buf.WriteTo(writer)
 
-		addRedaction(Redaction{
+		addRedaction(r.recorder, Redaction{
 			RedactorName:      r.redactName,
 			CharactersRemoved: len(doc) - len(newBytes),
 			Line:              0, // line 0 because we have no way to tell what line was impacted
@@ -80,7 +81,10 @@ func (r *YamlRedactor) Redact(input io.Reader) io.Reader {
 
 func (r *YamlRedactor) redactYaml(in interface{}, path []string) interface{} {
 	if len(path) == 0 {
-		r.foundMatch = true
+		// values that are already masked are left alone, so that redacting twice records no new redactions
+		if in != MASK_TEXT {
+			r.foundMatch = true
+		}
 		return MASK_TEXT
 	}
 	switch typed := in.(type) {